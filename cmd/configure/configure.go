@@ -22,6 +22,7 @@ func NewCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			confPath = config2.Discover(confPath)
 			if conf, err = config2.New(confPath, false); err != nil {
 				return fmt.Errorf("error loading config: %w", err)
 			}
@@ -51,6 +52,6 @@ func NewCommand() *cobra.Command {
 			return conf.WriteFile(confPath)
 		},
 	}
-	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
 	return command
 }
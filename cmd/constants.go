@@ -1,11 +1,39 @@
 package cmd
 
 const (
-	CommandAliasRoot     = "imdb-trakt-sync"
-	CommandNameConfigure = "configure"
-	CommandNameRoot      = "its"
-	CommandNameSync      = "sync"
-	ConfigFileDefault    = "config.yaml"
-	FlagNameConfigFile   = "config-file"
-	FlagNameInteractive  = "interactive"
+	CommandAliasRoot      = "imdb-trakt-sync"
+	CommandNameConfigure  = "configure"
+	CommandNameDoctor     = "doctor"
+	CommandNameDuplicates = "duplicates"
+	CommandNameExport     = "export"
+	CommandNamePrune      = "prune"
+	CommandNameRestore    = "restore"
+	CommandNameRoot       = "its"
+	CommandNameSnapshot   = "snapshot"
+	CommandNameStats      = "stats"
+	CommandNameSync       = "sync"
+	CommandNameVersion    = "version"
+	FlagNameConfigFile    = "config"
+	FlagNameDiff          = "diff"
+	FlagNameDryRun        = "dry-run"
+	FlagNameForce         = "force"
+	FlagNameFrom          = "from"
+	FlagNameFull          = "full"
+	FlagNameInput         = "input"
+	FlagNameInteractive   = "interactive"
+	FlagNameList          = "list"
+	FlagNameLogLevel      = "log-level"
+	FlagNameMaxItems      = "max-items"
+	FlagNameOnly          = "only"
+	FlagNameOutput        = "output"
+	FlagNameProfile       = "profile"
+	FlagNameQuiet         = "quiet"
+	FlagNameSkip          = "skip"
+	FlagNameThreshold     = "threshold"
+	FlagNameTo            = "to"
+	FlagNameTrakt         = "trakt"
+	FlagNameVerbose       = "verbose"
+
+	FlagNameConfigFileDeprecated = "config-file"
+	FlagUsageConfigFile          = "path to the config file, when omitted the first existing of these is used: ITS_CONFIG environment variable, ./config.yaml, ~/.config/imdb-trakt-sync/config.yaml"
 )
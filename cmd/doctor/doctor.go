@@ -0,0 +1,190 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+var errChecksFailed = errors.New("one or more checks failed")
+
+type check struct {
+	name string
+	hint string
+	run  func(context.Context) error
+}
+
+func NewCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameDoctor),
+		Short: "Check the config and the connectivity to IMDb and Trakt without syncing anything",
+		RunE: func(c *cobra.Command, args []string) error {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			var conf *config.Config
+			configChecks := []check{
+				{
+					name: fmt.Sprintf("config is loaded from %s", confPath),
+					hint: fmt.Sprintf("make sure %s exists and is valid yaml, or run the %s command", confPath, cmd.CommandNameConfigure),
+					run: func(context.Context) (err error) {
+						conf, err = config.New(confPath, true)
+						return err
+					},
+				},
+				{
+					name: "config is valid",
+					hint: fmt.Sprintf("fix the reported config field, or run the %s command", cmd.CommandNameConfigure),
+					run: func(context.Context) error {
+						return conf.Validate()
+					},
+				},
+			}
+			if err = runChecks(c.Context(), c.OutOrStdout(), configChecks); err != nil {
+				return err
+			}
+			var checks [][]check
+			switch {
+			case profile != "":
+				profileConf, err := conf.Profile(profile)
+				if err != nil {
+					return err
+				}
+				checks = connectivityChecks(profileConf)
+			case len(conf.Profiles) != 0:
+				for _, name := range conf.ProfileNames() {
+					profileConf, err := conf.Profile(name)
+					if err != nil {
+						return err
+					}
+					checks = append(checks, connectivityChecks(profileConf)...)
+				}
+			default:
+				checks = connectivityChecks(conf)
+			}
+			return runChecks(c.Context(), c.OutOrStdout(), checks...)
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to check, all profiles are checked when omitted")
+	return command
+}
+
+func connectivityChecks(conf *config.Config) [][]check {
+	var (
+		prefix      string
+		imdbClient  client.IMDbClientInterface
+		traktClient client.TraktClientInterface
+	)
+	if name := conf.ProfileName(); name != "" {
+		prefix = fmt.Sprintf("profile %s: ", name)
+	}
+	log := logger.NewLogger(io.Discard)
+	imdbChecks := []check{
+		{
+			name: prefix + "imdb client is created",
+			hint: "check the IMDB_COOKIEATMAIN and IMDB_COOKIEUBIDMAIN config fields",
+			run: func(context.Context) (err error) {
+				if conf.IMDb.IsOffline() {
+					imdbClient, err = client.NewIMDbOfflineClient(conf.IMDb, conf.HTTP, log)
+					return err
+				}
+				imdbClient, err = client.NewIMDbClient(conf.IMDb, conf.HTTP, log)
+				return err
+			},
+		},
+		{
+			name: prefix + "imdb user is authenticated",
+			hint: "refresh the IMDB_COOKIEATMAIN and IMDB_COOKIEUBIDMAIN cookies, they have most likely expired",
+			run: func(ctx context.Context) error {
+				return imdbClient.Hydrate(ctx)
+			},
+		},
+	}
+	switch {
+	case conf.IMDb.IsOffline():
+		imdbChecks[1].hint = "make sure IMDB_EXPORTSDIR points to an existing directory"
+	case conf.IMDb.IsAuthless():
+		imdbChecks = append(imdbChecks, check{
+			name: prefix + "imdb lists are public",
+			hint: "make the IMDB_LISTS lists public or set IMDB_AUTH to cookies",
+			run: func(ctx context.Context) error {
+				_, errs := imdbClient.ListsGet(ctx, conf.IMDb.Lists)
+				return errors.Join(errs...)
+			},
+		})
+	default:
+		imdbChecks = append(imdbChecks, check{
+			name: prefix + "imdb watchlist is readable",
+			hint: "make sure the IMDb watchlist is not empty and the IMDb account is not restricted",
+			run: func(ctx context.Context) error {
+				_, err := imdbClient.WatchlistGet(ctx)
+				return err
+			},
+		})
+	}
+	traktChecks := []check{
+		{
+			name: prefix + "trakt client is created",
+			hint: "check the TRAKT_* config fields",
+			run: func(context.Context) (err error) {
+				traktClient, err = client.NewTraktClient(conf.Trakt, conf.HTTP, log)
+				return err
+			},
+		},
+		{
+			name: prefix + "trakt user is authenticated",
+			hint: "check the TRAKT_EMAIL, TRAKT_PASSWORD, TRAKT_CLIENTID and TRAKT_CLIENTSECRET config fields",
+			run: func(ctx context.Context) error {
+				return traktClient.Hydrate(ctx)
+			},
+		},
+		{
+			name: prefix + "trakt watchlist is readable",
+			hint: "make sure the Trakt API is reachable and the Trakt account is not locked",
+			run: func(ctx context.Context) error {
+				_, err := traktClient.WatchlistGet(ctx)
+				return err
+			},
+		},
+	}
+	return [][]check{imdbChecks, traktChecks}
+}
+
+// runChecks reports every check to w, a failing check skips the remaining checks of its group since they depend on it
+func runChecks(ctx context.Context, w io.Writer, groups ...[]check) error {
+	failed := false
+	for _, group := range groups {
+		skip := false
+		for _, c := range group {
+			if skip {
+				fmt.Fprintf(w, "[SKIP] %s\n", c.name)
+				continue
+			}
+			if err := c.run(ctx); err != nil {
+				failed, skip = true, true
+				fmt.Fprintf(w, "[FAIL] %s: %v\n       hint: %s\n", c.name, err, c.hint)
+				continue
+			}
+			fmt.Fprintf(w, "[PASS] %s\n", c.name)
+		}
+	}
+	if failed {
+		return errChecksFailed
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runChecks(t *testing.T) {
+	type args struct {
+		groups [][]check
+	}
+	pass := func(context.Context) error {
+		return nil
+	}
+	fail := func(context.Context) error {
+		return errors.New("boom")
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "all checks pass",
+			args: args{
+				groups: [][]check{
+					{
+						{name: "first", run: pass},
+						{name: "second", run: pass},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("[PASS] first\n[PASS] second\n", output)
+			},
+		},
+		{
+			name: "failing check skips the rest of its group only",
+			args: args{
+				groups: [][]check{
+					{
+						{name: "first", hint: "do something", run: fail},
+						{name: "second", run: pass},
+					},
+					{
+						{name: "third", run: pass},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.ErrorIs(err, errChecksFailed)
+				assertions.Equal("[FAIL] first: boom\n       hint: do something\n[SKIP] second\n[PASS] third\n", output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := runChecks(context.Background(), &output, tt.args.groups...)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
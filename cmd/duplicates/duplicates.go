@@ -0,0 +1,98 @@
+package duplicates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+const thresholdDefault = 0.9
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameDuplicates),
+		Short: "Report IMDb items that are probably duplicates of each other, e.g. regional versions, without changing anything",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			threshold, err := c.Flags().GetFloat64(cmd.FlagNameThreshold)
+			if err != nil {
+				return err
+			}
+			if threshold <= 0 || threshold > 1 {
+				return fmt.Errorf("flag --%s must be greater than 0 and at most 1", cmd.FlagNameThreshold)
+			}
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+			}
+			threshold, err := c.Flags().GetFloat64(cmd.FlagNameThreshold)
+			if err != nil {
+				return err
+			}
+			return duplicates(c.Context(), c.OutOrStdout(), conf, threshold)
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to check, the default config is checked when omitted")
+	command.Flags().Float64(cmd.FlagNameThreshold, thresholdDefault, "minimum title similarity of probable duplicates, from 0 to 1, where 1 only matches titles equal ignoring case and punctuation")
+	return command
+}
+
+func duplicates(ctx context.Context, w io.Writer, conf *config.Config, threshold float64) error {
+	// dry-run mode and error logs keep the report read-only and the output limited to the table
+	mode, level := config.SyncModeDryRun, config.LogLevelError
+	conf.Sync.Mode = &mode
+	conf.Sync.Modes = config.Modes{}
+	conf.Log.Level = &level
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	pairs, err := s.Duplicates(ctx, threshold)
+	if err != nil {
+		return fmt.Errorf("error detecting duplicates: %w", err)
+	}
+	return writeDuplicates(w, pairs)
+}
+
+func writeDuplicates(w io.Writer, pairs []syncer.DuplicatePair) error {
+	if len(pairs) == 0 {
+		_, err := fmt.Fprintln(w, "no probable duplicates found")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SIMILARITY\tFIRST\tFOUND IN\tSECOND\tFOUND IN")
+	for _, pair := range pairs {
+		fmt.Fprintf(tw, "%.2f\t%s\t%s\t%s\t%s\n", pair.Similarity, itemLabel(pair.First), strings.Join(pair.First.Locations, ", "), itemLabel(pair.Second), strings.Join(pair.Second.Locations, ", "))
+	}
+	return tw.Flush()
+}
+
+func itemLabel(item syncer.DuplicateItem) string {
+	traktItem := item.Item.ToTraktItem()
+	return traktItem.Label()
+}
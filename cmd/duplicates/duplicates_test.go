@@ -0,0 +1,61 @@
+package duplicates
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writeDuplicates(t *testing.T) {
+	type args struct {
+		pairs []syncer.DuplicatePair
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "write a message when there are no duplicates",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("no probable duplicates found\n", output)
+			},
+		},
+		{
+			name: "write aligned pairs",
+			args: args{
+				pairs: []syncer.DuplicatePair{
+					{
+						First: syncer.DuplicateItem{
+							Item:      entities.IMDbItem{ID: "tt0386676", Title: "The Office", Year: 2005, TitleType: "tvSeries"},
+							Locations: []string{"ratings", "watchlist"},
+						},
+						Second: syncer.DuplicateItem{
+							Item:      entities.IMDbItem{ID: "tt0290978", Title: "The Office", Year: 2001, TitleType: "tvSeries"},
+							Locations: []string{"list Comedy"},
+						},
+						Similarity: 1,
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "SIMILARITY  FIRST                          FOUND IN            SECOND                         FOUND IN\n" +
+					"1.00        The Office (2005) [tt0386676]  ratings, watchlist  The Office (2001) [tt0290978]  list Comedy\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writeDuplicates(&output, tt.args.pairs)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
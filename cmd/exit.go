@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+const (
+	ExitCodeSuccess        = 0
+	ExitCodeFailure        = 1
+	ExitCodePartialFailure = 2
+)
+
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	var partialSyncError *syncer.PartialSyncError
+	if errors.As(err, &partialSyncError) {
+		return ExitCodePartialFailure
+	}
+	return ExitCodeFailure
+}
+
+// ApplyPartialFailurePolicy reports a partial sync failure as configured by SYNC_PARTIALFAILURE, which ExitCode maps to the exit code
+// the errors are logged by the syncer already, hence they're dropped when partial failures are reported as successes
+func ApplyPartialFailurePolicy(policy string, err error) error {
+	var partialSyncError *syncer.PartialSyncError
+	if !errors.As(err, &partialSyncError) {
+		return err
+	}
+	switch policy {
+	case config.PartialFailureFailure:
+		return partialSyncError.Err
+	case config.PartialFailureSuccess:
+		return nil
+	default:
+		return err
+	}
+}
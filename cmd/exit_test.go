@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func TestExitCode(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, int)
+	}{
+		{
+			name: "all ok",
+			args: args{
+				err: nil,
+			},
+			assertions: func(assertions *assert.Assertions, code int) {
+				assertions.Equal(ExitCodeSuccess, code)
+			},
+		},
+		{
+			name: "partial failure",
+			args: args{
+				err: &syncer.PartialSyncError{
+					Err: errors.New("failure syncing ratings"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, code int) {
+				assertions.Equal(ExitCodePartialFailure, code)
+			},
+		},
+		{
+			name: "wrapped partial failure",
+			args: args{
+				err: fmt.Errorf("wrapped: %w", &syncer.PartialSyncError{
+					Err: errors.New("failure syncing lists"),
+				}),
+			},
+			assertions: func(assertions *assert.Assertions, code int) {
+				assertions.Equal(ExitCodePartialFailure, code)
+			},
+		},
+		{
+			name: "fatal failure",
+			args: args{
+				err: errors.New("failure hydrating imdb client"),
+			},
+			assertions: func(assertions *assert.Assertions, code int) {
+				assertions.Equal(ExitCodeFailure, code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), ExitCode(tt.args.err))
+		})
+	}
+}
+
+func TestApplyPartialFailurePolicy(t *testing.T) {
+	type args struct {
+		policy string
+		err    error
+	}
+	partialSyncError := &syncer.PartialSyncError{
+		Err: errors.New("failure syncing ratings"),
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "partial failure reported as partial",
+			args: args{
+				policy: config.PartialFailurePartial,
+				err:    partialSyncError,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Equal(ExitCodePartialFailure, ExitCode(err))
+			},
+		},
+		{
+			name: "partial failure reported as failure",
+			args: args{
+				policy: config.PartialFailureFailure,
+				err:    partialSyncError,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "failure syncing ratings")
+				assertions.Equal(ExitCodeFailure, ExitCode(err))
+			},
+		},
+		{
+			name: "partial failure reported as success",
+			args: args{
+				policy: config.PartialFailureSuccess,
+				err:    partialSyncError,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Equal(ExitCodeSuccess, ExitCode(err))
+			},
+		},
+		{
+			name: "fatal failure left as is",
+			args: args{
+				policy: config.PartialFailureSuccess,
+				err:    errors.New("failure hydrating imdb client"),
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Equal(ExitCodeFailure, ExitCode(err))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), ApplyPartialFailurePolicy(tt.args.policy, tt.args.err))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+// letterboxdDateLayout is the watched date format of the letterboxd import, see https://letterboxd.com/about/importing-data/
+const letterboxdDateLayout = "2006-01-02"
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameExport),
+		Short: "Export the rated items as a CSV file that can be imported to Letterboxd, without syncing anything",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+			}
+			output, err := c.Flags().GetString(cmd.FlagNameOutput)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				return export(c.Context(), c.OutOrStdout(), conf)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failure creating export file %s: %w", output, err)
+			}
+			if err = export(c.Context(), file, conf); err != nil {
+				file.Close()
+				return err
+			}
+			return file.Close()
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to export, the default config is exported when omitted")
+	command.Flags().String(cmd.FlagNameOutput, "", "path to the CSV file to write, the CSV is written to stdout when omitted")
+	return command
+}
+
+func export(ctx context.Context, w io.Writer, conf *config.Config) error {
+	// dry-run mode and error logs keep the export read-only and stdout limited to the csv
+	mode, level := config.SyncModeDryRun, config.LogLevelError
+	conf.Sync.Mode = &mode
+	conf.Sync.Modes = config.Modes{}
+	conf.Log.Level = &level
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	rows, err := s.Export(ctx)
+	if err != nil {
+		return fmt.Errorf("error exporting rated items: %w", err)
+	}
+	return writeLetterboxdCSV(w, rows)
+}
+
+func writeLetterboxdCSV(w io.Writer, rows []syncer.ExportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"imdbID", "Title", "Year", "Rating10", "WatchedDate"}); err != nil {
+		return fmt.Errorf("failure writing csv header: %w", err)
+	}
+	for _, row := range rows {
+		var year, rating, watchedDate string
+		if row.Year != 0 {
+			year = strconv.Itoa(row.Year)
+		}
+		if row.Rating != nil {
+			rating = strconv.Itoa(*row.Rating)
+		}
+		if row.WatchedDate != nil {
+			watchedDate = row.WatchedDate.Format(letterboxdDateLayout)
+		}
+		if err := cw.Write([]string{row.IMDbID, row.Title, year, rating, watchedDate}); err != nil {
+			return fmt.Errorf("failure writing csv row for %s: %w", row.IMDbID, err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writeLetterboxdCSV(t *testing.T) {
+	type args struct {
+		rows []syncer.ExportRow
+	}
+	rating := 8
+	watchedDate := time.Date(2024, time.March, 5, 21, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "write header only",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("imdbID,Title,Year,Rating10,WatchedDate\n", output)
+			},
+		},
+		{
+			name: "write rows and leave unknown fields empty",
+			args: args{
+				rows: []syncer.ExportRow{
+					{IMDbID: "tt5013056", Title: "Dunkirk", Year: 2017, Rating: &rating, WatchedDate: &watchedDate},
+					{IMDbID: "tt0000001", Title: "Carmencita, the Dancer"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "imdbID,Title,Year,Rating10,WatchedDate\n" +
+					"tt5013056,Dunkirk,2017,8,2024-03-05\n" +
+					"tt0000001,\"Carmencita, the Dancer\",,,\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writeLetterboxdCSV(&output, tt.args.rows)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package prune
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNamePrune),
+		Short: "Remove the items missing from the given IMDb lists from their Trakt lists, without syncing anything else",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			lists, err := c.Flags().GetStringSlice(cmd.FlagNameList)
+			if err != nil {
+				return err
+			}
+			if len(lists) == 0 {
+				return fmt.Errorf("flag --%s must name at least one imdb list", cmd.FlagNameList)
+			}
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+			}
+			lists, err := c.Flags().GetStringSlice(cmd.FlagNameList)
+			if err != nil {
+				return err
+			}
+			dryRun, err := c.Flags().GetBool(cmd.FlagNameDryRun)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				mode := config.SyncModeDryRun
+				conf.Sync.Mode = &mode
+				conf.Sync.Modes = config.Modes{}
+			}
+			force, err := c.Flags().GetBool(cmd.FlagNameForce)
+			if err != nil {
+				return err
+			}
+			if force {
+				conf.Sync.Removals.Force()
+			}
+			interactive, err := c.Flags().GetBool(cmd.FlagNameInteractive)
+			if err != nil {
+				return err
+			}
+			if interactive {
+				conf.Sync.Removals.ConfirmInteractively()
+			}
+			return prune(c.Context(), c.OutOrStdout(), conf, lists)
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to prune, the default config is used when omitted")
+	command.Flags().StringSlice(cmd.FlagNameList, nil, "comma separated ids of the imdb lists whose trakt lists should be pruned")
+	command.Flags().Bool(cmd.FlagNameDryRun, false, "only report the items that would be removed, overrides the sync mode of the config file")
+	command.Flags().Bool(cmd.FlagNameForce, false, "remove trakt items even when the removals exceed the configured limits")
+	command.Flags().Bool(cmd.FlagNameInteractive, false, "ask for confirmation in the terminal before removing trakt items exceeding the configured limits")
+	return command
+}
+
+func prune(ctx context.Context, w io.Writer, conf *config.Config, lists []string) error {
+	// the lists cache would leave out the lists unchanged since the previous run, hence everything is fetched
+	conf.Sync.FetchEverything()
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	pruned, err := s.Prune(ctx, lists)
+	if writeErr := writePruned(w, pruned); writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error pruning trakt lists: %w", err)
+	}
+	return nil
+}
+
+func writePruned(w io.Writer, pruned []syncer.PrunedList) error {
+	if len(pruned) == 0 {
+		_, err := fmt.Fprintln(w, "nothing to prune")
+		return err
+	}
+	for _, list := range pruned {
+		action := "removed"
+		if list.DryRun {
+			action = "would remove"
+		}
+		if _, err := fmt.Fprintf(w, "%s %d item(s) from trakt list %s of imdb list %s\n", action, len(list.Items), list.Slug, list.ListID); err != nil {
+			return err
+		}
+		for _, label := range list.Items.Labels() {
+			if _, err := fmt.Fprintf(w, "  - %s\n", label); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package prune
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writePruned(t *testing.T) {
+	type args struct {
+		pruned []syncer.PrunedList
+	}
+	items := entities.TraktItems{
+		{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}, Title: "Dunkirk", Year: 2017},
+		},
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "report nothing to prune",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("nothing to prune\n", output)
+			},
+		},
+		{
+			name: "report removed and pending items",
+			args: args{
+				pruned: []syncer.PrunedList{
+					{ListID: "ls123456789", Slug: "favourites", Items: items},
+					{ListID: "ls987654321", Slug: "war-films", Items: items, DryRun: true},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "removed 1 item(s) from trakt list favourites of imdb list ls123456789\n" +
+					"  - Dunkirk (2017) [tt5013056]\n" +
+					"would remove 1 item(s) from trakt list war-films of imdb list ls987654321\n" +
+					"  - Dunkirk (2017) [tt5013056]\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writePruned(&output, tt.args.pruned)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameRestore),
+		Short: fmt.Sprintf("Restore the trakt watchlist, ratings and lists to a snapshot saved with %s --%s, e.g. to roll back a sync", cmd.CommandNameSnapshot, cmd.FlagNameTrakt),
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			input, err := c.Flags().GetString(cmd.FlagNameInput)
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return fmt.Errorf("flag --%s must name a trakt snapshot file", cmd.FlagNameInput)
+			}
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+			}
+			input, err := c.Flags().GetString(cmd.FlagNameInput)
+			if err != nil {
+				return err
+			}
+			dryRun, err := c.Flags().GetBool(cmd.FlagNameDryRun)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				mode := config.SyncModeDryRun
+				conf.Sync.Mode = &mode
+				conf.Sync.Modes = config.Modes{}
+			}
+			force, err := c.Flags().GetBool(cmd.FlagNameForce)
+			if err != nil {
+				return err
+			}
+			if force {
+				conf.Sync.Removals.Force()
+			}
+			interactive, err := c.Flags().GetBool(cmd.FlagNameInteractive)
+			if err != nil {
+				return err
+			}
+			if interactive {
+				conf.Sync.Removals.ConfirmInteractively()
+			}
+			return restore(c.Context(), c.OutOrStdout(), conf, input)
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to restore, the default config is used when omitted")
+	command.Flags().String(cmd.FlagNameInput, "", "path to the trakt snapshot file to restore")
+	command.Flags().Bool(cmd.FlagNameDryRun, false, "only report the items that would be added and removed, overrides the sync mode of the config file")
+	command.Flags().Bool(cmd.FlagNameForce, false, "remove trakt items even when the removals exceed the configured limits")
+	command.Flags().Bool(cmd.FlagNameInteractive, false, "ask for confirmation in the terminal before removing trakt ratings added since the snapshot or trakt items exceeding the configured limits")
+	return command
+}
+
+func restore(ctx context.Context, w io.Writer, conf *config.Config, input string) error {
+	snapshot, err := syncer.LoadTraktSnapshot(input)
+	if err != nil {
+		return err
+	}
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	restored, err := s.Restore(ctx, snapshot)
+	if writeErr := writeRestored(w, restored); writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error restoring trakt snapshot: %w", err)
+	}
+	return nil
+}
+
+func writeRestored(w io.Writer, restored []syncer.RestoredTarget) error {
+	if len(restored) == 0 {
+		_, err := fmt.Fprintln(w, "nothing to restore, trakt matches the snapshot")
+		return err
+	}
+	for _, target := range restored {
+		added, removed := "added", "removed"
+		if target.DryRun {
+			added, removed = "would add", "would remove"
+		}
+		if _, err := fmt.Fprintf(w, "trakt %s: %s %d item(s), %s %d item(s)\n", target.Target, added, len(target.Added), removed, len(target.Removed)); err != nil {
+			return err
+		}
+		for _, label := range target.Added.Labels() {
+			if _, err := fmt.Fprintf(w, "  + %s\n", label); err != nil {
+				return err
+			}
+		}
+		for _, label := range target.Removed.Labels() {
+			if _, err := fmt.Fprintf(w, "  - %s\n", label); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
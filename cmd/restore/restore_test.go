@@ -0,0 +1,67 @@
+package restore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writeRestored(t *testing.T) {
+	type args struct {
+		restored []syncer.RestoredTarget
+	}
+	dunkirk := entities.TraktItems{
+		{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}, Title: "Dunkirk", Year: 2017},
+		},
+	}
+	tenet := entities.TraktItems{
+		{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt6723592"}, Title: "Tenet", Year: 2020},
+		},
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "report nothing to restore",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("nothing to restore, trakt matches the snapshot\n", output)
+			},
+		},
+		{
+			name: "report restored and pending items",
+			args: args{
+				restored: []syncer.RestoredTarget{
+					{Target: "watchlist", Added: dunkirk, Removed: tenet},
+					{Target: "list favourites", Added: tenet, DryRun: true},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "trakt watchlist: added 1 item(s), removed 1 item(s)\n" +
+					"  + Dunkirk (2017) [tt5013056]\n" +
+					"  - Tenet (2020) [tt6723592]\n" +
+					"trakt list favourites: would add 1 item(s), would remove 0 item(s)\n" +
+					"  + Tenet (2020) [tt6723592]\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writeRestored(&output, tt.args.restored)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
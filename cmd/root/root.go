@@ -1,13 +1,24 @@
 package root
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/cecobask/imdb-trakt-sync/cmd"
 	"github.com/cecobask/imdb-trakt-sync/cmd/configure"
+	"github.com/cecobask/imdb-trakt-sync/cmd/doctor"
+	"github.com/cecobask/imdb-trakt-sync/cmd/duplicates"
+	"github.com/cecobask/imdb-trakt-sync/cmd/export"
+	"github.com/cecobask/imdb-trakt-sync/cmd/prune"
+	"github.com/cecobask/imdb-trakt-sync/cmd/restore"
+	"github.com/cecobask/imdb-trakt-sync/cmd/snapshot"
+	"github.com/cecobask/imdb-trakt-sync/cmd/stats"
 	"github.com/cecobask/imdb-trakt-sync/cmd/sync"
+	"github.com/cecobask/imdb-trakt-sync/cmd/version"
+	appversion "github.com/cecobask/imdb-trakt-sync/internal/version"
 )
 
 func NewCommand() *cobra.Command {
@@ -26,13 +37,29 @@ func NewCommand() *cobra.Command {
 			DisableDefaultCmd: true,
 		},
 		SilenceUsage: true,
+		Version:      appversion.Get().String(),
 	}
+	command.SetVersionTemplate(fmt.Sprintf("%s version {{.Version}}\n", cmd.CommandNameRoot))
+	command.SetGlobalNormalizationFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == cmd.FlagNameConfigFileDeprecated {
+			name = cmd.FlagNameConfigFile
+		}
+		return pflag.NormalizedName(name)
+	})
 	command.SetHelpCommand(&cobra.Command{
 		Hidden: true,
 	})
 	command.AddCommand(
 		configure.NewCommand(),
+		doctor.NewCommand(),
+		duplicates.NewCommand(),
+		export.NewCommand(),
+		prune.NewCommand(),
+		restore.NewCommand(),
+		snapshot.NewCommand(),
+		stats.NewCommand(),
 		sync.NewCommand(),
+		version.NewCommand(),
 	)
 	command.SetOut(os.Stdout)
 	command.SetErr(os.Stderr)
@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameSnapshot),
+		Short: "Save the imdb data as a snapshot, or report what changed on imdb since a saved snapshot, without touching trakt. Save the trakt data instead with --trakt",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+			}
+			output, err := c.Flags().GetString(cmd.FlagNameOutput)
+			if err != nil {
+				return err
+			}
+			diff, err := c.Flags().GetString(cmd.FlagNameDiff)
+			if err != nil {
+				return err
+			}
+			trakt, err := c.Flags().GetBool(cmd.FlagNameTrakt)
+			if err != nil {
+				return err
+			}
+			if trakt {
+				return traktSnapshot(c.Context(), c.OutOrStdout(), conf, output)
+			}
+			return snapshot(c.Context(), c.OutOrStdout(), conf, output, diff)
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to snapshot, the default config is used when omitted")
+	command.Flags().String(cmd.FlagNameOutput, "", "path to the snapshot file to write, the snapshot is written to stdout when omitted")
+	command.Flags().String(cmd.FlagNameDiff, "", "path to a saved snapshot file, the changes since that snapshot are reported instead of writing a new one")
+	command.Flags().Bool(cmd.FlagNameTrakt, false, fmt.Sprintf("save the trakt watchlist, ratings and lists mirroring imdb lists instead, which can be pushed back with the %s command", cmd.CommandNameRestore))
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameDiff, cmd.FlagNameTrakt)
+	return command
+}
+
+func snapshot(ctx context.Context, w io.Writer, conf *config.Config, output, diff string) error {
+	// error logs keep stdout limited to the snapshot or its changes
+	level := config.LogLevelError
+	conf.Log.Level = &level
+	var previous *syncer.Snapshot
+	if diff != "" {
+		var err error
+		if previous, err = syncer.LoadSnapshot(diff); err != nil {
+			return err
+		}
+	}
+	current, err := syncer.TakeSnapshot(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error taking snapshot: %w", err)
+	}
+	if previous != nil {
+		return writeSnapshotDiff(w, current.Diff(previous))
+	}
+	if output != "" {
+		return current.Save(output)
+	}
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure encoding snapshot: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func traktSnapshot(ctx context.Context, w io.Writer, conf *config.Config, output string) error {
+	// dry-run mode keeps hydrating read-only, while error logs keep stdout limited to the snapshot
+	mode, level := config.SyncModeDryRun, config.LogLevelError
+	conf.Sync.Mode = &mode
+	conf.Sync.Modes = config.Modes{}
+	conf.Log.Level = &level
+	// the lists cache would leave out the lists unchanged since the previous run, hence everything is fetched
+	conf.Sync.FetchEverything()
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	current, err := s.TakeTraktSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("error taking trakt snapshot: %w", err)
+	}
+	if output != "" {
+		return current.Save(output)
+	}
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure encoding trakt snapshot: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func writeSnapshotDiff(w io.Writer, diff syncer.SnapshotDiff) error {
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintln(w, "nothing changed since the snapshot")
+		return err
+	}
+	for _, list := range diff.Lists {
+		if _, err := fmt.Fprintf(w, "list %s (%s)\n", list.ListName, list.ListID); err != nil {
+			return err
+		}
+		if err := writeDelta(w, list.Diff); err != nil {
+			return err
+		}
+	}
+	if len(diff.Ratings) != 0 {
+		if _, err := fmt.Fprintln(w, "ratings"); err != nil {
+			return err
+		}
+		if err := writeDelta(w, diff.Ratings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeDelta(w io.Writer, delta map[string]entities.TraktItems) error {
+	for _, action := range []struct {
+		key    string
+		symbol string
+	}{{"add", "+"}, {"remove", "-"}, {"update", "~"}} {
+		for _, item := range delta[action.key] {
+			id, _ := item.GetItemID()
+			spec := itemSpec(item)
+			if _, err := fmt.Fprintf(w, "  %s %s %s (%d): %s\n", action.symbol, *id, spec.Title, spec.Year, item.Reason); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func itemSpec(item entities.TraktItem) entities.TraktItemSpec {
+	switch item.Type {
+	case entities.TraktItemTypeShow:
+		return item.Show
+	case entities.TraktItemTypeEpisode:
+		return item.Episode
+	default:
+		return item.Movie
+	}
+}
@@ -0,0 +1,84 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writeSnapshotDiff(t *testing.T) {
+	type args struct {
+		diff syncer.SnapshotDiff
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "report nothing changed",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("nothing changed since the snapshot\n", output)
+			},
+		},
+		{
+			name: "report list and ratings changes",
+			args: args{
+				diff: syncer.SnapshotDiff{
+					Lists: []syncer.ListDelta{
+						{
+							ListID:   "ls123456789",
+							ListName: "Favourites",
+							Diff: map[string]entities.TraktItems{
+								"remove": {
+									{
+										Type:   entities.TraktItemTypeShow,
+										Show:   entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0903747"}, Title: "Breaking Bad", Year: 2008},
+										Reason: syncer.DiffReasonRemovedSinceSnapshot,
+									},
+								},
+								"add": {
+									{
+										Type:   entities.TraktItemTypeMovie,
+										Movie:  entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}, Title: "Dunkirk", Year: 2017},
+										Reason: syncer.DiffReasonAddedSinceSnapshot,
+									},
+								},
+							},
+						},
+					},
+					Ratings: map[string]entities.TraktItems{
+						"update": {
+							{
+								Type:   entities.TraktItemTypeMovie,
+								Movie:  entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}, Title: "Dunkirk", Year: 2017},
+								Reason: "rating differs 7→8",
+							},
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "list Favourites (ls123456789)\n" +
+					"  + tt5013056 Dunkirk (2017): added since the snapshot\n" +
+					"  - tt0903747 Breaking Bad (2008): removed since the snapshot\n" +
+					"ratings\n" +
+					"  ~ tt5013056 Dunkirk (2017): rating differs 7→8\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writeSnapshotDiff(&output, tt.args.diff)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
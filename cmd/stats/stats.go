@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func NewCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameStats),
+		Short: "Compare the number of items on IMDb and Trakt without syncing anything",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			confPath = config.Discover(confPath)
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return conf.Validate()
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			if profile != "" {
+				if conf, err = conf.Profile(profile); err != nil {
+					return err
+				}
+				return stats(c.Context(), c.OutOrStdout(), conf)
+			}
+			if len(conf.Profiles) == 0 {
+				return stats(c.Context(), c.OutOrStdout(), conf)
+			}
+			for _, name := range conf.ProfileNames() {
+				profileConf, err := conf.Profile(name)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(c.OutOrStdout(), "profile %s\n", name)
+				if err = stats(c.Context(), c.OutOrStdout(), profileConf); err != nil {
+					return fmt.Errorf("profile %s: %w", name, err)
+				}
+			}
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to compare, all profiles are compared when omitted")
+	return command
+}
+
+func stats(ctx context.Context, w io.Writer, conf *config.Config) error {
+	// dry-run mode and error logs keep the comparison read-only and the output limited to the table
+	mode, level := config.SyncModeDryRun, config.LogLevelError
+	conf.Sync.Mode = &mode
+	conf.Sync.Modes = config.Modes{}
+	conf.Log.Level = &level
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	rows, err := s.Compare(ctx)
+	if err != nil {
+		return fmt.Errorf("error comparing imdb and trakt: %w", err)
+	}
+	return writeComparison(w, rows)
+}
+
+func writeComparison(w io.Writer, rows []syncer.ComparisonRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tIMDB\tTRAKT\tTO ADD\tTO REMOVE\tTO UPDATE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", row.Category, row.IMDb, row.Trakt, row.Add, row.Remove, row.Update)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func Test_writeComparison(t *testing.T) {
+	type args struct {
+		rows []syncer.ComparisonRow
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "write header only",
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("CATEGORY  IMDB  TRAKT  TO ADD  TO REMOVE  TO UPDATE\n", output)
+			},
+		},
+		{
+			name: "write aligned rows",
+			args: args{
+				rows: []syncer.ComparisonRow{
+					{Category: "watchlist", IMDb: 12, Trakt: 10, Add: 3, Remove: 1},
+					{Category: "ratings", IMDb: 250, Trakt: 248, Add: 2, Update: 5},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, output string, err error) {
+				assertions.NoError(err)
+				expected := "CATEGORY   IMDB  TRAKT  TO ADD  TO REMOVE  TO UPDATE\n" +
+					"watchlist  12    10     3       1          0\n" +
+					"ratings    250   248    2       0          5\n"
+				assertions.Equal(expected, output)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := writeComparison(&output, tt.args.rows)
+			tt.assertions(assert.New(t), output.String(), err)
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package sync
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cecobask/imdb-trakt-sync/cmd"
 	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/daemon"
 	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+	"github.com/cecobask/imdb-trakt-sync/pkg/cron"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
 func NewCommand() *cobra.Command {
@@ -20,19 +28,224 @@ func NewCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			confPath = config.Discover(confPath)
 			if conf, err = config.New(confPath, true); err != nil {
 				return fmt.Errorf("error loading config: %w", err)
 			}
+			if err = overrideLogLevel(c, conf); err != nil {
+				return err
+			}
 			return conf.Validate()
 		},
 		RunE: func(c *cobra.Command, args []string) error {
-			s, err := syncer.NewSyncer(conf)
+			profile, err := c.Flags().GetString(cmd.FlagNameProfile)
+			if err != nil {
+				return err
+			}
+			only, err := c.Flags().GetStringSlice(cmd.FlagNameOnly)
+			if err != nil {
+				return err
+			}
+			skip, err := c.Flags().GetStringSlice(cmd.FlagNameSkip)
 			if err != nil {
-				return fmt.Errorf("error creating syncer: %w", err)
+				return err
+			}
+			lists, err := c.Flags().GetStringSlice(cmd.FlagNameList)
+			if err != nil {
+				return err
 			}
-			return s.Sync()
+			if len(lists) != 0 && len(only) == 0 && len(skip) == 0 {
+				only = []string{config.SyncCategoryLists}
+			}
+			if err = conf.Sync.OverrideCategories(only, skip); err != nil {
+				return err
+			}
+			force, err := c.Flags().GetBool(cmd.FlagNameForce)
+			if err != nil {
+				return err
+			}
+			if force {
+				conf.Sync.Removals.Force()
+				conf.Sync.Additions.Force()
+			}
+			full, err := c.Flags().GetBool(cmd.FlagNameFull)
+			if err != nil {
+				return err
+			}
+			if full {
+				conf.Sync.FetchEverything()
+			}
+			interactive, err := c.Flags().GetBool(cmd.FlagNameInteractive)
+			if err != nil {
+				return err
+			}
+			if interactive {
+				conf.Sync.Removals.ConfirmInteractively()
+				conf.Sync.Additions.ConfirmInteractively()
+				conf.Sync.ReviewChanges()
+			}
+			from, err := c.Flags().GetString(cmd.FlagNameFrom)
+			if err != nil {
+				return err
+			}
+			to, err := c.Flags().GetString(cmd.FlagNameTo)
+			if err != nil {
+				return err
+			}
+			if err = conf.Sync.RestrictDates(from, to); err != nil {
+				return err
+			}
+			if c.Flags().Changed(cmd.FlagNameMaxItems) {
+				maxItems, err := c.Flags().GetInt(cmd.FlagNameMaxItems)
+				if err != nil {
+					return err
+				}
+				conf.Sync.MaxItems = &maxItems
+			}
+			run := func(ctx context.Context) error {
+				return syncProfiles(ctx, conf, lists)
+			}
+			if profile != "" {
+				profileConf, err := conf.Profile(profile)
+				if err != nil {
+					return err
+				}
+				run = func(ctx context.Context) error {
+					return sync(ctx, profileConf, lists)
+				}
+			} else if len(conf.Profiles) == 0 {
+				run = func(ctx context.Context) error {
+					return sync(ctx, conf, lists)
+				}
+			}
+			if conf.Daemon.IsEnabled() {
+				return runDaemon(c.Context(), conf, run)
+			}
+			return cmd.ApplyPartialFailurePolicy(conf.Sync.PartialFailurePolicy(), run(c.Context()))
 		},
 	}
-	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameConfigFile, "", cmd.FlagUsageConfigFile)
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to sync, all profiles are synced sequentially when omitted")
+	command.Flags().StringSlice(cmd.FlagNameOnly, nil, "comma separated categories to sync, all other categories are skipped (lists, ratings, check-ins, history, comments), lists includes the watchlist")
+	command.Flags().Bool(cmd.FlagNameFull, false, "ignore the lists cache and fetch all trakt lists and the watchlist, even when they didn't change since the previous run")
+	command.Flags().Bool(cmd.FlagNameInteractive, false, "review the list and rating changes in the terminal before applying them, and ask for confirmation before removing trakt ratings or adding or removing trakt items exceeding the configured limits")
+	command.Flags().Int(cmd.FlagNameMaxItems, 0, "maximum number of items added or removed per list and category, e.g. to try the syncer on a large library, overrides the config file")
+	command.Flags().String(cmd.FlagNameFrom, "", "only sync the ratings and history of items rated or watched on or after this date, e.g. 2023-01-01")
+	command.Flags().String(cmd.FlagNameTo, "", "only sync the ratings and history of items rated or watched on or before this date, e.g. 2023-12-31")
+	command.Flags().StringSlice(cmd.FlagNameList, nil, "comma separated ids of the imdb lists to sync, all other lists and the watchlist are skipped, implies --only lists unless --only or --skip is set")
+	command.Flags().String(cmd.FlagNameLogLevel, "", fmt.Sprintf("log level, overrides the config file (%s)", strings.Join(config.ValidLogLevels(), ", ")))
+	command.Flags().BoolP(cmd.FlagNameVerbose, "v", false, fmt.Sprintf("shorthand for --%s=%s", cmd.FlagNameLogLevel, config.LogLevelDebug))
+	command.Flags().BoolP(cmd.FlagNameQuiet, "q", false, fmt.Sprintf("shorthand for --%s=%s", cmd.FlagNameLogLevel, config.LogLevelWarn))
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameLogLevel, cmd.FlagNameVerbose, cmd.FlagNameQuiet)
+	command.Flags().Bool(cmd.FlagNameForce, false, "add and remove trakt items even when the additions or removals exceed the configured limits")
+	command.Flags().StringSlice(cmd.FlagNameSkip, nil, "comma separated categories to skip (lists, ratings, check-ins, history, comments), lists includes the watchlist")
 	return command
 }
+
+func overrideLogLevel(c *cobra.Command, conf *config.Config) error {
+	level, err := c.Flags().GetString(cmd.FlagNameLogLevel)
+	if err != nil {
+		return err
+	}
+	verbose, err := c.Flags().GetBool(cmd.FlagNameVerbose)
+	if err != nil {
+		return err
+	}
+	quiet, err := c.Flags().GetBool(cmd.FlagNameQuiet)
+	if err != nil {
+		return err
+	}
+	switch {
+	case verbose:
+		level = config.LogLevelDebug
+	case quiet:
+		level = config.LogLevelWarn
+	}
+	if level != "" {
+		conf.Log.Level = &level
+	}
+	return nil
+}
+
+func sync(ctx context.Context, conf *config.Config, lists []string) error {
+	if err := conf.IMDb.RestrictLists(lists); err != nil {
+		return err
+	}
+	if timeout := conf.Sync.Timeout; timeout != nil && *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	s, err := syncer.NewSyncer(ctx, conf)
+	if err != nil {
+		return fmt.Errorf("error creating syncer: %w", err)
+	}
+	_, err = s.Sync(ctx)
+	return err
+}
+
+// runDaemon keeps syncing on the configured interval or cron schedule until the context is cancelled, serving the health endpoints when enabled
+func runDaemon(ctx context.Context, conf *config.Config, run func(context.Context) error) error {
+	level, err := logger.ParseLevel(conf.Log.LevelOrDefault())
+	if err != nil {
+		return err
+	}
+	var schedule daemon.Schedule
+	if conf.Daemon.HasSchedule() {
+		if schedule, err = cron.Parse(*conf.Daemon.Schedule); err != nil {
+			return err
+		}
+	} else {
+		schedule = daemon.Interval(*conf.Daemon.Interval)
+	}
+	policy := conf.Sync.PartialFailurePolicy()
+	d := daemon.New(clock.New(), logger.NewLoggerWithLevel(os.Stdout, level), schedule, func(ctx context.Context) error {
+		// the daemon records the outcome of each run the way a single run would report it
+		return cmd.ApplyPartialFailurePolicy(policy, run(ctx))
+	})
+	if !conf.Daemon.ShouldServeHealth() {
+		return d.Run(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- d.Serve(ctx, *conf.Daemon.HealthAddress)
+		// the daemon is stopped when the health endpoints can't be served, rather than running unmonitored
+		cancel()
+	}()
+	runErr := d.Run(ctx)
+	cancel()
+	return errors.Join(runErr, <-serveErr)
+}
+
+func syncProfiles(ctx context.Context, conf *config.Config, lists []string) error {
+	var (
+		errs    []error
+		partial bool
+	)
+	for _, name := range conf.ProfileNames() {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		profileConf, err := conf.Profile(name)
+		if err != nil {
+			return err
+		}
+		if err = sync(ctx, profileConf, lists); err != nil {
+			var partialSyncError *syncer.PartialSyncError
+			partial = partial || errors.As(err, &partialSyncError)
+			errs = append(errs, fmt.Errorf("profile %s: %w", name, err))
+			continue
+		}
+		partial = true
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	if partial {
+		return &syncer.PartialSyncError{Err: errors.Join(errs...)}
+	}
+	return errors.Join(errs...)
+}
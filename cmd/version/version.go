@@ -0,0 +1,22 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/version"
+)
+
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   cmd.CommandNameVersion,
+		Short: "Print the version, git commit and build date",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			_, err := fmt.Fprintf(c.OutOrStdout(), "%s version %s\n", cmd.CommandNameRoot, version.Get())
+			return err
+		},
+	}
+}
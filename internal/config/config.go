@@ -2,49 +2,877 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/cron"
+)
+
+var (
+	listIDPattern = regexp.MustCompile(`ls\d+`)
+	// matches a trakt list slug, optionally preceded by its owner, or the url of a trakt list
+	traktListPattern = regexp.MustCompile(`^(?:https?://(?:www\.)?trakt\.tv/users/)?(?:([^/\s]+)/(?:lists/)?)?([^/?#\s]+)/?$`)
+	traktSlugPattern = regexp.MustCompile(`^[-_a-z0-9]+$`)
 )
 
 type IMDb struct {
-	CookieAtMain   *string  `koanf:"COOKIEATMAIN"`
-	CookieUbidMain *string  `koanf:"COOKIEUBIDMAIN"`
-	Lists          []string `koanf:"LISTS"`
+	Auth             *string                `koanf:"AUTH"`
+	CookieAtMain     *string                `koanf:"COOKIEATMAIN"`
+	CookieUbidMain   *string                `koanf:"COOKIEUBIDMAIN"`
+	ExportsDir       *string                `koanf:"EXPORTSDIR"`
+	Lists            []string               `koanf:"LISTS"`
+	Accounts         map[string]IMDbAccount `koanf:"ACCOUNTS"`
+	AccountsConflict *string                `koanf:"ACCOUNTSCONFLICT"`
+	restricted       bool
+}
+
+// IMDbAccount is an additional imdb account, whose data is merged with the data of the main account
+type IMDbAccount struct {
+	Auth           *string `koanf:"AUTH"`
+	CookieAtMain   *string `koanf:"COOKIEATMAIN"`
+	CookieUbidMain *string `koanf:"COOKIEUBIDMAIN"`
+	ExportsDir     *string `koanf:"EXPORTSDIR"`
+}
+
+// AccountNames returns the names of the additional imdb accounts in alphabetical order, which is the order they are merged in
+func (i IMDb) AccountNames() []string {
+	names := make([]string, 0, len(i.Accounts))
+	for name := range i.Accounts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Account resolves the named additional imdb account into a standalone imdb config, sharing the lists of the main account
+func (i IMDb) Account(name string) IMDb {
+	account := i.Accounts[name]
+	conf := i
+	conf.Auth = account.Auth
+	conf.CookieAtMain = account.CookieAtMain
+	conf.CookieUbidMain = account.CookieUbidMain
+	conf.ExportsDir = account.ExportsDir
+	conf.Accounts = nil
+	return conf
+}
+
+func (i IMDb) AccountsConflictPolicy() string {
+	if i.AccountsConflict == nil || *i.AccountsConflict == "" {
+		return AccountsConflictPrimary
+	}
+	return *i.AccountsConflict
+}
+
+func (i IMDb) IsOffline() bool {
+	return i.ExportsDir != nil && *i.ExportsDir != ""
+}
+
+// IsAuthless reports whether imdb is accessed anonymously, in which case only public lists can be synced
+func (i IMDb) IsAuthless() bool {
+	return !i.IsOffline() && i.Auth != nil && *i.Auth == IMDbAuthNone
+}
+
+// RestrictLists limits the sync to the given imdb lists, excluding the watchlist
+// The lists must be part of the configured lists, unless all lists are synced, in which case any list is allowed
+func (i *IMDb) RestrictLists(listIDs []string) error {
+	if len(listIDs) == 0 {
+		return nil
+	}
+	if len(i.Lists) != 0 {
+		for _, listID := range listIDs {
+			if !slices.Contains(i.Lists, listID) {
+				return fmt.Errorf("imdb list %s is not configured, configured lists: %s", listID, strings.Join(i.Lists, ", "))
+			}
+		}
+	}
+	i.Lists = listIDs
+	i.restricted = true
+	return nil
+}
+
+func (i IMDb) IsRestricted() bool {
+	return i.restricted
 }
 
 type Trakt struct {
-	Email        *string `koanf:"EMAIL"`
-	Password     *string `koanf:"PASSWORD"`
-	ClientID     *string `koanf:"CLIENTID"`
-	ClientSecret *string `koanf:"CLIENTSECRET"`
+	Email        *string        `koanf:"EMAIL"`
+	Password     *string        `koanf:"PASSWORD"`
+	ClientID     *string        `koanf:"CLIENTID"`
+	ClientSecret *string        `koanf:"CLIENTSECRET"`
+	BatchSize    *int           `koanf:"BATCHSIZE"`
+	TitleMatch   *string        `koanf:"TITLEMATCH"`
+	WriteDelay   *time.Duration `koanf:"WRITEDELAY"`
+	SecretsFile  *string        `koanf:"SECRETSFILE"`
+	TokensFile   *string        `koanf:"TOKENSFILE"`
+	Keyring      *bool          `koanf:"KEYRING"`
+}
+
+// TokensFilePath returns the path of the file the trakt tokens are kept in between runs, which is empty when they aren't kept in a file
+func (t Trakt) TokensFilePath() string {
+	if t.TokensFile == nil {
+		return ""
+	}
+	return *t.TokensFile
+}
+
+// ShouldUseKeyring tells whether the trakt tokens are kept in the os keyring between runs
+func (t Trakt) ShouldUseKeyring() bool {
+	return t.Keyring != nil && *t.Keyring
+}
+
+// WriteDelayDuration returns the minimum delay between requests that modify trakt data, zero means no delay
+func (t Trakt) WriteDelayDuration() time.Duration {
+	if t.WriteDelay == nil || *t.WriteDelay < 0 {
+		return 0
+	}
+	return *t.WriteDelay
+}
+
+// TitleMatchStrategy returns how items that trakt can't find by imdb id are matched by title and year
+func (t Trakt) TitleMatchStrategy() string {
+	if t.TitleMatch == nil || *t.TitleMatch == "" {
+		return TitleMatchOff
+	}
+	return *t.TitleMatch
 }
 
 type Sync struct {
-	Mode        *string `koanf:"MODE"`
-	SkipHistory *bool   `koanf:"SKIPHISTORY"`
+	Mode                           *string        `koanf:"MODE"`
+	Modes                          Modes          `koanf:"MODES"`
+	SkipHistory                    *bool          `koanf:"SKIPHISTORY"`
+	Timeout                        *time.Duration `koanf:"TIMEOUT"`
+	ContinueOnError                *bool          `koanf:"CONTINUEONERROR"`
+	PartialFailure                 *string        `koanf:"PARTIALFAILURE"`
+	ProgressInterval               *time.Duration `koanf:"PROGRESSINTERVAL"`
+	RatingsConflict                *string        `koanf:"RATINGSCONFLICT"`
+	Removals                       Removals       `koanf:"REMOVALS"`
+	Additions                      Additions      `koanf:"ADDITIONS"`
+	CacheFile                      *string        `koanf:"CACHEFILE"`
+	MetricsFile                    *string        `koanf:"METRICSFILE"`
+	SummaryFile                    *string        `koanf:"SUMMARYFILE"`
+	ErrorsFile                     *string        `koanf:"ERRORSFILE"`
+	CheckpointFile                 *string        `koanf:"CHECKPOINTFILE"`
+	RatingsStateFile               *string        `koanf:"RATINGSSTATEFILE"`
+	LogItems                       *bool          `koanf:"LOGITEMS"`
+	RatingImpliesWatched           *bool          `koanf:"RATINGIMPLIESWATCHED"`
+	RemoveHistory                  *bool          `koanf:"REMOVEHISTORY"`
+	HistoryBatchSize               *int           `koanf:"HISTORYBATCHSIZE"`
+	WatchedLists                   []string       `koanf:"WATCHEDLISTS"`
+	MaxItems                       *int           `koanf:"MAXITEMS"`
+	WatchlistRemovalImpliesWatched *bool          `koanf:"WATCHLISTREMOVALIMPLIESWATCHED"`
+	WatchlistTypes                 []string       `koanf:"WATCHLISTTYPES"`
+	RemoveFilteredWatchlistItems   *bool          `koanf:"REMOVEFILTEREDWATCHLISTITEMS"`
+	MinRating                      *int           `koanf:"MINRATING"`
+	RemoveBelowMinRating           *bool          `koanf:"REMOVEBELOWMINRATING"`
+	WatchlistRemovalMinRating      *int           `koanf:"WATCHLISTREMOVALMINRATING"`
+	Bidirectional                  *bool          `koanf:"BIDIRECTIONAL"`
+	WatchedRating                  *int           `koanf:"WATCHEDRATING"`
+	HiddenSections                 []string       `koanf:"HIDDENSECTIONS"`
+	MatchMissingIDs                *bool          `koanf:"MATCHMISSINGIDS"`
+	Comments                       Comments       `koanf:"COMMENTS"`
+	CheckIns                       CheckIns       `koanf:"CHECKINS"`
+	overrides                      map[string]bool
+	fullFetch                      bool
+	dateFrom                       string
+	dateTo                         string
+	review                         bool
+}
+
+// FetchEverything ignores the state kept between runs in the lists cache for the current run, fetching all lists and the watchlist again
+func (s *Sync) FetchEverything() {
+	s.fullFetch = true
+}
+
+func (s Sync) IsFullFetch() bool {
+	return s.fullFetch
+}
+
+// ReviewChanges lets the list and rating changes be approved or rejected in the terminal before any of them is applied
+func (s *Sync) ReviewChanges() {
+	s.review = true
+}
+
+func (s Sync) ShouldReview() bool {
+	return s.review
+}
+
+// ShouldLogItems reports whether the items left untouched due to the sync mode should be listed, which is the default
+func (s Sync) ShouldLogItems() bool {
+	return s.LogItems == nil || *s.LogItems
+}
+
+func (s Sync) ShouldRatingImplyWatched() bool {
+	return s.RatingImpliesWatched != nil && *s.RatingImpliesWatched
+}
+
+// ShouldRemoveHistory returns whether trakt history may be removed, history is add-only unless enabled, regardless of the sync mode
+func (s Sync) ShouldRemoveHistory() bool {
+	return s.RemoveHistory != nil && *s.RemoveHistory
+}
+
+// HistoryBatchSizeOrDefault returns the number of history items added to trakt at a time, 0 adds all of them at once
+func (s Sync) HistoryBatchSizeOrDefault() int {
+	if s.HistoryBatchSize == nil {
+		return 0
+	}
+	return *s.HistoryBatchSize
+}
+
+func (s Sync) ShouldWatchlistRemovalImplyWatched() bool {
+	return s.WatchlistRemovalImpliesWatched != nil && *s.WatchlistRemovalImpliesWatched
+}
+
+// ShouldRemoveFilteredWatchlistItems reports whether the trakt watchlist items of types left out by the watchlist types are removed
+func (s Sync) ShouldRemoveFilteredWatchlistItems() bool {
+	return s.RemoveFilteredWatchlistItems != nil && *s.RemoveFilteredWatchlistItems
+}
+
+// RatingThreshold returns the minimum imdb rating of the ratings to sync, 0 means all ratings are synced
+func (s Sync) RatingThreshold() int {
+	if s.MinRating == nil {
+		return 0
+	}
+	return *s.MinRating
+}
+
+func (s Sync) ShouldRemoveBelowMinRating() bool {
+	return s.RemoveBelowMinRating != nil && *s.RemoveBelowMinRating
+}
+
+// MaxItemsPerChange returns how many items each change of a category processes at most in a run, zero means unlimited
+func (s Sync) MaxItemsPerChange() int {
+	if s.MaxItems == nil || *s.MaxItems < 0 {
+		return 0
+	}
+	return *s.MaxItems
+}
+
+// WatchlistRemovalRating returns the minimum imdb rating of the newly rated items removed from the trakt watchlist, zero means disabled
+func (s Sync) WatchlistRemovalRating() int {
+	if s.WatchlistRemovalMinRating == nil {
+		return 0
+	}
+	return *s.WatchlistRemovalMinRating
+}
+
+// IsBidirectional reports whether the items watched on trakt, but never rated on imdb, are rated on imdb with the watched rating
+func (s Sync) IsBidirectional() bool {
+	return s.Bidirectional != nil && *s.Bidirectional
+}
+
+// PlaceholderRating returns the imdb rating given to the items watched on trakt, zero means they aren't rated on imdb
+func (s Sync) PlaceholderRating() int {
+	if !s.IsBidirectional() || s.WatchedRating == nil {
+		return 0
+	}
+	return *s.WatchedRating
+}
+
+// ShouldMatchMissingIDs reports whether the trakt items lacking an imdb id are matched to imdb items by title and year, rather than skipped
+func (s Sync) ShouldMatchMissingIDs() bool {
+	return s.MatchMissingIDs != nil && *s.MatchMissingIDs
+}
+
+// HasHiddenSections reports whether the items hidden in any trakt section must not be added to trakt lists and ratings
+func (s Sync) HasHiddenSections() bool {
+	return len(s.HiddenSections) != 0
+}
+
+// OverrideCategories force-enables the categories in only, disabling all others, and force-disables the categories in skip
+func (s *Sync) OverrideCategories(only, skip []string) error {
+	for _, category := range append(slices.Clone(only), skip...) {
+		if category == SyncCategoryWatchlist {
+			return fmt.Errorf("sync category %s can't be selected on its own, it's synced as part of sync category %s", category, SyncCategoryLists)
+		}
+		if !slices.Contains(validSyncCategories(), category) {
+			return fmt.Errorf("sync category %s must be one of: %s", category, strings.Join(validSyncCategories(), ", "))
+		}
+	}
+	for _, category := range only {
+		if slices.Contains(skip, category) {
+			return fmt.Errorf("sync category %s can't be both included and skipped", category)
+		}
+	}
+	if len(only) == 0 && len(skip) == 0 {
+		return nil
+	}
+	s.overrides = make(map[string]bool)
+	if len(only) != 0 {
+		for _, category := range validSyncCategories() {
+			s.overrides[category] = slices.Contains(only, category)
+		}
+	}
+	for _, category := range skip {
+		s.overrides[category] = false
+	}
+	if enabled, ok := s.overrides[SyncCategoryHistory]; ok {
+		skipHistory := !enabled
+		s.SkipHistory = &skipHistory
+	}
+	return nil
+}
+
+// RestrictDates limits the ratings and history sync to the items rated or watched from one date to another, e.g. to backfill a single year
+// both dates are inclusive days in UTC, formatted like 2023-01-31, and either of them may be empty to leave the range open on that side
+func (s *Sync) RestrictDates(from, to string) error {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("date %s must be formatted like %s", date, time.DateOnly)
+		}
+	}
+	// dates formatted like 2006-01-02 sort chronologically
+	if from != "" && to != "" && from > to {
+		return fmt.Errorf("date range start %s must not be after its end %s", from, to)
+	}
+	s.dateFrom, s.dateTo = from, to
+	return nil
+}
+
+func (s Sync) HasDateRange() bool {
+	return s.dateFrom != "" || s.dateTo != ""
+}
+
+// InDateRange reports whether the day of t in UTC lies within the date range, which is always the case without a range
+func (s Sync) InDateRange(t time.Time) bool {
+	date := t.UTC().Format(time.DateOnly)
+	return (s.dateFrom == "" || date >= s.dateFrom) && (s.dateTo == "" || date <= s.dateTo)
+}
+
+func (s Sync) RatingsConflictPolicy() string {
+	if s.RatingsConflict == nil || *s.RatingsConflict == "" {
+		return RatingsConflictIMDbWins
+	}
+	return *s.RatingsConflict
+}
+
+func (s Sync) CategoryOverride(category string) (enabled bool, ok bool) {
+	enabled, ok = s.overrides[category]
+	return enabled, ok
+}
+
+// PartialFailurePolicy returns how a run is reported when some categories failed while others were synced
+func (s Sync) PartialFailurePolicy() string {
+	if s.PartialFailure == nil || *s.PartialFailure == "" {
+		return PartialFailurePartial
+	}
+	return *s.PartialFailure
+}
+
+// Modes overrides the sync mode per category, the categories left empty use the sync mode
+type Modes struct {
+	Lists     *string `koanf:"LISTS"`
+	Watchlist *string `koanf:"WATCHLIST"`
+	Ratings   *string `koanf:"RATINGS"`
+	History   *string `koanf:"HISTORY"`
+	Comments  *string `koanf:"COMMENTS"`
+}
+
+// ModeFor returns the sync mode of a category, falling back to the sync mode when it isn't overridden
+func (s Sync) ModeFor(category string) string {
+	var mode *string
+	switch category {
+	case SyncCategoryLists:
+		mode = s.Modes.Lists
+	case SyncCategoryWatchlist:
+		mode = s.Modes.Watchlist
+	case SyncCategoryRatings:
+		mode = s.Modes.Ratings
+	case SyncCategoryHistory, SyncCategoryCheckIns:
+		mode = s.Modes.History
+	case SyncCategoryComments:
+		mode = s.Modes.Comments
+	}
+	if mode == nil || *mode == "" {
+		return *s.Mode
+	}
+	return *mode
+}
+
+type Removals struct {
+	MaxCount     *int  `koanf:"MAXCOUNT"`
+	MaxPercent   *int  `koanf:"MAXPERCENT"`
+	AllowRatings *bool `koanf:"ALLOWRATINGS"`
+	forced       bool
+	interactive  bool
+}
+
+// Force lifts the removal limits for the current run
+func (r *Removals) Force() {
+	r.forced = true
+}
+
+func (r Removals) IsForced() bool {
+	return r.forced
+}
+
+// ConfirmInteractively asks for confirmation in the terminal before removing ratings, unless they are allowed already, and before removing items exceeding the limits, instead of failing
+func (r *Removals) ConfirmInteractively() {
+	r.interactive = true
+}
+
+func (r Removals) IsInteractive() bool {
+	return r.interactive
+}
+
+func (r Removals) RatingsAllowed() bool {
+	return r.AllowRatings != nil && *r.AllowRatings
+}
+
+// Additions limits the items added to a single trakt list, the watchlist, ratings or history in one run, like Removals limits the removals
+type Additions struct {
+	MaxCount    *int `koanf:"MAXCOUNT"`
+	MaxPercent  *int `koanf:"MAXPERCENT"`
+	forced      bool
+	interactive bool
+}
+
+// Force lifts the addition limits for the current run
+func (a *Additions) Force() {
+	a.forced = true
+}
+
+func (a Additions) IsForced() bool {
+	return a.forced
+}
+
+// ConfirmInteractively asks for confirmation in the terminal before adding items exceeding the limits, instead of failing
+func (a *Additions) ConfirmInteractively() {
+	a.interactive = true
+}
+
+func (a Additions) IsInteractive() bool {
+	return a.interactive
+}
+
+type Comments struct {
+	List    *string `koanf:"LIST"`
+	Spoiler *bool   `koanf:"SPOILER"`
+	Reviews *bool   `koanf:"REVIEWS"`
+}
+
+func (c Comments) IsEnabled() bool {
+	return c.HasList() || c.ShouldSyncReviews()
+}
+
+func (c Comments) HasList() bool {
+	return c.List != nil && *c.List != ""
+}
+
+// ShouldSyncReviews reports whether the reviews of the imdb ratings export should be posted as trakt comments as well
+func (c Comments) ShouldSyncReviews() bool {
+	return c.Reviews != nil && *c.Reviews
+}
+
+type CheckIns struct {
+	List     *string `koanf:"LIST"`
+	Scrobble *bool   `koanf:"SCROBBLE"`
+}
+
+func (c CheckIns) IsEnabled() bool {
+	return c.List != nil && *c.List != ""
+}
+
+func (c CheckIns) ShouldScrobble() bool {
+	return c.Scrobble != nil && *c.Scrobble
+}
+
+type Notify struct {
+	WebhookURL *string `koanf:"WEBHOOKURL"`
+	Preset     *string `koanf:"PRESET"`
+}
+
+func (n Notify) IsEnabled() bool {
+	return n.WebhookURL != nil && *n.WebhookURL != ""
+}
+
+type Log struct {
+	Level *string `koanf:"LEVEL"`
+}
+
+type Daemon struct {
+	Interval      *time.Duration `koanf:"INTERVAL"`
+	Schedule      *string        `koanf:"SCHEDULE"`
+	HealthAddress *string        `koanf:"HEALTHADDRESS"`
+}
+
+// IsEnabled reports whether the sync command keeps running and syncs on an interval or cron schedule, instead of syncing once
+func (d Daemon) IsEnabled() bool {
+	return d.HasInterval() || d.HasSchedule()
+}
+
+func (d Daemon) HasInterval() bool {
+	return d.Interval != nil && *d.Interval > 0
+}
+
+func (d Daemon) HasSchedule() bool {
+	return d.Schedule != nil && *d.Schedule != ""
+}
+
+// ShouldServeHealth reports whether the daemon serves the health endpoints
+func (d Daemon) ShouldServeHealth() bool {
+	return d.HealthAddress != nil && *d.HealthAddress != ""
+}
+
+type HTTP struct {
+	UserAgent *string           `koanf:"USERAGENT"`
+	Headers   map[string]string `koanf:"HEADERS"`
+	Proxy     *string           `koanf:"PROXY"`
+	CABundle  *string           `koanf:"CABUNDLE"`
+}
+
+func (h HTTP) HasProxy() bool {
+	return h.Proxy != nil && *h.Proxy != ""
+}
+
+func (h HTTP) HasCABundle() bool {
+	return h.CABundle != nil && *h.CABundle != ""
+}
+
+func (l Log) LevelOrDefault() string {
+	if l.Level == nil || *l.Level == "" {
+		return LogLevelInfo
+	}
+	return *l.Level
+}
+
+type ListOverride struct {
+	Privacy  *string `koanf:"PRIVACY"`
+	NoRemove *bool   `koanf:"NOREMOVE"`
+	SortBy   *string `koanf:"SORTBY"`
+	SortHow  *string `koanf:"SORTHOW"`
+
+	MinRating            *int  `koanf:"MINRATING"`
+	RemoveBelowMinRating *bool `koanf:"REMOVEBELOWMINRATING"`
+
+	TraktList *string `koanf:"TRAKTLIST"`
+	Like      *bool   `koanf:"LIKE"`
+
+	Rules         []ListRule `koanf:"RULES"`
+	SkipUnmatched *bool      `koanf:"SKIPUNMATCHED"`
+
+	Archive *string `koanf:"ARCHIVE"`
+}
+
+// ListRule routes the items of an imdb list matching all of its conditions to another trakt list, conditions left empty match every item
+type ListRule struct {
+	TraktList *string  `koanf:"TRAKTLIST"`
+	Types     []string `koanf:"TYPES"`
+	Genres    []string `koanf:"GENRES"`
+	MinYear   *int     `koanf:"MINYEAR"`
+	MaxYear   *int     `koanf:"MAXYEAR"`
+}
+
+// Matches reports whether an item of the given trakt item type, imdb genres and release year satisfies the conditions of the rule
+// an item matches a condition listing multiple values when it matches any of them, items without a year never match a year condition
+func (r ListRule) Matches(itemType string, genres []string, year int) bool {
+	if len(r.Types) != 0 && !slices.Contains(r.Types, itemType) {
+		return false
+	}
+	if len(r.Genres) != 0 && !slices.ContainsFunc(genres, func(genre string) bool {
+		return slices.ContainsFunc(r.Genres, func(ruleGenre string) bool {
+			return strings.EqualFold(ruleGenre, genre)
+		})
+	}) {
+		return false
+	}
+	if r.MinYear != nil && (year == 0 || year < *r.MinYear) {
+		return false
+	}
+	if r.MaxYear != nil && (year == 0 || year > *r.MaxYear) {
+		return false
+	}
+	return true
+}
+
+// listRouteSeparator separates the imdb list id from the trakt list slug in the ids of imdb lists split by rules, imdb list ids never contain it
+const listRouteSeparator = "/"
+
+// RoutedListID returns the id of the part of an imdb list routed to a trakt list by rules, which shares the overrides of the imdb list
+func RoutedListID(listID, slug string) string {
+	return listID + listRouteSeparator + slug
+}
+
+func IsRoutedListID(id string) bool {
+	return strings.Contains(id, listRouteSeparator)
+}
+
+type Lists struct {
+	Privacy          *string                 `koanf:"PRIVACY"`
+	SortBy           *string                 `koanf:"SORTBY"`
+	SortHow          *string                 `koanf:"SORTHOW"`
+	ReconcilePrivacy *bool                   `koanf:"RECONCILEPRIVACY"`
+	ReconcileDetails *bool                   `koanf:"RECONCILEDETAILS"`
+	RemoveEmpty      *bool                   `koanf:"REMOVEEMPTY"`
+	NamePrefix       *string                 `koanf:"NAMEPREFIX"`
+	NameSuffix       *string                 `koanf:"NAMESUFFIX"`
+	WatchlistArchive *string                 `koanf:"WATCHLISTARCHIVE"`
+	WatchlistMirrors []string                `koanf:"WATCHLISTMIRRORS"`
+	TrackingList     *string                 `koanf:"TRACKINGLIST"`
+	Genres           []string                `koanf:"GENRES"`
+	Slug             Slug                    `koanf:"SLUG"`
+	Overrides        map[string]ListOverride `koanf:"OVERRIDES"`
+}
+
+// Slug configures how list names are normalised before the slugs of their trakt lists are inferred from them
+type Slug struct {
+	Transliterate *bool             `koanf:"TRANSLITERATE"`
+	Replacements  map[string]string `koanf:"REPLACEMENTS"`
+}
+
+// ShouldTransliterate reports whether accented letters are replaced by their base letters, which is the default, since trakt does so too
+func (s Slug) ShouldTransliterate() bool {
+	return s.Transliterate == nil || *s.Transliterate
+}
+
+const genreListIDPrefix = "genre:"
+
+// GenreListID returns the id of the list of the rated items of an imdb genre, which is used to look up its overrides like the id of an imdb list
+func GenreListID(genre string) string {
+	return genreListIDPrefix + strings.ToLower(genre)
+}
+
+func IsGenreListID(id string) bool {
+	return strings.HasPrefix(id, genreListIDPrefix)
+}
+
+// HasGenreLists reports whether the rated items are synced to trakt lists named after their imdb genres
+func (l Lists) HasGenreLists() bool {
+	return len(l.Genres) != 0
+}
+
+// override looks up the overrides of an imdb list, ignoring case as environment variable keys are upper case
+func (l Lists) override(listID string) (ListOverride, bool) {
+	listID, _, _ = strings.Cut(listID, listRouteSeparator)
+	for id, override := range l.Overrides {
+		if strings.EqualFold(id, listID) {
+			return override, true
+		}
+	}
+	return ListOverride{}, false
+}
+
+func (l Lists) PrivacyFor(listID string) string {
+	if override, ok := l.override(listID); ok && override.Privacy != nil && *override.Privacy != "" {
+		return *override.Privacy
+	}
+	if l.Privacy != nil && *l.Privacy != "" {
+		return *l.Privacy
+	}
+	return ListPrivacyPrivate
+}
+
+// TraktListName returns the name of the trakt list mirroring an imdb list, decorated with the configured prefix and suffix
+func (l Lists) TraktListName(imdbListName string) string {
+	name := imdbListName
+	if l.NamePrefix != nil {
+		name = *l.NamePrefix + name
+	}
+	if l.NameSuffix != nil {
+		name += *l.NameSuffix
+	}
+	return name
+}
+
+// TraktListFor returns the owner and slug of the trakt list an imdb list is mirrored to, when it is set via list override
+// an empty owner stands for the trakt user, while an empty slug means the slug is inferred from the imdb list name
+func (l Lists) TraktListFor(listID string) (owner, slug string) {
+	if _, slug, routed := strings.Cut(listID, listRouteSeparator); routed {
+		return "", slug
+	}
+	override, ok := l.override(listID)
+	if !ok || override.TraktList == nil {
+		return "", ""
+	}
+	matches := traktListPattern.FindStringSubmatch(strings.TrimSpace(*override.TraktList))
+	if matches == nil {
+		return "", ""
+	}
+	return matches[1], matches[2]
+}
+
+// RulesFor returns the rules routing the items of an imdb list to other trakt lists, in order of precedence
+func (l Lists) RulesFor(listID string) []ListRule {
+	if override, ok := l.override(listID); ok {
+		return override.Rules
+	}
+	return nil
+}
+
+// ShouldSkipUnmatched reports whether the items of an imdb list matching none of its rules are skipped, instead of being synced to its trakt list
+func (l Lists) ShouldSkipUnmatched(listID string) bool {
+	override, ok := l.override(listID)
+	return ok && override.SkipUnmatched != nil && *override.SkipUnmatched
+}
+
+// ArchiveFor returns the slug of the trakt list the items removed from the trakt list mirroring an imdb list are moved to, empty when they are deleted
+func (l Lists) ArchiveFor(listID string) string {
+	if override, ok := l.override(listID); ok && override.Archive != nil {
+		return *override.Archive
+	}
+	return ""
+}
+
+// WatchlistArchiveSlug returns the slug of the trakt list the items removed from the trakt watchlist are moved to, empty when they are deleted
+func (l Lists) WatchlistArchiveSlug() string {
+	if l.WatchlistArchive == nil {
+		return ""
+	}
+	return *l.WatchlistArchive
+}
+
+// TrackingListSlug returns the slug of the private trakt list the items added by the syncer are recorded in, empty when they aren't tracked
+func (l Lists) TrackingListSlug() string {
+	if l.TrackingList == nil {
+		return ""
+	}
+	return *l.TrackingList
+}
+
+// ShouldLike reports whether the trakt list of another user, which an imdb list is mirrored to, should be liked instead
+func (l Lists) ShouldLike(listID string) bool {
+	override, ok := l.override(listID)
+	return ok && override.Like != nil && *override.Like
+}
+
+// SortFor returns the sort settings of the trakt list mirroring an imdb list, empty values mean the sort is not configured
+func (l Lists) SortFor(listID string) (sortBy, sortHow string) {
+	if l.SortBy != nil {
+		sortBy = *l.SortBy
+	}
+	if l.SortHow != nil {
+		sortHow = *l.SortHow
+	}
+	if override, ok := l.override(listID); ok {
+		if override.SortBy != nil && *override.SortBy != "" {
+			sortBy = *override.SortBy
+		}
+		if override.SortHow != nil && *override.SortHow != "" {
+			sortHow = *override.SortHow
+		}
+	}
+	return sortBy, sortHow
+}
+
+// RatingThresholdFor returns the minimum imdb rating of the items synced to the trakt list mirroring an imdb list, 0 means all items are synced
+func (l Lists) RatingThresholdFor(listID string) int {
+	if override, ok := l.override(listID); ok && override.MinRating != nil {
+		return *override.MinRating
+	}
+	return 0
+}
+
+func (l Lists) RemoveBelowMinRatingFor(listID string) bool {
+	override, ok := l.override(listID)
+	return ok && override.RemoveBelowMinRating != nil && *override.RemoveBelowMinRating
+}
+
+// NoRemoveFor reports whether removing items from the trakt list mirroring an imdb list is disabled
+func (l Lists) NoRemoveFor(listID string) bool {
+	override, ok := l.override(listID)
+	return ok && override.NoRemove != nil && *override.NoRemove
+}
+
+func (l Lists) ShouldReconcilePrivacy() bool {
+	return l.ReconcilePrivacy != nil && *l.ReconcilePrivacy
+}
+
+// ShouldReconcileDetails reports whether the name and description of existing trakt lists follow their imdb list
+func (l Lists) ShouldReconcileDetails() bool {
+	return l.ReconcileDetails != nil && *l.ReconcileDetails
+}
+
+func (l Lists) ShouldRemoveEmpty() bool {
+	return l.RemoveEmpty != nil && *l.RemoveEmpty
+}
+
+type Profile struct {
+	Trakt Trakt    `koanf:"TRAKT"`
+	Lists []string `koanf:"LISTS"`
 }
 
 type Config struct {
-	koanf *koanf.Koanf
-	IMDb  IMDb  `koanf:"IMDB"`
-	Trakt Trakt `koanf:"TRAKT"`
-	Sync  Sync  `koanf:"SYNC"`
+	koanf    *koanf.Koanf
+	path     string
+	profile  string
+	warnings []string
+	Version  int                `koanf:"VERSION"`
+	IMDb     IMDb               `koanf:"IMDB"`
+	Trakt    Trakt              `koanf:"TRAKT"`
+	Sync     Sync               `koanf:"SYNC"`
+	Lists    Lists              `koanf:"LISTS"`
+	Notify   Notify             `koanf:"NOTIFY"`
+	Log      Log                `koanf:"LOG"`
+	HTTP     HTTP               `koanf:"HTTP"`
+	Daemon   Daemon             `koanf:"DAEMON"`
+	Profiles map[string]Profile `koanf:"PROFILES"`
 }
 
 const (
 	delimiter = "_"
 	prefix    = "ITS" + delimiter
 
+	LogLevelDebug = "debug"
+	LogLevelError = "error"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+
+	ListPrivacyFriends = "friends"
+	ListPrivacyPrivate = "private"
+	ListPrivacyPublic  = "public"
+
+	ListSortHowAsc  = "asc"
+	ListSortHowDesc = "desc"
+
+	NotifyPresetDiscord = "discord"
+	NotifyPresetGeneric = "generic"
+	NotifyPresetNtfy    = "ntfy"
+
+	RatingsConflictHighest    = "highest"
+	RatingsConflictIMDbWins   = "imdb-wins"
+	RatingsConflictMostRecent = "most-recent"
+	RatingsConflictSkip       = "skip"
+	RatingsConflictTraktWins  = "trakt-wins"
+
+	SyncCategoryCheckIns = "check-ins"
+	SyncCategoryComments = "comments"
+	SyncCategoryHistory  = "history"
+	SyncCategoryLists    = "lists"
+	SyncCategoryRatings  = "ratings"
+	// the watchlist is synced as part of the lists category, it is only distinguished for its sync mode
+	SyncCategoryWatchlist = "watchlist"
+
+	TitleMatchLoose  = "loose"
+	TitleMatchOff    = "off"
+	TitleMatchStrict = "strict"
+
+	IMDbAuthCookies = "cookies"
+	IMDbAuthNone    = "none"
+
+	AccountsConflictHighest    = "highest"
+	AccountsConflictLowest     = "lowest"
+	AccountsConflictMostRecent = "most-recent"
+	AccountsConflictPrimary    = "primary"
+
 	SyncModeAddOnly = "add-only"
 	SyncModeDryRun  = "dry-run"
 	SyncModeFull    = "full"
+
+	PartialFailureFailure = "failure"
+	PartialFailurePartial = "partial"
+	PartialFailureSuccess = "success"
 )
 
 func New(path string, includeEnv bool) (*Config, error) {
@@ -54,17 +882,31 @@ func New(path string, includeEnv bool) (*Config, error) {
 		return nil, fmt.Errorf("error loading config from yaml file: %w", err)
 	}
 	if includeEnv {
+		if err := interpolateEnv(k); err != nil {
+			return nil, fmt.Errorf("error interpolating environment variables in config: %w", err)
+		}
 		envProvider := env.ProviderWithValue(prefix, delimiter, environmentVariableModifier)
 		if err := k.Load(envProvider, nil); err != nil {
 			return nil, fmt.Errorf("error loading config from environment variables: %w", err)
 		}
 	}
+	warnings, err := migrate(k)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating config: %w", err)
+	}
 	conf := Config{
-		koanf: k,
+		koanf:    k,
+		path:     path,
+		warnings: warnings,
 	}
 	if err := k.Unmarshal("", &conf); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
+	if err := conf.loadTraktSecrets(); err != nil {
+		return nil, err
+	}
+	conf.resolveTraktTokensFile()
+	conf.normalizeLists()
 	return &conf, nil
 }
 
@@ -74,22 +916,85 @@ func NewFromMap(data map[string]interface{}) (*Config, error) {
 	if err := k.Load(cmProvider, nil); err != nil {
 		return nil, err
 	}
+	warnings, err := migrate(k)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating config: %w", err)
+	}
 	conf := Config{
-		koanf: k,
+		koanf:    k,
+		warnings: warnings,
 	}
 	if err := k.Unmarshal("", &conf); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
+	conf.normalizeLists()
 	return &conf, nil
 }
 
+// normalizeLists replaces imdb list urls with the ids of the lists, e.g. https://www.imdb.com/list/ls000000000/ becomes ls000000000
+func (c *Config) normalizeLists() {
+	c.IMDb.Lists = normalizeListIDs(c.IMDb.Lists)
+	c.Sync.WatchedLists = normalizeListIDs(c.Sync.WatchedLists)
+	for name, profile := range c.Profiles {
+		profile.Lists = normalizeListIDs(profile.Lists)
+		c.Profiles[name] = profile
+	}
+}
+
+func normalizeListIDs(lists []string) []string {
+	for i, list := range lists {
+		if id := listIDPattern.FindString(list); id != "" {
+			lists[i] = id
+		}
+	}
+	return lists
+}
+
 func (c *Config) Validate() error {
-	if c.IMDb.CookieAtMain == nil {
+	if len(c.Profiles) == 0 {
+		return c.validate()
+	}
+	for _, name := range c.ProfileNames() {
+		profile, err := c.Profile(name)
+		if err != nil {
+			return err
+		}
+		if err = profile.validate(); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	if auth := c.IMDb.Auth; auth != nil && *auth != "" && !slices.Contains(validIMDbAuths(), *auth) {
+		return fmt.Errorf("config field 'IMDB_AUTH' must be one of: %s", strings.Join(validIMDbAuths(), ", "))
+	}
+	if c.IMDb.IsAuthless() && len(c.IMDb.Lists) == 0 {
+		return fmt.Errorf("config field 'IMDB_LISTS' must not be empty when config field 'IMDB_AUTH' is %s, imdb lists can't be discovered anonymously", IMDbAuthNone)
+	}
+	requiresCookies := !c.IMDb.IsOffline() && !c.IMDb.IsAuthless()
+	if c.IMDb.CookieAtMain == nil && requiresCookies {
 		return fmt.Errorf("config field 'IMDB_COOKIEATMAIN' is required")
 	}
-	if c.IMDb.CookieUbidMain == nil {
+	if c.IMDb.CookieUbidMain == nil && requiresCookies {
 		return fmt.Errorf("config field 'IMDB_COOKIEUBIDMAIN' is required")
 	}
+	for _, name := range c.IMDb.AccountNames() {
+		account := c.IMDb.Account(name)
+		if auth := account.Auth; auth != nil && *auth != "" && *auth != IMDbAuthCookies {
+			return fmt.Errorf("config field 'IMDB_ACCOUNTS_%s_AUTH' must be %s, anonymous imdb accounts have no data to merge", name, IMDbAuthCookies)
+		}
+		if !account.IsOffline() && (account.CookieAtMain == nil || account.CookieUbidMain == nil) {
+			return fmt.Errorf("config fields 'IMDB_ACCOUNTS_%s_COOKIEATMAIN' and 'IMDB_ACCOUNTS_%s_COOKIEUBIDMAIN' are required, unless config field 'IMDB_ACCOUNTS_%s_EXPORTSDIR' is set", name, name, name)
+		}
+	}
+	if len(c.IMDb.Accounts) != 0 && c.IMDb.IsAuthless() {
+		return fmt.Errorf("config field 'IMDB_ACCOUNTS' requires config field 'IMDB_AUTH' to be %s, the data of the accounts is merged with the data of the main account", IMDbAuthCookies)
+	}
+	if policy := c.IMDb.AccountsConflict; policy != nil && *policy != "" && !slices.Contains(validAccountsConflictPolicies(), *policy) {
+		return fmt.Errorf("config field 'IMDB_ACCOUNTSCONFLICT' must be one of: %s", strings.Join(validAccountsConflictPolicies(), ", "))
+	}
 	if c.Trakt.Email == nil {
 		return fmt.Errorf("config field 'TRAKT_EMAIL' is required")
 	}
@@ -111,9 +1016,243 @@ func (c *Config) Validate() error {
 	if !slices.Contains(validSyncModes(), *c.Sync.Mode) {
 		return fmt.Errorf("config field 'SYNC_MODE' must be one of: %s", strings.Join(validSyncModes(), ", "))
 	}
+	modes := map[string]*string{
+		"LISTS":     c.Sync.Modes.Lists,
+		"WATCHLIST": c.Sync.Modes.Watchlist,
+		"RATINGS":   c.Sync.Modes.Ratings,
+		"HISTORY":   c.Sync.Modes.History,
+		"COMMENTS":  c.Sync.Modes.Comments,
+	}
+	for field, mode := range modes {
+		if mode == nil || *mode == "" {
+			continue
+		}
+		if !slices.Contains(validSyncModes(), *mode) {
+			return fmt.Errorf("config field 'SYNC_MODES_%s' must be one of: %s", field, strings.Join(validSyncModes(), ", "))
+		}
+		if *c.Sync.Mode == SyncModeDryRun && *mode != SyncModeDryRun {
+			return fmt.Errorf("config field 'SYNC_MODES_%s' must be %s, since config field 'SYNC_MODE' is %s", field, SyncModeDryRun, SyncModeDryRun)
+		}
+	}
+	if maxPercent := c.Sync.Removals.MaxPercent; maxPercent != nil && (*maxPercent < 0 || *maxPercent > 100) {
+		return fmt.Errorf("config field 'SYNC_REMOVALS_MAXPERCENT' must be between 0 and 100")
+	}
+	if c.Sync.Comments.ShouldSyncReviews() && c.IMDb.IsAuthless() {
+		return fmt.Errorf("config field 'SYNC_COMMENTS_REVIEWS' requires config field 'IMDB_AUTH' to be %s, the reviews are part of the imdb ratings", IMDbAuthCookies)
+	}
+	if c.Lists.HasGenreLists() && c.IMDb.IsAuthless() {
+		return fmt.Errorf("config field 'LISTS_GENRES' requires config field 'IMDB_AUTH' to be %s, the genre lists are made of the imdb ratings", IMDbAuthCookies)
+	}
+	if maxCount := c.Sync.Additions.MaxCount; maxCount != nil && *maxCount < 0 {
+		return fmt.Errorf("config field 'SYNC_ADDITIONS_MAXCOUNT' must not be negative")
+	}
+	if maxPercent := c.Sync.Additions.MaxPercent; maxPercent != nil && *maxPercent < 0 {
+		return fmt.Errorf("config field 'SYNC_ADDITIONS_MAXPERCENT' must not be negative")
+	}
+	for _, itemType := range c.Sync.WatchlistTypes {
+		if !slices.Contains(validItemTypes(), itemType) {
+			return fmt.Errorf("config field 'SYNC_WATCHLISTTYPES' must only contain: %s", strings.Join(validItemTypes(), ", "))
+		}
+	}
+	for _, section := range c.Sync.HiddenSections {
+		if !slices.Contains(validHiddenSections(), section) {
+			return fmt.Errorf("config field 'SYNC_HIDDENSECTIONS' must only contain: %s", strings.Join(validHiddenSections(), ", "))
+		}
+	}
+	if minRating := c.Sync.MinRating; minRating != nil && (*minRating < 0 || *minRating > 10) {
+		return fmt.Errorf("config field 'SYNC_MINRATING' must be between 0 and 10")
+	}
+	if maxItems := c.Sync.MaxItems; maxItems != nil && *maxItems < 0 {
+		return fmt.Errorf("config field 'SYNC_MAXITEMS' must not be negative")
+	}
+	if c.HTTP.HasProxy() {
+		if proxyURL, err := url.Parse(*c.HTTP.Proxy); err != nil || !slices.Contains(validProxySchemes(), proxyURL.Scheme) || proxyURL.Host == "" {
+			return fmt.Errorf("config field 'HTTP_PROXY' must be a url with one of the schemes: %s", strings.Join(validProxySchemes(), ", "))
+		}
+	}
+	if interval := c.Daemon.Interval; interval != nil && *interval < 0 {
+		return fmt.Errorf("config field 'DAEMON_INTERVAL' must not be negative")
+	}
+	if c.Daemon.HasSchedule() {
+		if c.Daemon.HasInterval() {
+			return fmt.Errorf("config fields 'DAEMON_INTERVAL' and 'DAEMON_SCHEDULE' must not be set at the same time")
+		}
+		if _, err := cron.Parse(*c.Daemon.Schedule); err != nil {
+			return fmt.Errorf("config field 'DAEMON_SCHEDULE' must be a valid cron expression: %w", err)
+		}
+	}
+	if minRating := c.Sync.WatchlistRemovalMinRating; minRating != nil && (*minRating < 0 || *minRating > 10) {
+		return fmt.Errorf("config field 'SYNC_WATCHLISTREMOVALMINRATING' must be between 0 and 10")
+	}
+	if c.Sync.IsBidirectional() {
+		if rating := c.Sync.WatchedRating; rating == nil || *rating < 1 || *rating > 10 {
+			return fmt.Errorf("config field 'SYNC_WATCHEDRATING' must be between 1 and 10 when config field 'SYNC_BIDIRECTIONAL' is true")
+		}
+		if !requiresCookies {
+			return fmt.Errorf("config field 'SYNC_BIDIRECTIONAL' requires config field 'IMDB_AUTH' to be %s and config field 'IMDB_EXPORTSDIR' to be empty, imdb ratings can't be added otherwise", IMDbAuthCookies)
+		}
+	}
+	if strategy := c.Trakt.TitleMatch; strategy != nil && *strategy != "" && !slices.Contains(validTitleMatchStrategies(), *strategy) {
+		return fmt.Errorf("config field 'TRAKT_TITLEMATCH' must be one of: %s", strings.Join(validTitleMatchStrategies(), ", "))
+	}
+	if delay := c.Trakt.WriteDelay; delay != nil && *delay < 0 {
+		return fmt.Errorf("config field 'TRAKT_WRITEDELAY' must not be negative")
+	}
+	if c.Trakt.TokensFilePath() != "" && c.Trakt.ShouldUseKeyring() {
+		return fmt.Errorf("config field 'TRAKT_TOKENSFILE' must be empty when config field 'TRAKT_KEYRING' is true, the trakt tokens are kept in one place only")
+	}
+	if size := c.Sync.HistoryBatchSize; size != nil && *size < 0 {
+		return fmt.Errorf("config field 'SYNC_HISTORYBATCHSIZE' must not be negative")
+	}
+	if policy := c.Sync.PartialFailure; policy != nil && *policy != "" && !slices.Contains(validPartialFailurePolicies(), *policy) {
+		return fmt.Errorf("config field 'SYNC_PARTIALFAILURE' must be one of: %s", strings.Join(validPartialFailurePolicies(), ", "))
+	}
+	if policy := c.Sync.RatingsConflict; policy != nil && *policy != "" && !slices.Contains(validRatingsConflictPolicies(), *policy) {
+		return fmt.Errorf("config field 'SYNC_RATINGSCONFLICT' must be one of: %s", strings.Join(validRatingsConflictPolicies(), ", "))
+	}
+	if level := c.Log.Level; level != nil && *level != "" && !slices.Contains(ValidLogLevels(), *level) {
+		return fmt.Errorf("config field 'LOG_LEVEL' must be one of: %s", strings.Join(ValidLogLevels(), ", "))
+	}
+	if privacy := c.Lists.Privacy; privacy != nil && *privacy != "" && !slices.Contains(validListPrivacies(), *privacy) {
+		return fmt.Errorf("config field 'LISTS_PRIVACY' must be one of: %s", strings.Join(validListPrivacies(), ", "))
+	}
+	if sortBy := c.Lists.SortBy; sortBy != nil && *sortBy != "" && !slices.Contains(validListSortBys(), *sortBy) {
+		return fmt.Errorf("config field 'LISTS_SORTBY' must be one of: %s", strings.Join(validListSortBys(), ", "))
+	}
+	if sortHow := c.Lists.SortHow; sortHow != nil && *sortHow != "" && !slices.Contains(validListSortHows(), *sortHow) {
+		return fmt.Errorf("config field 'LISTS_SORTHOW' must be one of: %s", strings.Join(validListSortHows(), ", "))
+	}
+	if archive := c.Lists.WatchlistArchive; archive != nil && *archive != "" && !traktSlugPattern.MatchString(*archive) {
+		return fmt.Errorf("config field 'LISTS_WATCHLISTARCHIVE' must be the slug of a trakt list")
+	}
+	for _, mirror := range c.Lists.WatchlistMirrors {
+		if !traktSlugPattern.MatchString(mirror) {
+			return fmt.Errorf("config field 'LISTS_WATCHLISTMIRRORS' must only contain slugs of trakt lists")
+		}
+		if mirror == c.Lists.WatchlistArchiveSlug() {
+			return fmt.Errorf("config field 'LISTS_WATCHLISTMIRRORS' must not contain the slug of config field 'LISTS_WATCHLISTARCHIVE'")
+		}
+	}
+	if tracking := c.Lists.TrackingListSlug(); tracking != "" {
+		if !traktSlugPattern.MatchString(tracking) {
+			return fmt.Errorf("config field 'LISTS_TRACKINGLIST' must be the slug of a trakt list")
+		}
+		if tracking == c.Lists.WatchlistArchiveSlug() || slices.Contains(c.Lists.WatchlistMirrors, tracking) {
+			return fmt.Errorf("config field 'LISTS_TRACKINGLIST' must be a trakt list of its own, rather than the watchlist archive or a watchlist mirror")
+		}
+	}
+	for id, override := range c.Lists.Overrides {
+		if privacy := override.Privacy; privacy != nil && *privacy != "" && !slices.Contains(validListPrivacies(), *privacy) {
+			return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_PRIVACY' must be one of: %s", id, strings.Join(validListPrivacies(), ", "))
+		}
+		if sortBy := override.SortBy; sortBy != nil && *sortBy != "" && !slices.Contains(validListSortBys(), *sortBy) {
+			return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_SORTBY' must be one of: %s", id, strings.Join(validListSortBys(), ", "))
+		}
+		if sortHow := override.SortHow; sortHow != nil && *sortHow != "" && !slices.Contains(validListSortHows(), *sortHow) {
+			return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_SORTHOW' must be one of: %s", id, strings.Join(validListSortHows(), ", "))
+		}
+		if traktList := override.TraktList; traktList != nil && *traktList != "" && !traktListPattern.MatchString(strings.TrimSpace(*traktList)) {
+			return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_TRAKTLIST' must be a trakt list slug, username/slug or trakt list url", id)
+		}
+		if minRating := override.MinRating; minRating != nil {
+			if *minRating < 0 || *minRating > 10 {
+				return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_MINRATING' must be between 0 and 10", id)
+			}
+			if *minRating > 0 && c.IMDb.IsAuthless() {
+				return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_MINRATING' requires imdb ratings, which can't be fetched when config field 'IMDB_AUTH' is %s", id, IMDbAuthNone)
+			}
+		}
+		if archive := override.Archive; archive != nil && *archive != "" && !traktSlugPattern.MatchString(*archive) {
+			return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_ARCHIVE' must be the slug of a trakt list", id)
+		}
+		for i, rule := range override.Rules {
+			if rule.TraktList == nil || !traktSlugPattern.MatchString(*rule.TraktList) {
+				return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_RULES_%d_TRAKTLIST' must be the slug of a trakt list", id, i)
+			}
+			for _, itemType := range rule.Types {
+				if !slices.Contains(validItemTypes(), itemType) {
+					return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_RULES_%d_TYPES' must only contain: %s", id, i, strings.Join(validItemTypes(), ", "))
+				}
+			}
+			if rule.MinYear != nil && rule.MaxYear != nil && *rule.MinYear > *rule.MaxYear {
+				return fmt.Errorf("config field 'LISTS_OVERRIDES_%s_RULES_%d_MINYEAR' must not be greater than 'LISTS_OVERRIDES_%s_RULES_%d_MAXYEAR'", id, i, id, i)
+			}
+		}
+	}
+	if preset := c.Notify.Preset; preset != nil && *preset != "" && !slices.Contains(validNotifyPresets(), *preset) {
+		return fmt.Errorf("config field 'NOTIFY_PRESET' must be one of: %s", strings.Join(validNotifyPresets(), ", "))
+	}
 	return nil
 }
 
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Profile resolves the named profile into a standalone config.
+// Trakt fields and imdb lists set on the profile take precedence over the top level ones, as do the credentials of its trakt secrets file.
+// The trakt tokens of every profile are kept apart, the top level tokens file gets the profile name appended unless the profile sets its own.
+func (c *Config) Profile(name string) (*Config, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %s is not configured, available profiles: %s", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	conf := *c
+	conf.profile = name
+	conf.Profiles = nil
+	if p.Trakt.Email != nil {
+		conf.Trakt.Email = p.Trakt.Email
+	}
+	if p.Trakt.Password != nil {
+		conf.Trakt.Password = p.Trakt.Password
+	}
+	if p.Trakt.ClientID != nil {
+		conf.Trakt.ClientID = p.Trakt.ClientID
+	}
+	if p.Trakt.ClientSecret != nil {
+		conf.Trakt.ClientSecret = p.Trakt.ClientSecret
+	}
+	if p.Trakt.SecretsFile != nil {
+		conf.Trakt.SecretsFile = p.Trakt.SecretsFile
+		if err := conf.loadTraktSecrets(); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	if p.Trakt.TokensFile != nil {
+		conf.Trakt.TokensFile = p.Trakt.TokensFile
+		conf.resolveTraktTokensFile()
+	} else if path := conf.Trakt.TokensFilePath(); path != "" {
+		tokensFile := profilePath(path, name)
+		conf.Trakt.TokensFile = &tokensFile
+	}
+	if p.Trakt.Keyring != nil {
+		conf.Trakt.Keyring = p.Trakt.Keyring
+	}
+	if p.Lists != nil {
+		conf.IMDb.Lists = p.Lists
+	}
+	return &conf, nil
+}
+
+// Path returns the path of the file the config was loaded from, which is empty for configs not loaded from a file
+func (c *Config) Path() string {
+	return c.path
+}
+
+func (c *Config) ProfileName() string {
+	return c.profile
+}
+
+// Warnings describes the deprecated fields found while loading the config
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
 func (c *Config) WriteFile(path string) error {
 	data, err := c.koanf.Marshal(yaml.Parser())
 	if err != nil {
@@ -126,6 +1265,70 @@ func (c *Config) Flatten() map[string]interface{} {
 	return c.koanf.All()
 }
 
+// validItemTypes returns the trakt item types imdb items are converted to
+func validItemTypes() []string {
+	return []string{
+		"movie",
+		"show",
+		"episode",
+	}
+}
+
+// validHiddenSections are the trakt sections items can be hidden from, see https://trakt.docs.apiary.io/#reference/users/hidden-items
+func validHiddenSections() []string {
+	return []string{
+		"calendar",
+		"progress_watched",
+		"progress_collected",
+		"recommendations",
+		"dropped",
+	}
+}
+
+func validRatingsConflictPolicies() []string {
+	return []string{
+		RatingsConflictIMDbWins,
+		RatingsConflictTraktWins,
+		RatingsConflictHighest,
+		RatingsConflictMostRecent,
+		RatingsConflictSkip,
+	}
+}
+
+func validAccountsConflictPolicies() []string {
+	return []string{
+		AccountsConflictPrimary,
+		AccountsConflictHighest,
+		AccountsConflictLowest,
+		AccountsConflictMostRecent,
+	}
+}
+
+func validTitleMatchStrategies() []string {
+	return []string{
+		TitleMatchOff,
+		TitleMatchStrict,
+		TitleMatchLoose,
+	}
+}
+
+func validIMDbAuths() []string {
+	return []string{
+		IMDbAuthCookies,
+		IMDbAuthNone,
+	}
+}
+
+func validSyncCategories() []string {
+	return []string{
+		SyncCategoryLists,
+		SyncCategoryRatings,
+		SyncCategoryCheckIns,
+		SyncCategoryHistory,
+		SyncCategoryComments,
+	}
+}
+
 func validSyncModes() []string {
 	return []string{
 		SyncModeFull,
@@ -134,7 +1337,77 @@ func validSyncModes() []string {
 	}
 }
 
+func validPartialFailurePolicies() []string {
+	return []string{
+		PartialFailurePartial,
+		PartialFailureFailure,
+		PartialFailureSuccess,
+	}
+}
+
+func ValidLogLevels() []string {
+	return []string{
+		LogLevelDebug,
+		LogLevelInfo,
+		LogLevelWarn,
+		LogLevelError,
+	}
+}
+
+func validListPrivacies() []string {
+	return []string{
+		ListPrivacyPrivate,
+		ListPrivacyFriends,
+		ListPrivacyPublic,
+	}
+}
+
+// validListSortBys mirrors the sort_by values accepted by the trakt create/update list api
+func validListSortBys() []string {
+	return []string{
+		"rank",
+		"added",
+		"title",
+		"released",
+		"runtime",
+		"popularity",
+		"percentage",
+		"votes",
+		"my_rating",
+		"random",
+		"watched",
+		"collected",
+	}
+}
+
+func validListSortHows() []string {
+	return []string{
+		ListSortHowAsc,
+		ListSortHowDesc,
+	}
+}
+
+func validProxySchemes() []string {
+	return []string{
+		"http",
+		"https",
+		"socks5",
+	}
+}
+
+func validNotifyPresets() []string {
+	return []string{
+		NotifyPresetGeneric,
+		NotifyPresetDiscord,
+		NotifyPresetNtfy,
+	}
+}
+
 func environmentVariableModifier(key string, value string) (string, any) {
+	if key == envNameConfigFile {
+		// locates the config file, rather than setting a config field
+		return "", nil
+	}
 	key = strings.TrimPrefix(key, prefix)
 	if value == "" {
 		return key, nil
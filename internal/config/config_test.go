@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/knadh/koanf/v2"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +29,7 @@ TRAKT:
 SYNC:
   MODE: dry-run
   SKIPHISTORY: true
+  TIMEOUT: 30m
 `
 	type args struct {
 		includeEnv bool
@@ -57,6 +61,7 @@ SYNC:
 				assertions.NotEmpty(config.Trakt.ClientSecret)
 				assertions.NotEmpty(config.Sync.Mode)
 				assertions.NotEmpty(config.Sync.SkipHistory)
+				assertions.Equal(30*time.Minute, *config.Sync.Timeout)
 			},
 		},
 		{
@@ -84,6 +89,108 @@ SYNC:
 				assertions.NotEmpty(config.Sync.SkipHistory)
 			},
 		},
+		{
+			name: "success interpolating environment variables in config file",
+			args: args{
+				includeEnv: true,
+			},
+			requirements: func(t *testing.T, path string) {
+				config := strings.ReplaceAll(dummyConfig, "PASSWORD: password", "PASSWORD: pre-${TEST_TRAKT_PASSWORD}-post")
+				config = strings.ReplaceAll(config, "- ls111111111", "- ${TEST_IMDB_LIST}")
+				err := os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+				t.Setenv("TEST_TRAKT_PASSWORD", "secret")
+				t.Setenv("TEST_IMDB_LIST", "ls222222222")
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.NotNil(config)
+				assertions.Equal("pre-secret-post", *config.Trakt.Password)
+				assertions.Equal([]string{"ls000000000", "ls222222222"}, config.IMDb.Lists)
+			},
+		},
+		{
+			name: "success loading trakt credentials from secrets file",
+			args: args{
+				includeEnv: false,
+			},
+			requirements: func(t *testing.T, path string) {
+				secrets := "CLIENTSECRET: file-secret\nPASSWORD: file-password\n"
+				err := os.WriteFile(filepath.Join(filepath.Dir(path), "secrets.yaml"), []byte(secrets), 0600)
+				require.Nil(t, err)
+				config := strings.ReplaceAll(dummyConfig, "TRAKT:\n", "TRAKT:\n  SECRETSFILE: secrets.yaml\n")
+				err = os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.Equal("file-password", *config.Trakt.Password)
+				assertions.Equal("file-secret", *config.Trakt.ClientSecret)
+				assertions.Equal("user@domain.com", *config.Trakt.Email)
+				assertions.Equal("password", config.koanf.String("TRAKT_PASSWORD"))
+			},
+		},
+		{
+			name: "success resolving relative trakt tokens file against the config directory",
+			args: args{
+				includeEnv: false,
+			},
+			requirements: func(t *testing.T, path string) {
+				config := strings.ReplaceAll(dummyConfig, "TRAKT:\n", "TRAKT:\n  TOKENSFILE: tokens.json\n")
+				err := os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.Equal(filepath.Join(filepath.Dir(config.Path()), "tokens.json"), config.Trakt.TokensFilePath())
+				assertions.False(config.Trakt.ShouldUseKeyring())
+			},
+		},
+		{
+			name: "failure loading missing trakt secrets file",
+			args: args{
+				includeEnv: false,
+			},
+			requirements: func(t *testing.T, path string) {
+				config := strings.ReplaceAll(dummyConfig, "TRAKT:\n", "TRAKT:\n  SECRETSFILE: missing.yaml\n")
+				err := os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(config)
+				assertions.ErrorContains(err, "error loading trakt secrets from file")
+			},
+		},
+		{
+			name: "failure interpolating unset environment variable",
+			args: args{
+				includeEnv: true,
+			},
+			requirements: func(t *testing.T, path string) {
+				config := strings.ReplaceAll(dummyConfig, "PASSWORD: password", "PASSWORD: ${TEST_UNSET_VARIABLE}")
+				err := os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(config)
+				assertions.ErrorContains(err, "config field 'TRAKT_PASSWORD' references environment variable(s) that are not set: TEST_UNSET_VARIABLE")
+			},
+		},
+		{
+			name: "keep environment variable references when excluding env vars",
+			args: args{
+				includeEnv: false,
+			},
+			requirements: func(t *testing.T, path string) {
+				config := strings.ReplaceAll(dummyConfig, "PASSWORD: password", "PASSWORD: ${TEST_UNSET_VARIABLE}")
+				err := os.WriteFile(path, []byte(config), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.Equal("${TEST_UNSET_VARIABLE}", *config.Trakt.Password)
+			},
+		},
 		{
 			name: "invalid config file path",
 			args: args{
@@ -124,9 +231,13 @@ SYNC:
 
 func TestConfig_Validate(t *testing.T) {
 	type fields struct {
-		IMDb  IMDb
-		Trakt Trakt
-		Sync  Sync
+		IMDb   IMDb
+		Trakt  Trakt
+		Sync   Sync
+		Notify Notify
+		HTTP   HTTP
+		Daemon Daemon
+		Lists  Lists
 	}
 	tests := []struct {
 		name       string
@@ -158,6 +269,167 @@ func TestConfig_Validate(t *testing.T) {
 				assertions.Nil(err)
 			},
 		},
+		{
+			name: "success without cookies when exports directory is set",
+			fields: fields{
+				IMDb: IMDb{
+					ExportsDir: func() *string {
+						s := "exports"
+						return &s
+					}(),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "success without cookies when imdb auth is none",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "missing IMDb.Lists when imdb auth is none",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.ErrorContains(err, "config field 'IMDB_LISTS' must not be empty")
+			},
+		},
+		{
+			name: "invalid IMDb.Auth",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := "password"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.ErrorContains(err, "config field 'IMDB_AUTH' must be one of")
+			},
+		},
+		{
+			name: "anonymous IMDb.Accounts",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+					Accounts: map[string]IMDbAccount{
+						"SHARED": {
+							Auth: func() *string {
+								s := IMDbAuthNone
+								return &s
+							}(),
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "config field 'IMDB_ACCOUNTS_SHARED_AUTH' must be cookies")
+			},
+		},
+		{
+			name: "missing IMDb.Accounts cookies",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+					Accounts: map[string]IMDbAccount{
+						"SHARED": {
+							CookieAtMain: new(string),
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "'IMDB_ACCOUNTS_SHARED_COOKIEUBIDMAIN' are required")
+			},
+		},
+		{
+			name: "IMDb.Accounts without IMDb authentication",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+					Accounts: map[string]IMDbAccount{
+						"SHARED": {
+							ExportsDir: func() *string {
+								s := "exports"
+								return &s
+							}(),
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "config field 'IMDB_ACCOUNTS' requires config field 'IMDB_AUTH' to be cookies")
+			},
+		},
+		{
+			name: "invalid IMDb.AccountsConflict",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+					AccountsConflict: func() *string {
+						s := "invalid"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "config field 'IMDB_ACCOUNTSCONFLICT' must be one of")
+			},
+		},
 		{
 			name: "missing IMDb.CookieAtMain",
 			fields: fields{
@@ -327,156 +599,1867 @@ func TestConfig_Validate(t *testing.T) {
 				assertions.Contains(err.Error(), "SYNC_MODE")
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := &Config{
-				IMDb:  tt.fields.IMDb,
-				Trakt: tt.fields.Trakt,
-				Sync:  tt.fields.Sync,
-			}
-			tt.assertions(assert.New(t), c.Validate())
-		})
-	}
-}
-
-func Test_environmentVariableModifier(t *testing.T) {
-	type args struct {
-		key   string
-		value string
-	}
-	tests := []struct {
-		name       string
-		args       args
-		assertions func(*assert.Assertions, string, any)
-	}{
-		{
-			name: "empty value",
-			args: args{
-				key:   "key",
-				value: "",
-			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Nil(value)
-			},
-		},
 		{
-			name: "single value",
-			args: args{
-				key:   "key",
-				value: "value1",
+			name: "invalid Notify.Preset",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Notify: Notify{
+					Preset: func() *string {
+						s := "invalid"
+						return &s
+					}(),
+				},
 			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Equal("value1", value)
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "NOTIFY_PRESET")
 			},
 		},
 		{
-			name: "multiple values",
-			args: args{
-				key:   "key",
-				value: "value1,value2",
-			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Equal([]string{
-					"value1",
-					"value2",
+			name: "invalid Sync.PartialFailure",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					PartialFailure: func() *string {
+						s := "invalid"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_PARTIALFAILURE")
+			},
+		},
+		{
+			name: "invalid Sync.RatingsConflict",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					RatingsConflict: func() *string {
+						s := "invalid"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_RATINGSCONFLICT")
+			},
+		},
+		{
+			name: "invalid Sync.Removals.MaxPercent",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					Removals: Removals{
+						MaxPercent: func() *int {
+							i := 150
+							return &i
+						}(),
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_REMOVALS_MAXPERCENT")
+			},
+		},
+		{
+			name: "failure validating comment reviews without imdb authentication",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					Comments: Comments{
+						Reviews: func() *bool {
+							b := true
+							return &b
+						}(),
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_COMMENTS_REVIEWS")
+			},
+		},
+		{
+			name: "failure validating genre lists without imdb authentication",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Lists: Lists{
+					Genres: []string{"Horror"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "LISTS_GENRES")
+			},
+		},
+		{
+			name: "failure validating watchlist mirror matching the watchlist archive",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Lists: Lists{
+					WatchlistArchive: func() *string {
+						s := "watched"
+						return &s
+					}(),
+					WatchlistMirrors: []string{"shared-watchlist", "watched"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "LISTS_WATCHLISTMIRRORS")
+			},
+		},
+		{
+			name: "failure validating tracking list matching a watchlist mirror",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Lists: Lists{
+					WatchlistMirrors: []string{"shared-watchlist"},
+					TrackingList: func() *string {
+						s := "shared-watchlist"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "LISTS_TRACKINGLIST")
+			},
+		},
+		{
+			name: "invalid Sync.Additions.MaxCount",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					Additions: Additions{
+						MaxCount: func() *int {
+							i := -1
+							return &i
+						}(),
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_ADDITIONS_MAXCOUNT")
+			},
+		},
+		{
+			name: "failure validating trakt tokens file combined with keyring",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+					TokensFile: func() *string {
+						s := "tokens.json"
+						return &s
+					}(),
+					Keyring: func() *bool {
+						b := true
+						return &b
+					}(),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_TOKENSFILE")
+			},
+		},
+		{
+			name: "failure validating negative trakt write delay",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+					WriteDelay: func() *time.Duration {
+						d := -time.Second
+						return &d
+					}(),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_WRITEDELAY")
+			},
+		},
+		{
+			name: "failure validating negative history batch size",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					HistoryBatchSize: func() *int {
+						i := -1
+						return &i
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_HISTORYBATCHSIZE")
+			},
+		},
+		{
+			name: "failure validating bidirectional sync without watched rating",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					Bidirectional: func() *bool {
+						b := true
+						return &b
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_WATCHEDRATING")
+			},
+		},
+		{
+			name: "failure validating bidirectional sync without imdb authentication",
+			fields: fields{
+				IMDb: IMDb{
+					Auth: func() *string {
+						s := IMDbAuthNone
+						return &s
+					}(),
+					Lists: []string{"ls000000000"},
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					Bidirectional: func() *bool {
+						b := true
+						return &b
+					}(),
+					WatchedRating: func() *int {
+						i := 6
+						return &i
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_BIDIRECTIONAL")
+			},
+		},
+		{
+			name: "failure validating sync watchlist removal min rating",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					WatchlistRemovalMinRating: func() *int {
+						i := -1
+						return &i
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_WATCHLISTREMOVALMINRATING")
+			},
+		},
+		{
+			name: "failure validating negative sync max items",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					MaxItems: func() *int {
+						i := -1
+						return &i
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MAXITEMS")
+			},
+		},
+		{
+			name: "failure validating http proxy",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				HTTP: HTTP{
+					Proxy: func() *string {
+						s := "proxy.corp.example:3128"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_PROXY")
+			},
+		},
+		{
+			name: "failure validating negative daemon interval",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Daemon: Daemon{
+					Interval: func() *time.Duration {
+						d := -time.Hour
+						return &d
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "DAEMON_INTERVAL")
+			},
+		},
+		{
+			name: "failure validating daemon schedule",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+				},
+				Daemon: Daemon{
+					Schedule: func() *string {
+						s := "every 6 hours"
+						return &s
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "DAEMON_SCHEDULE")
+			},
+		},
+		{
+			name: "failure validating sync min rating",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory: new(bool),
+					MinRating: func() *int {
+						i := 11
+						return &i
+					}(),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MINRATING")
+			},
+		},
+		{
+			name: "failure validating sync mode override in dry-run sync mode",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeDryRun
+						return &s
+					}(),
+					Modes: Modes{
+						Ratings: func() *string {
+							s := SyncModeFull
+							return &s
+						}(),
+					},
+					SkipHistory: new(bool),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MODES_RATINGS")
+			},
+		},
+		{
+			name: "failure validating sync watchlist types",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory:    new(bool),
+					WatchlistTypes: []string{"movie", "season"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_WATCHLISTTYPES")
+			},
+		},
+		{
+			name: "failure validating sync hidden sections",
+			fields: fields{
+				IMDb: IMDb{
+					CookieAtMain:   new(string),
+					CookieUbidMain: new(string),
+				},
+				Trakt: Trakt{
+					Email:        new(string),
+					Password:     new(string),
+					ClientID:     new(string),
+					ClientSecret: new(string),
+				},
+				Sync: Sync{
+					Mode: func() *string {
+						s := SyncModeFull
+						return &s
+					}(),
+					SkipHistory:    new(bool),
+					HiddenSections: []string{"recommendations", "watchlist"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_HIDDENSECTIONS")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				IMDb:   tt.fields.IMDb,
+				Trakt:  tt.fields.Trakt,
+				Sync:   tt.fields.Sync,
+				Notify: tt.fields.Notify,
+				HTTP:   tt.fields.HTTP,
+				Daemon: tt.fields.Daemon,
+				Lists:  tt.fields.Lists,
+			}
+			tt.assertions(assert.New(t), c.Validate())
+		})
+	}
+}
+
+func Test_environmentVariableModifier(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, any)
+	}{
+		{
+			name: "empty value",
+			args: args{
+				key:   "key",
+				value: "",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Nil(value)
+			},
+		},
+		{
+			name: "single value",
+			args: args{
+				key:   "key",
+				value: "value1",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Equal("value1", value)
+			},
+		},
+		{
+			name: "multiple values",
+			args: args{
+				key:   "key",
+				value: "value1,value2",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Equal([]string{
+					"value1",
+					"value2",
 				}, value)
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			key, value := environmentVariableModifier(tt.args.key, tt.args.value)
-			tt.assertions(assert.New(t), key, value)
+			key, value := environmentVariableModifier(tt.args.key, tt.args.value)
+			tt.assertions(assert.New(t), key, value)
+		})
+	}
+}
+
+func TestConfig_WriteFile(t *testing.T) {
+	type fields struct {
+		koanf *koanf.Koanf
+	}
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "success",
+			fields: fields{
+				koanf: koanf.New("."),
+			},
+			args: args{
+				path: "config.yaml",
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				koanf: tt.fields.koanf,
+			}
+			tt.args.path = fmt.Sprintf("%s/%s", t.TempDir(), tt.args.path)
+			tt.assertions(assert.New(t), c.WriteFile(tt.args.path))
+		})
+	}
+}
+
+func TestNewFromMap(t *testing.T) {
+	type args struct {
+		data map[string]interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       *Config
+		assertions func(*assert.Assertions, *Config, error)
+	}{
+		{
+			name: "success",
+			args: args{
+				data: map[string]interface{}{
+					"IMDB": map[string]interface{}{
+						"COOKIEATMAIN": "xXx",
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.NotNil(config)
+				assertions.NotNil(config.IMDb.CookieAtMain)
+				assertions.Equal("xXx", *config.IMDb.CookieAtMain)
+			},
+		},
+		{
+			name: "normalize imdb list urls to list ids",
+			args: args{
+				data: map[string]interface{}{
+					"IMDB": map[string]interface{}{
+						"LISTS": []interface{}{"https://www.imdb.com/list/ls000000000/", "ls111111111"},
+					},
+					"PROFILES": map[string]interface{}{
+						"FAMILY": map[string]interface{}{
+							"LISTS": []interface{}{"www.imdb.com/list/ls222222222/?ref_=uspf_t_1"},
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.Equal([]string{"ls000000000", "ls111111111"}, config.IMDb.Lists)
+				assertions.Equal([]string{"ls222222222"}, config.Profiles["FAMILY"].Lists)
+			},
+		},
+		{
+			name: "invalid config",
+			args: args{
+				data: map[string]interface{}{
+					"SYNC": map[string]interface{}{
+						"SKIPHISTORY": "invalid",
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.NotNil(err)
+				assertions.Nil(config)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, err := NewFromMap(tt.args.data)
+			tt.assertions(assert.New(t), conf, err)
+		})
+	}
+}
+
+func TestConfig_Profile(t *testing.T) {
+	dummyConfig := `---
+IMDB:
+  COOKIEATMAIN: xXx
+  COOKIEUBIDMAIN: xXx
+  LISTS:
+    - ls000000000
+TRAKT:
+  EMAIL: user@domain.com
+  PASSWORD: password
+  CLIENTID: xXx
+  CLIENTSECRET: xXx
+SYNC:
+  MODE: dry-run
+  SKIPHISTORY: true
+PROFILES:
+  FAMILY:
+    TRAKT:
+      EMAIL: family@domain.com
+    LISTS:
+      - ls111111111
+  WORK:
+    TRAKT:
+      PASSWORD: secret
+`
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, *Config, error)
+	}{
+		{
+			name: "successfully resolve profile with overrides",
+			args: args{
+				name: "FAMILY",
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.NoError(err)
+				assertions.Equal("FAMILY", config.ProfileName())
+				assertions.Equal("family@domain.com", *config.Trakt.Email)
+				assertions.Equal("password", *config.Trakt.Password)
+				assertions.Equal([]string{"ls111111111"}, config.IMDb.Lists)
+				assertions.Nil(config.Profiles)
+				assertions.NoError(config.Validate())
+			},
+		},
+		{
+			name: "successfully resolve profile with fallbacks",
+			args: args{
+				name: "WORK",
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.NoError(err)
+				assertions.Equal("user@domain.com", *config.Trakt.Email)
+				assertions.Equal("secret", *config.Trakt.Password)
+				assertions.Equal([]string{"ls000000000"}, config.IMDb.Lists)
+			},
+		},
+		{
+			name: "handle unknown profile",
+			args: args{
+				name: "UNKNOWN",
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(config)
+				assertions.ErrorContains(err, "FAMILY, WORK")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := fmt.Sprintf("%s/config.yaml", t.TempDir())
+			require.NoError(t, os.WriteFile(path, []byte(dummyConfig), 0644))
+			conf, err := New(path, false)
+			require.NoError(t, err)
+			require.NoError(t, conf.Validate())
+			profile, err := conf.Profile(tt.args.name)
+			tt.assertions(assert.New(t), profile, err)
+		})
+	}
+}
+
+func TestConfig_Profile_traktFiles(t *testing.T) {
+	dummyConfig := `---
+IMDB:
+  COOKIEATMAIN: xXx
+  COOKIEUBIDMAIN: xXx
+TRAKT:
+  EMAIL: user@domain.com
+  PASSWORD: password
+  CLIENTID: xXx
+  CLIENTSECRET: xXx
+  TOKENSFILE: tokens.json
+SYNC:
+  MODE: dry-run
+  SKIPHISTORY: true
+PROFILES:
+  FAMILY:
+    TRAKT:
+      EMAIL: family@domain.com
+  WORK:
+    TRAKT:
+      SECRETSFILE: work-secrets.yaml
+      TOKENSFILE: work/tokens.json
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(dummyConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "work-secrets.yaml"), []byte("EMAIL: work@domain.com\nPASSWORD: work-password\n"), 0600))
+	conf, err := New(path, false)
+	require.NoError(t, err)
+	assertions := assert.New(t)
+	family, err := conf.Profile("FAMILY")
+	assertions.NoError(err)
+	assertions.Equal("family@domain.com", *family.Trakt.Email)
+	assertions.Equal(filepath.Join(dir, "tokens-FAMILY.json"), family.Trakt.TokensFilePath())
+	work, err := conf.Profile("WORK")
+	assertions.NoError(err)
+	assertions.Equal("work@domain.com", *work.Trakt.Email)
+	assertions.Equal("work-password", *work.Trakt.Password)
+	assertions.Equal(filepath.Join(dir, "work", "tokens.json"), work.Trakt.TokensFilePath())
+	assertions.Equal(filepath.Join(dir, "tokens.json"), conf.Trakt.TokensFilePath())
+	assertions.NoError(conf.Validate())
+}
+
+func TestIMDb_RestrictLists(t *testing.T) {
+	type fields struct {
+		imdb IMDb
+	}
+	type args struct {
+		listIDs []string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, IMDb, error)
+	}{
+		{
+			name: "keep lists when no lists are requested",
+			fields: fields{
+				imdb: IMDb{
+					Lists: []string{"ls000000000", "ls111111111"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, imdb IMDb, err error) {
+				assertions.NoError(err)
+				assertions.False(imdb.IsRestricted())
+				assertions.Len(imdb.Lists, 2)
+			},
+		},
+		{
+			name: "restrict to configured list",
+			fields: fields{
+				imdb: IMDb{
+					Lists: []string{"ls000000000", "ls111111111"},
+				},
+			},
+			args: args{
+				listIDs: []string{"ls111111111"},
+			},
+			assertions: func(assertions *assert.Assertions, imdb IMDb, err error) {
+				assertions.NoError(err)
+				assertions.True(imdb.IsRestricted())
+				assertions.Equal([]string{"ls111111111"}, imdb.Lists)
+			},
+		},
+		{
+			name: "allow any list when all lists are synced",
+			args: args{
+				listIDs: []string{"ls222222222"},
+			},
+			assertions: func(assertions *assert.Assertions, imdb IMDb, err error) {
+				assertions.NoError(err)
+				assertions.True(imdb.IsRestricted())
+				assertions.Equal([]string{"ls222222222"}, imdb.Lists)
+			},
+		},
+		{
+			name: "fail when list is not configured",
+			fields: fields{
+				imdb: IMDb{
+					Lists: []string{"ls000000000"},
+				},
+			},
+			args: args{
+				listIDs: []string{"ls222222222"},
+			},
+			assertions: func(assertions *assert.Assertions, imdb IMDb, err error) {
+				assertions.ErrorContains(err, "imdb list ls222222222 is not configured")
+				assertions.False(imdb.IsRestricted())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imdb := tt.fields.imdb
+			err := imdb.RestrictLists(tt.args.listIDs)
+			tt.assertions(assert.New(t), imdb, err)
+		})
+	}
+}
+
+func TestIMDb_Account(t *testing.T) {
+	str := func(s string) *string {
+		return &s
+	}
+	imdb := IMDb{
+		CookieAtMain:   str("main-at"),
+		CookieUbidMain: str("main-ubid"),
+		Lists:          []string{"ls000000000"},
+		Accounts: map[string]IMDbAccount{
+			"SHARED": {
+				CookieAtMain:   str("shared-at"),
+				CookieUbidMain: str("shared-ubid"),
+			},
+			"KIDS": {
+				ExportsDir: str("exports"),
+			},
+		},
+	}
+	assertions := assert.New(t)
+	assertions.Equal([]string{"KIDS", "SHARED"}, imdb.AccountNames())
+	shared := imdb.Account("SHARED")
+	assertions.Equal("shared-at", *shared.CookieAtMain)
+	assertions.Equal("shared-ubid", *shared.CookieUbidMain)
+	assertions.Equal([]string{"ls000000000"}, shared.Lists)
+	assertions.Nil(shared.Accounts)
+	assertions.False(shared.IsOffline())
+	kids := imdb.Account("KIDS")
+	assertions.True(kids.IsOffline())
+	assertions.Nil(kids.CookieAtMain)
+	assertions.Equal("main-at", *imdb.CookieAtMain)
+	assertions.Equal(AccountsConflictPrimary, imdb.AccountsConflictPolicy())
+}
+
+func TestLists_PrivacyFor(t *testing.T) {
+	type fields struct {
+		lists Lists
+	}
+	type args struct {
+		listID string
+	}
+	friends := ListPrivacyFriends
+	public := ListPrivacyPublic
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, string)
+	}{
+		{
+			name: "default to private",
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, privacy string) {
+				assertions.Equal(ListPrivacyPrivate, privacy)
+			},
+		},
+		{
+			name: "use global privacy",
+			fields: fields{
+				lists: Lists{
+					Privacy: &friends,
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, privacy string) {
+				assertions.Equal(ListPrivacyFriends, privacy)
+			},
+		},
+		{
+			name: "prefer list override regardless of key case",
+			fields: fields{
+				lists: Lists{
+					Privacy: &friends,
+					Overrides: map[string]ListOverride{
+						"LS000000000": {
+							Privacy: &public,
+						},
+					},
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, privacy string) {
+				assertions.Equal(ListPrivacyPublic, privacy)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.fields.lists.PrivacyFor(tt.args.listID))
+		})
+	}
+}
+
+func TestLists_SortFor(t *testing.T) {
+	type fields struct {
+		lists Lists
+	}
+	type args struct {
+		listID string
+	}
+	released := "released"
+	title := "title"
+	desc := ListSortHowDesc
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, string, string)
+	}{
+		{
+			name: "default to unconfigured sort",
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, sortBy, sortHow string) {
+				assertions.Empty(sortBy)
+				assertions.Empty(sortHow)
+			},
+		},
+		{
+			name: "use global sort",
+			fields: fields{
+				lists: Lists{
+					SortBy:  &released,
+					SortHow: &desc,
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, sortBy, sortHow string) {
+				assertions.Equal("released", sortBy)
+				assertions.Equal(ListSortHowDesc, sortHow)
+			},
+		},
+		{
+			name: "prefer list override per field regardless of key case",
+			fields: fields{
+				lists: Lists{
+					SortBy:  &released,
+					SortHow: &desc,
+					Overrides: map[string]ListOverride{
+						"LS000000000": {
+							SortBy: &title,
+						},
+					},
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, sortBy, sortHow string) {
+				assertions.Equal("title", sortBy)
+				assertions.Equal(ListSortHowDesc, sortHow)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortBy, sortHow := tt.fields.lists.SortFor(tt.args.listID)
+			tt.assertions(assert.New(t), sortBy, sortHow)
+		})
+	}
+}
+
+func TestLists_NoRemoveFor(t *testing.T) {
+	type fields struct {
+		lists Lists
+	}
+	type args struct {
+		listID string
+	}
+	enabled := true
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, bool)
+	}{
+		{
+			name: "default to allowing removals",
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, noRemove bool) {
+				assertions.False(noRemove)
+			},
+		},
+		{
+			name: "disable removals via list override regardless of key case",
+			fields: fields{
+				lists: Lists{
+					Overrides: map[string]ListOverride{
+						"LS000000000": {
+							NoRemove: &enabled,
+						},
+					},
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, noRemove bool) {
+				assertions.True(noRemove)
+			},
+		},
+		{
+			name: "ignore overrides of other lists",
+			fields: fields{
+				lists: Lists{
+					Overrides: map[string]ListOverride{
+						"ls111111111": {
+							NoRemove: &enabled,
+						},
+					},
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, noRemove bool) {
+				assertions.False(noRemove)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.fields.lists.NoRemoveFor(tt.args.listID))
 		})
 	}
 }
 
-func TestConfig_WriteFile(t *testing.T) {
+func TestLists_TraktListFor(t *testing.T) {
+	type args struct {
+		traktList string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, string)
+	}{
+		{
+			name: "default to inferring the slug",
+			assertions: func(assertions *assert.Assertions, owner, slug string) {
+				assertions.Empty(owner)
+				assertions.Empty(slug)
+			},
+		},
+		{
+			name: "parse slug",
+			args: args{
+				traktList: "favourites",
+			},
+			assertions: func(assertions *assert.Assertions, owner, slug string) {
+				assertions.Empty(owner)
+				assertions.Equal("favourites", slug)
+			},
+		},
+		{
+			name: "parse owner and slug",
+			args: args{
+				traktList: "someone/favourites",
+			},
+			assertions: func(assertions *assert.Assertions, owner, slug string) {
+				assertions.Equal("someone", owner)
+				assertions.Equal("favourites", slug)
+			},
+		},
+		{
+			name: "parse trakt list url",
+			args: args{
+				traktList: "https://trakt.tv/users/someone/lists/favourites/",
+			},
+			assertions: func(assertions *assert.Assertions, owner, slug string) {
+				assertions.Equal("someone", owner)
+				assertions.Equal("favourites", slug)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists := Lists{
+				Overrides: map[string]ListOverride{
+					"ls000000000": {
+						TraktList: &tt.args.traktList,
+					},
+				},
+			}
+			owner, slug := lists.TraktListFor("ls000000000")
+			tt.assertions(assert.New(t), owner, slug)
+		})
+	}
+	t.Run("use the slug of routed lists", func(t *testing.T) {
+		owner, slug := Lists{}.TraktListFor(RoutedListID("ls000000000", "horror"))
+		assertions := assert.New(t)
+		assertions.Empty(owner)
+		assertions.Equal("horror", slug)
+	})
+}
+
+func TestListRule_Matches(t *testing.T) {
+	type args struct {
+		itemType string
+		genres   []string
+		year     int
+	}
+	minYear, maxYear := 1980, 1989
+	rule := ListRule{
+		Types:   []string{"movie"},
+		Genres:  []string{"horror", "thriller"},
+		MinYear: &minYear,
+		MaxYear: &maxYear,
+	}
+	tests := []struct {
+		name       string
+		rule       ListRule
+		args       args
+		assertions func(*assert.Assertions, bool)
+	}{
+		{
+			name: "match items without conditions",
+			args: args{
+				itemType: "show",
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.True(matches)
+			},
+		},
+		{
+			name: "match all conditions",
+			rule: rule,
+			args: args{
+				itemType: "movie",
+				genres:   []string{"Drama", "Horror"},
+				year:     1982,
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.True(matches)
+			},
+		},
+		{
+			name: "mismatch type",
+			rule: rule,
+			args: args{
+				itemType: "show",
+				genres:   []string{"Horror"},
+				year:     1982,
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.False(matches)
+			},
+		},
+		{
+			name: "mismatch genres",
+			rule: rule,
+			args: args{
+				itemType: "movie",
+				genres:   []string{"Comedy"},
+				year:     1982,
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.False(matches)
+			},
+		},
+		{
+			name: "mismatch year outside of the range",
+			rule: rule,
+			args: args{
+				itemType: "movie",
+				genres:   []string{"Horror"},
+				year:     1990,
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.False(matches)
+			},
+		},
+		{
+			name: "mismatch unknown year",
+			rule: rule,
+			args: args{
+				itemType: "movie",
+				genres:   []string{"Horror"},
+			},
+			assertions: func(assertions *assert.Assertions, matches bool) {
+				assertions.False(matches)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.rule.Matches(tt.args.itemType, tt.args.genres, tt.args.year))
+		})
+	}
+}
+
+func TestLists_TraktListName(t *testing.T) {
 	type fields struct {
-		koanf *koanf.Koanf
+		lists Lists
+	}
+	prefix, suffix := "[IMDb] ", " (mirror)"
+	tests := []struct {
+		name       string
+		fields     fields
+		assertions func(*assert.Assertions, string)
+	}{
+		{
+			name: "default to the imdb list name",
+			assertions: func(assertions *assert.Assertions, name string) {
+				assertions.Equal("Abandoned", name)
+			},
+		},
+		{
+			name: "decorate with prefix and suffix",
+			fields: fields{
+				lists: Lists{
+					NamePrefix: &prefix,
+					NameSuffix: &suffix,
+				},
+			},
+			assertions: func(assertions *assert.Assertions, name string) {
+				assertions.Equal("[IMDb] Abandoned (mirror)", name)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.fields.lists.TraktListName("Abandoned"))
+		})
+	}
+}
+
+func TestLists_RatingThresholdFor(t *testing.T) {
+	type fields struct {
+		lists Lists
 	}
 	type args struct {
-		path string
+		listID string
 	}
+	minRating := 7
 	tests := []struct {
 		name       string
 		fields     fields
 		args       args
-		assertions func(*assert.Assertions, error)
+		assertions func(*assert.Assertions, int)
 	}{
 		{
-			name: "success",
+			name: "default to syncing all items",
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, threshold int) {
+				assertions.Equal(0, threshold)
+			},
+		},
+		{
+			name: "set threshold via list override",
 			fields: fields{
-				koanf: koanf.New("."),
+				lists: Lists{
+					Overrides: map[string]ListOverride{
+						"ls000000000": {
+							MinRating: &minRating,
+						},
+					},
+				},
 			},
 			args: args{
-				path: "config.yaml",
+				listID: "ls000000000",
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Nil(err)
+			assertions: func(assertions *assert.Assertions, threshold int) {
+				assertions.Equal(minRating, threshold)
+			},
+		},
+		{
+			name: "ignore overrides of other lists",
+			fields: fields{
+				lists: Lists{
+					Overrides: map[string]ListOverride{
+						"ls111111111": {
+							MinRating: &minRating,
+						},
+					},
+				},
+			},
+			args: args{
+				listID: "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, threshold int) {
+				assertions.Equal(0, threshold)
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &Config{
-				koanf: tt.fields.koanf,
+			tt.assertions(assert.New(t), tt.fields.lists.RatingThresholdFor(tt.args.listID))
+		})
+	}
+}
+
+func TestSync_OverrideCategories(t *testing.T) {
+	type args struct {
+		only []string
+		skip []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, Sync, error)
+	}{
+		{
+			name: "no overrides",
+			args: args{},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				_, ok := sync.CategoryOverride(SyncCategoryLists)
+				assertions.False(ok)
+				assertions.True(*sync.SkipHistory)
+			},
+		},
+		{
+			name: "only enables the given categories",
+			args: args{
+				only: []string{SyncCategoryRatings, SyncCategoryHistory},
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				enabled, ok := sync.CategoryOverride(SyncCategoryRatings)
+				assertions.True(ok)
+				assertions.True(enabled)
+				enabled, ok = sync.CategoryOverride(SyncCategoryLists)
+				assertions.True(ok)
+				assertions.False(enabled)
+				assertions.False(*sync.SkipHistory)
+			},
+		},
+		{
+			name: "watchlist can't be selected apart from lists",
+			args: args{
+				only: []string{SyncCategoryWatchlist},
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.ErrorContains(err, "synced as part of sync category lists")
+			},
+		},
+		{
+			name: "skip disables the given categories",
+			args: args{
+				skip: []string{SyncCategoryLists},
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				enabled, ok := sync.CategoryOverride(SyncCategoryLists)
+				assertions.True(ok)
+				assertions.False(enabled)
+				_, ok = sync.CategoryOverride(SyncCategoryRatings)
+				assertions.False(ok)
+			},
+		},
+		{
+			name: "handle unknown category",
+			args: args{
+				only: []string{"episodes"},
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.ErrorContains(err, "sync category episodes must be one of")
+			},
+		},
+		{
+			name: "handle category both included and skipped",
+			args: args{
+				only: []string{SyncCategoryRatings},
+				skip: []string{SyncCategoryRatings},
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.ErrorContains(err, "can't be both included and skipped")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skipHistory := true
+			sync := Sync{
+				SkipHistory: &skipHistory,
 			}
-			tt.args.path = fmt.Sprintf("%s/%s", t.TempDir(), tt.args.path)
-			tt.assertions(assert.New(t), c.WriteFile(tt.args.path))
+			err := sync.OverrideCategories(tt.args.only, tt.args.skip)
+			tt.assertions(assert.New(t), sync, err)
 		})
 	}
 }
 
-func TestNewFromMap(t *testing.T) {
+func TestSync_RestrictDates(t *testing.T) {
 	type args struct {
-		data map[string]interface{}
+		from string
+		to   string
 	}
 	tests := []struct {
 		name       string
 		args       args
-		want       *Config
-		assertions func(*assert.Assertions, *Config, error)
+		assertions func(*assert.Assertions, Sync, error)
 	}{
 		{
-			name: "success",
+			name: "no date range",
+			args: args{},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				assertions.False(sync.HasDateRange())
+				assertions.True(sync.InDateRange(time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)))
+			},
+		},
+		{
+			name: "both bounds are inclusive",
 			args: args{
-				data: map[string]interface{}{
-					"IMDB": map[string]interface{}{
-						"COOKIEATMAIN": "xXx",
-					},
-				},
+				from: "2023-01-01",
+				to:   "2023-12-31",
 			},
-			assertions: func(assertions *assert.Assertions, config *Config, err error) {
-				assertions.Nil(err)
-				assertions.NotNil(config)
-				assertions.NotNil(config.IMDb.CookieAtMain)
-				assertions.Equal("xXx", *config.IMDb.CookieAtMain)
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				assertions.True(sync.HasDateRange())
+				assertions.True(sync.InDateRange(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)))
+				assertions.True(sync.InDateRange(time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC)))
+				assertions.False(sync.InDateRange(time.Date(2022, time.December, 31, 23, 59, 59, 0, time.UTC)))
+				assertions.False(sync.InDateRange(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)))
 			},
 		},
 		{
-			name: "invalid config",
+			name: "open ended range",
 			args: args{
-				data: map[string]interface{}{
-					"SYNC": map[string]interface{}{
-						"SKIPHISTORY": "invalid",
-					},
-				},
+				from: "2023-06-15",
 			},
-			assertions: func(assertions *assert.Assertions, config *Config, err error) {
-				assertions.NotNil(err)
-				assertions.Nil(config)
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.NoError(err)
+				assertions.True(sync.InDateRange(time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)))
+				assertions.False(sync.InDateRange(time.Date(2023, time.June, 14, 12, 0, 0, 0, time.UTC)))
+			},
+		},
+		{
+			name: "handle invalid date",
+			args: args{
+				to: "31/12/2023",
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.ErrorContains(err, "date 31/12/2023 must be formatted like 2006-01-02")
+				assertions.False(sync.HasDateRange())
+			},
+		},
+		{
+			name: "handle start after end",
+			args: args{
+				from: "2024-01-01",
+				to:   "2023-12-31",
+			},
+			assertions: func(assertions *assert.Assertions, sync Sync, err error) {
+				assertions.ErrorContains(err, "must not be after its end")
+				assertions.False(sync.HasDateRange())
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			conf, err := NewFromMap(tt.args.data)
-			tt.assertions(assert.New(t), conf, err)
+			var sync Sync
+			err := sync.RestrictDates(tt.args.from, tt.args.to)
+			tt.assertions(assert.New(t), sync, err)
+		})
+	}
+}
+
+func TestSync_ModeFor(t *testing.T) {
+	mode, addOnly, dryRun, empty := SyncModeFull, SyncModeAddOnly, SyncModeDryRun, ""
+	sync := Sync{
+		Mode: &mode,
+		Modes: Modes{
+			Lists:    &addOnly,
+			Ratings:  &empty,
+			History:  &addOnly,
+			Comments: &dryRun,
+		},
+	}
+	tests := []struct {
+		name       string
+		category   string
+		assertions func(*assert.Assertions, string)
+	}{
+		{
+			name:     "use the category mode",
+			category: SyncCategoryLists,
+			assertions: func(assertions *assert.Assertions, mode string) {
+				assertions.Equal(SyncModeAddOnly, mode)
+			},
+		},
+		{
+			name:     "fall back to the sync mode when the category mode is empty",
+			category: SyncCategoryRatings,
+			assertions: func(assertions *assert.Assertions, mode string) {
+				assertions.Equal(SyncModeFull, mode)
+			},
+		},
+		{
+			name:     "fall back to the sync mode when the category mode is unset",
+			category: SyncCategoryWatchlist,
+			assertions: func(assertions *assert.Assertions, mode string) {
+				assertions.Equal(SyncModeFull, mode)
+			},
+		},
+		{
+			name:     "use the history mode for check-ins",
+			category: SyncCategoryCheckIns,
+			assertions: func(assertions *assert.Assertions, mode string) {
+				assertions.Equal(SyncModeAddOnly, mode)
+			},
+		},
+		{
+			name:     "use the comments mode",
+			category: SyncCategoryComments,
+			assertions: func(assertions *assert.Assertions, mode string) {
+				assertions.Equal(SyncModeDryRun, mode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), sync.ModeFor(tt.category))
 		})
 	}
 }
+
+func TestLists_ArchiveFor(t *testing.T) {
+	archive, watchlistArchive := "archive", "watchlist-archive"
+	lists := Lists{
+		WatchlistArchive: &watchlistArchive,
+		Overrides: map[string]ListOverride{
+			"ls000000000": {
+				Archive: &archive,
+			},
+		},
+	}
+	assertions := assert.New(t)
+	assertions.Equal("archive", lists.ArchiveFor("ls000000000"))
+	assertions.Equal("archive", lists.ArchiveFor(RoutedListID("ls000000000", "horror")))
+	assertions.Empty(lists.ArchiveFor("ls111111111"))
+	assertions.Equal("watchlist-archive", lists.WatchlistArchiveSlug())
+	assertions.Empty(Lists{}.WatchlistArchiveSlug())
+}
+
+func TestLists_TrackingListSlug(t *testing.T) {
+	tracking := "synced-by-imdb-trakt-sync"
+	assertions := assert.New(t)
+	assertions.Equal("synced-by-imdb-trakt-sync", Lists{TrackingList: &tracking}.TrackingListSlug())
+	assertions.Empty(Lists{}.TrackingListSlug())
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	envNameConfigFile = prefix + "CONFIG"
+	fileNameDefault   = "config.yaml"
+)
+
+// Discover resolves the path of the config file, in order of precedence:
+// the given path, usually set via command line flag, the ITS_CONFIG environment variable,
+// config.yaml in the working directory and ~/.config/imdb-trakt-sync/config.yaml
+// When none of the files exist, config.yaml in the working directory is returned, so that it can be created
+func Discover(path string) string {
+	if path != "" {
+		return path
+	}
+	if path = os.Getenv(envNameConfigFile); path != "" {
+		return path
+	}
+	candidates := []string{fileNameDefault}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "imdb-trakt-sync", fileNameDefault))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return fileNameDefault
+}
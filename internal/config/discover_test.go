@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	type args struct {
+		path string
+		env  string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func(*require.Assertions, string, string)
+		assertions   func(*assert.Assertions, string, string, string)
+	}{
+		{
+			name: "prefer the given path",
+			args: args{
+				path: "custom.yaml",
+				env:  "env.yaml",
+			},
+			requirements: func(requirements *require.Assertions, workDir, homeDir string) {
+				requirements.NoError(os.WriteFile(filepath.Join(workDir, fileNameDefault), nil, 0644))
+			},
+			assertions: func(assertions *assert.Assertions, path, workDir, homeDir string) {
+				assertions.Equal("custom.yaml", path)
+			},
+		},
+		{
+			name: "prefer the environment variable over discovered files",
+			args: args{
+				env: "env.yaml",
+			},
+			requirements: func(requirements *require.Assertions, workDir, homeDir string) {
+				requirements.NoError(os.WriteFile(filepath.Join(workDir, fileNameDefault), nil, 0644))
+			},
+			assertions: func(assertions *assert.Assertions, path, workDir, homeDir string) {
+				assertions.Equal("env.yaml", path)
+			},
+		},
+		{
+			name: "prefer the working directory over the home directory",
+			requirements: func(requirements *require.Assertions, workDir, homeDir string) {
+				requirements.NoError(os.WriteFile(filepath.Join(workDir, fileNameDefault), nil, 0644))
+				requirements.NoError(os.MkdirAll(filepath.Join(homeDir, ".config", "imdb-trakt-sync"), 0755))
+				requirements.NoError(os.WriteFile(filepath.Join(homeDir, ".config", "imdb-trakt-sync", fileNameDefault), nil, 0644))
+			},
+			assertions: func(assertions *assert.Assertions, path, workDir, homeDir string) {
+				assertions.Equal(fileNameDefault, path)
+			},
+		},
+		{
+			name: "fall back to the home directory",
+			requirements: func(requirements *require.Assertions, workDir, homeDir string) {
+				requirements.NoError(os.MkdirAll(filepath.Join(homeDir, ".config", "imdb-trakt-sync"), 0755))
+				requirements.NoError(os.WriteFile(filepath.Join(homeDir, ".config", "imdb-trakt-sync", fileNameDefault), nil, 0644))
+			},
+			assertions: func(assertions *assert.Assertions, path, workDir, homeDir string) {
+				assertions.Equal(filepath.Join(homeDir, ".config", "imdb-trakt-sync", fileNameDefault), path)
+			},
+		},
+		{
+			name:         "default to the working directory when no config file exists",
+			requirements: func(*require.Assertions, string, string) {},
+			assertions: func(assertions *assert.Assertions, path, workDir, homeDir string) {
+				assertions.Equal(fileNameDefault, path)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir, homeDir := t.TempDir(), t.TempDir()
+			previousDir, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(workDir))
+			t.Cleanup(func() {
+				_ = os.Chdir(previousDir)
+			})
+			t.Setenv("HOME", homeDir)
+			t.Setenv(envNameConfigFile, tt.args.env)
+			tt.requirements(require.New(t), workDir, homeDir)
+			tt.assertions(assert.New(t), Discover(tt.args.path), workDir, homeDir)
+		})
+	}
+}
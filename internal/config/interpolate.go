@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolateEnv replaces ${NAME} references in string config fields with the values of the referenced environment variables
+func interpolateEnv(k *koanf.Koanf) error {
+	raw := k.Raw()
+	if err := interpolateMap(raw, nil); err != nil {
+		return err
+	}
+	// the nested map is loaded without a delimiter, as map keys such as profile names may contain the delimiter
+	return k.Load(confmap.Provider(raw, ""), nil)
+}
+
+func interpolateMap(m map[string]interface{}, path []string) error {
+	for key, value := range m {
+		keyPath := append(path[:len(path):len(path)], key)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := interpolateMap(v, keyPath); err != nil {
+				return err
+			}
+		case []interface{}:
+			for i, element := range v {
+				if s, ok := element.(string); ok {
+					resolved, err := expandEnv(keyPath, s)
+					if err != nil {
+						return err
+					}
+					v[i] = resolved
+				}
+			}
+		case string:
+			resolved, err := expandEnv(keyPath, v)
+			if err != nil {
+				return err
+			}
+			m[key] = resolved
+		}
+	}
+	return nil
+}
+
+func expandEnv(path []string, value string) (string, error) {
+	var missing []string
+	resolved := envReference.ReplaceAllStringFunc(value, func(reference string) string {
+		name := envReference.FindStringSubmatch(reference)[1]
+		envValue, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+			return reference
+		}
+		return envValue
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("config field '%s' references environment variable(s) that are not set: %s", strings.Join(path, delimiter), strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// configVersionLatest is the latest config file layout supported by this build
+	configVersionLatest = 1
+
+	keyVersion = "VERSION"
+)
+
+// migrations upgrade the config layout one version at a time, the migration at index i upgrades version i to version i+1
+var migrations = []func(k *koanf.Koanf) error{
+	migrateToV1,
+}
+
+// deprecatedKeys maps config fields that are no longer supported to the fields replacing them
+var deprecatedKeys = map[string]string{
+	"IMDB_LIST": "IMDB_LISTS",
+}
+
+// migrate upgrades the loaded config to the latest layout, returning warnings about the deprecated fields in use
+func migrate(k *koanf.Koanf) ([]string, error) {
+	version := 0
+	if k.Exists(keyVersion) {
+		version = k.Int(keyVersion)
+	}
+	if version < 0 {
+		return nil, fmt.Errorf("config field '%s' must not be negative", keyVersion)
+	}
+	if version > configVersionLatest {
+		return nil, fmt.Errorf("config version %d is newer than version %d supported by this build, upgrade imdb-trakt-sync to use it", version, configVersionLatest)
+	}
+	warnings := renameDeprecatedKeys(k)
+	for ; version < configVersionLatest; version++ {
+		if err := migrations[version](k); err != nil {
+			return nil, fmt.Errorf("failure migrating config from version %d to version %d: %w", version, version+1, err)
+		}
+	}
+	if err := k.Set(keyVersion, configVersionLatest); err != nil {
+		return nil, fmt.Errorf("failure setting config version: %w", err)
+	}
+	return warnings, nil
+}
+
+func renameDeprecatedKeys(k *koanf.Koanf) []string {
+	var warnings []string
+	for _, key := range sortedKeys(deprecatedKeys) {
+		if !k.Exists(key) {
+			continue
+		}
+		replacement := deprecatedKeys[key]
+		warnings = append(warnings, fmt.Sprintf("config field '%s' is deprecated, use '%s' instead", key, replacement))
+		if !k.Exists(replacement) {
+			_ = k.Set(replacement, k.Get(key))
+		}
+		k.Delete(key)
+	}
+	return warnings
+}
+
+// migrateToV1 upgrades unversioned config files, which allowed the imdb lists to be a single comma or space separated string
+func migrateToV1(k *koanf.Koanf) error {
+	key := "IMDB_LISTS"
+	value, ok := k.Get(key).(string)
+	if !ok {
+		return nil
+	}
+	lists := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	return k.Set(key, lists)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_migrate(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]interface{}
+		assertions func(*assert.Assertions, *koanf.Koanf, []string, error)
+	}{
+		{
+			name: "upgrade unversioned config with imdb lists string",
+			data: map[string]interface{}{
+				"IMDB_LISTS": "ls000000000, ls111111111 ls222222222",
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.NoError(err)
+				assertions.Empty(warnings)
+				assertions.Equal([]string{"ls000000000", "ls111111111", "ls222222222"}, k.Strings("IMDB_LISTS"))
+				assertions.Equal(configVersionLatest, k.Int(keyVersion))
+			},
+		},
+		{
+			name: "keep imdb lists array as is",
+			data: map[string]interface{}{
+				"IMDB_LISTS": []interface{}{"ls000000000"},
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.NoError(err)
+				assertions.Equal([]string{"ls000000000"}, k.Strings("IMDB_LISTS"))
+			},
+		},
+		{
+			name: "rename deprecated key",
+			data: map[string]interface{}{
+				"IMDB_LIST": "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.NoError(err)
+				assertions.Len(warnings, 1)
+				assertions.Contains(warnings[0], "'IMDB_LIST' is deprecated")
+				assertions.False(k.Exists("IMDB_LIST"))
+				assertions.Equal([]string{"ls000000000"}, k.Strings("IMDB_LISTS"))
+			},
+		},
+		{
+			name: "keep replacement of deprecated key when both are set",
+			data: map[string]interface{}{
+				"IMDB_LIST":  "ls000000000",
+				"IMDB_LISTS": []interface{}{"ls111111111"},
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.NoError(err)
+				assertions.Len(warnings, 1)
+				assertions.Equal([]string{"ls111111111"}, k.Strings("IMDB_LISTS"))
+			},
+		},
+		{
+			name: "accept latest version from environment variable",
+			data: map[string]interface{}{
+				keyVersion:   "1",
+				"IMDB_LISTS": "ls000000000",
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("ls000000000", k.String("IMDB_LISTS"))
+			},
+		},
+		{
+			name: "fail when version is newer than supported",
+			data: map[string]interface{}{
+				keyVersion: configVersionLatest + 1,
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.ErrorContains(err, "is newer than version")
+			},
+		},
+		{
+			name: "fail when version is negative",
+			data: map[string]interface{}{
+				keyVersion: -1,
+			},
+			assertions: func(assertions *assert.Assertions, k *koanf.Koanf, warnings []string, err error) {
+				assertions.ErrorContains(err, "must not be negative")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := koanf.New(delimiter)
+			require.NoError(t, k.Load(confmap.Provider(tt.data, delimiter), nil))
+			warnings, err := migrate(k)
+			tt.assertions(assert.New(t), k, warnings, err)
+		})
+	}
+}
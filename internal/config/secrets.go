@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+// traktSecrets are the trakt credentials that can be kept in a separate secrets file, e.g. one managed by a secret manager
+type traktSecrets struct {
+	Email        *string `koanf:"EMAIL"`
+	Password     *string `koanf:"PASSWORD"`
+	ClientID     *string `koanf:"CLIENTID"`
+	ClientSecret *string `koanf:"CLIENTSECRET"`
+}
+
+// loadTraktSecrets overrides the trakt credentials with the ones found in the secrets file, when one is configured
+// the secrets are applied to the config struct only, hence they are never written back to the main config file
+func (c *Config) loadTraktSecrets() error {
+	path := c.Trakt.SecretsFile
+	if path == nil || *path == "" {
+		return nil
+	}
+	secretsPath := c.relativeToConfig(*path)
+	k := koanf.New(delimiter)
+	if err := k.Load(file.Provider(secretsPath), yaml.Parser()); err != nil {
+		return fmt.Errorf("error loading trakt secrets from file %s: %w", secretsPath, err)
+	}
+	var secrets traktSecrets
+	if err := k.Unmarshal("", &secrets); err != nil {
+		return fmt.Errorf("error unmarshalling trakt secrets from file %s: %w", secretsPath, err)
+	}
+	if secrets.Email != nil {
+		c.Trakt.Email = secrets.Email
+	}
+	if secrets.Password != nil {
+		c.Trakt.Password = secrets.Password
+	}
+	if secrets.ClientID != nil {
+		c.Trakt.ClientID = secrets.ClientID
+	}
+	if secrets.ClientSecret != nil {
+		c.Trakt.ClientSecret = secrets.ClientSecret
+	}
+	return nil
+}
+
+// resolveTraktTokensFile resolves a relative trakt tokens file against the directory of the config file,
+// so that the tokens are found regardless of the directory the sync is run from
+func (c *Config) resolveTraktTokensFile() {
+	if path := c.Trakt.TokensFilePath(); path != "" {
+		resolved := c.relativeToConfig(path)
+		c.Trakt.TokensFile = &resolved
+	}
+}
+
+func (c *Config) relativeToConfig(path string) string {
+	if filepath.IsAbs(path) || c.path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(c.path), path)
+}
+
+// profilePath appends the profile name to the file name of a path, e.g. tokens.json becomes tokens-family.json for profile family
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), profile, ext)
+}
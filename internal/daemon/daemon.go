@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	pathHealth = "/healthz"
+	pathReady  = "/readyz"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Daemon keeps running a sync on a schedule, reporting the outcome of the last run on the health endpoints
+type Daemon struct {
+	clock    clock.Clock
+	logger   *slog.Logger
+	schedule Schedule
+	run      func(context.Context) error
+	mutex    sync.Mutex
+	status   Status
+}
+
+// Schedule returns the time of the run following the given run time, the zero time stops the daemon
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// Interval schedules a run right away and then once per interval
+type Interval time.Duration
+
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// Status describes the last completed run of the daemon
+type Status struct {
+	Runs        int       `json:"runs"`
+	Running     bool      `json:"running"`
+	LastRun     time.Time `json:"lastRun,omitempty"`
+	LastRunAgo  string    `json:"lastRunAgo,omitempty"`
+	LastSuccess bool      `json:"lastSuccess"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+func New(clock clock.Clock, logger *slog.Logger, schedule Schedule, run func(context.Context) error) *Daemon {
+	return &Daemon{
+		clock:    clock,
+		logger:   logger,
+		schedule: schedule,
+		run:      run,
+	}
+}
+
+// Run runs the sync on the schedule until the context is cancelled, an interval schedule runs the first sync right away
+// a failed run doesn't stop the daemon, while the runs scheduled during a run that is still going are skipped
+func (d *Daemon) Run(ctx context.Context) error {
+	next := d.clock.Now()
+	if _, ok := d.schedule.(Interval); !ok {
+		next = d.schedule.Next(next)
+	}
+	for {
+		if next.IsZero() {
+			return fmt.Errorf("the daemon schedule has no upcoming runs")
+		}
+		if wait := next.Sub(d.clock.Now()); wait > 0 {
+			d.logger.Info(fmt.Sprintf("next sync at %s, in %s", next.Format(time.RFC3339), wait.Round(time.Second)))
+			select {
+			case <-ctx.Done():
+				d.logger.Info("stopping daemon")
+				return nil
+			case <-d.clock.After(wait):
+			}
+		}
+		d.logger.Info(fmt.Sprintf("running sync scheduled at %s", next.Format(time.RFC3339)))
+		d.runOnce(ctx)
+		if ctx.Err() != nil {
+			d.logger.Info("stopping daemon")
+			return nil
+		}
+		finished := d.clock.Now()
+		for next = d.schedule.Next(next); !next.IsZero() && !next.After(finished); next = d.schedule.Next(next) {
+			d.logger.Warn(fmt.Sprintf("skipping sync scheduled at %s, the previous sync was still running", next.Format(time.RFC3339)))
+		}
+	}
+}
+
+func (d *Daemon) runOnce(ctx context.Context) {
+	d.mutex.Lock()
+	d.status.Running = true
+	d.mutex.Unlock()
+	err := d.run(ctx)
+	if err != nil {
+		d.logger.Error("failure running scheduled sync", logger.Error(err))
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.status.Runs++
+	d.status.Running = false
+	d.status.LastRun = d.clock.Now()
+	d.status.LastSuccess = err == nil
+	d.status.LastError = ""
+	if err != nil {
+		d.status.LastError = err.Error()
+	}
+}
+
+// Status returns the outcome of the last run
+func (d *Daemon) Status() Status {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	status := d.status
+	if !status.LastRun.IsZero() {
+		status.LastRunAgo = d.clock.Now().Sub(status.LastRun).Round(time.Second).String()
+	}
+	return status
+}
+
+// Handler serves the health endpoints:
+// /healthz reports the daemon is alive, while /readyz fails until a run completes and whenever the last run failed
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pathHealth, func(w http.ResponseWriter, r *http.Request) {
+		d.writeStatus(w, http.StatusOK)
+	})
+	mux.HandleFunc(pathReady, func(w http.ResponseWriter, r *http.Request) {
+		statusCode := http.StatusOK
+		if status := d.Status(); status.Runs == 0 || !status.LastSuccess {
+			statusCode = http.StatusServiceUnavailable
+		}
+		d.writeStatus(w, statusCode)
+	})
+	return mux
+}
+
+func (d *Daemon) writeStatus(w http.ResponseWriter, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(d.Status()); err != nil {
+		d.logger.Warn("failure writing health status", logger.Error(err))
+	}
+}
+
+// Serve serves the health endpoints on the address until the context is cancelled
+func (d *Daemon) Serve(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failure listening on health address %s: %w", address, err)
+	}
+	server := &http.Server{
+		Handler:           d.Handler(),
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	d.logger.Info(fmt.Sprintf("serving health endpoints on %s", listener.Addr()))
+	if err = server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failure serving health endpoints: %w", err)
+	}
+	return nil
+}
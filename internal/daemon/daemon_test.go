@@ -0,0 +1,187 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+	"github.com/cecobask/imdb-trakt-sync/pkg/cron"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func TestDaemon_Run(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	everyQuarter, err := cron.Parse("*/15 * * * *")
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		schedule   Schedule
+		runTime    time.Duration
+		results    []error
+		assertions func(*assert.Assertions, *Daemon, error)
+	}{
+		{
+			name:     "run once per interval until cancelled",
+			schedule: Interval(time.Hour),
+			results:  []error{nil, nil, nil},
+			assertions: func(assertions *assert.Assertions, d *Daemon, err error) {
+				assertions.NoError(err)
+				status := d.Status()
+				assertions.Equal(3, status.Runs)
+				assertions.True(status.LastSuccess)
+				assertions.Equal(start.Add(2*time.Hour), status.LastRun)
+			},
+		},
+		{
+			name:     "wait for the first run of a cron schedule",
+			schedule: everyQuarter,
+			results:  []error{nil, nil},
+			assertions: func(assertions *assert.Assertions, d *Daemon, err error) {
+				assertions.NoError(err)
+				status := d.Status()
+				assertions.Equal(2, status.Runs)
+				assertions.Equal(start.Add(30*time.Minute), status.LastRun)
+			},
+		},
+		{
+			name:     "skip the runs scheduled while a run is still going",
+			schedule: Interval(time.Hour),
+			runTime:  90 * time.Minute,
+			results:  []error{nil, nil},
+			assertions: func(assertions *assert.Assertions, d *Daemon, err error) {
+				assertions.NoError(err)
+				status := d.Status()
+				assertions.Equal(2, status.Runs)
+				assertions.Equal(start.Add(2*time.Hour+90*time.Minute), status.LastRun)
+			},
+		},
+		{
+			name:     "keep running after a failed run",
+			schedule: Interval(time.Hour),
+			results:  []error{errors.New("sync failed"), nil},
+			assertions: func(assertions *assert.Assertions, d *Daemon, err error) {
+				assertions.NoError(err)
+				status := d.Status()
+				assertions.Equal(2, status.Runs)
+				assertions.True(status.LastSuccess)
+				assertions.Empty(status.LastError)
+			},
+		},
+		{
+			name:     "report the error of the last run",
+			schedule: Interval(time.Hour),
+			results:  []error{nil, errors.New("sync failed")},
+			assertions: func(assertions *assert.Assertions, d *Daemon, err error) {
+				assertions.NoError(err)
+				status := d.Status()
+				assertions.False(status.LastSuccess)
+				assertions.Equal("sync failed", status.LastError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fakeClock := clock.NewFake(start)
+			runs := 0
+			run := func(ctx context.Context) error {
+				fakeClock.Advance(tt.runTime)
+				err := tt.results[runs]
+				if runs++; runs == len(tt.results) {
+					cancel()
+				}
+				return err
+			}
+			d := New(fakeClock, logger.NewLogger(io.Discard), tt.schedule, run)
+			err := d.Run(ctx)
+			tt.assertions(assert.New(t), d, err)
+		})
+	}
+}
+
+func TestDaemon_Handler(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		path       string
+		status     Status
+		assertions func(*assert.Assertions, *httptest.ResponseRecorder, Status)
+	}{
+		{
+			name: "report healthy before the first run",
+			path: pathHealth,
+			assertions: func(assertions *assert.Assertions, response *httptest.ResponseRecorder, status Status) {
+				assertions.Equal(http.StatusOK, response.Code)
+				assertions.Zero(status.Runs)
+			},
+		},
+		{
+			name: "report not ready before the first run",
+			path: pathReady,
+			assertions: func(assertions *assert.Assertions, response *httptest.ResponseRecorder, status Status) {
+				assertions.Equal(http.StatusServiceUnavailable, response.Code)
+			},
+		},
+		{
+			name: "report ready after a successful run",
+			path: pathReady,
+			status: Status{
+				Runs:        1,
+				LastRun:     start.Add(-time.Minute),
+				LastSuccess: true,
+			},
+			assertions: func(assertions *assert.Assertions, response *httptest.ResponseRecorder, status Status) {
+				assertions.Equal(http.StatusOK, response.Code)
+				assertions.True(status.LastSuccess)
+				assertions.Equal("1m0s", status.LastRunAgo)
+			},
+		},
+		{
+			name: "report not ready after a failed run",
+			path: pathReady,
+			status: Status{
+				Runs:      1,
+				LastRun:   start.Add(-time.Minute),
+				LastError: "sync failed",
+			},
+			assertions: func(assertions *assert.Assertions, response *httptest.ResponseRecorder, status Status) {
+				assertions.Equal(http.StatusServiceUnavailable, response.Code)
+				assertions.Equal("sync failed", status.LastError)
+			},
+		},
+		{
+			name: "report healthy after a failed run",
+			path: pathHealth,
+			status: Status{
+				Runs:      1,
+				LastRun:   start.Add(-time.Minute),
+				LastError: "sync failed",
+			},
+			assertions: func(assertions *assert.Assertions, response *httptest.ResponseRecorder, status Status) {
+				assertions.Equal(http.StatusOK, response.Code)
+				assertions.False(status.LastSuccess)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(clock.NewFake(start), logger.NewLogger(io.Discard), Interval(time.Hour), nil)
+			d.status = tt.status
+			response := httptest.NewRecorder()
+			d.Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+			var status Status
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&status))
+			tt.assertions(assert.New(t), response, status)
+		})
+	}
+}
@@ -1,8 +1,21 @@
 package entities
 
 import (
+	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// reasons attached to the items of a difference, explaining why they are added or removed
+const (
+	DiffReasonMissingOnIMDb       = "missing on imdb"
+	DiffReasonMissingOnTrakt      = "in imdb, missing on trakt"
+	DiffReasonRemovedFromIMDbList = "removed from imdb list"
 )
 
 func ListDifference(imdbList IMDbList, traktList TraktList) map[string]TraktItems {
@@ -18,35 +31,129 @@ func ListDifference(imdbList IMDbList, traktList TraktList) map[string]TraktItem
 		}
 		traktItems[*id] = item
 	}
-	return ItemsDifference(imdbItems, traktItems)
+	diff := ItemsDifference(imdbItems, traktItems)
+	for i := range diff["remove"] {
+		diff["remove"][i].Reason = DiffReasonRemovedFromIMDbList
+	}
+	return diff
+}
+
+// ListUnionDifference compares an imdb list with its trakt list like ListDifference, except that the trakt items found in any of the sibling
+// imdb lists, which are mirrored to the same trakt list, are kept. An item is only removed from the trakt list once it's gone from all of them
+func ListUnionDifference(imdbList IMDbList, siblings []IMDbList, traktList TraktList) map[string]TraktItems {
+	diff := ListDifference(imdbList, traktList)
+	if len(siblings) == 0 || len(diff["remove"]) == 0 {
+		return diff
+	}
+	kept := make(map[string]struct{})
+	for _, sibling := range siblings {
+		for _, item := range sibling.ListItems {
+			kept[item.ID] = struct{}{}
+		}
+	}
+	var remove TraktItems
+	for _, item := range diff["remove"] {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := kept[*id]; found {
+				continue
+			}
+		}
+		remove = append(remove, item)
+	}
+	diff["remove"] = remove
+	return diff
 }
 
 func ItemsDifference(imdbItems map[string]IMDbItem, traktItems map[string]TraktItem) map[string]TraktItems {
 	diff := make(map[string]TraktItems)
 	for id, imdbItem := range imdbItems {
-		traktItem := imdbItem.toTraktItem()
+		traktItem := imdbItem.ToTraktItem()
 		if _, found := traktItems[id]; !found {
+			traktItem.Reason = DiffReasonMissingOnTrakt
 			diff["add"] = append(diff["add"], traktItem)
 			continue
 		}
 		if imdbItem.Rating != nil && *imdbItem.Rating != traktItems[id].Rating {
-			diff["add"] = append(diff["add"], traktItem)
+			traktItem.Reason = fmt.Sprintf("rating differs %d→%d", traktItems[id].Rating, *imdbItem.Rating)
+			diff["update"] = append(diff["update"], traktItem)
 			continue
 		}
 	}
 	for id, traktItem := range traktItems {
 		if _, found := imdbItems[id]; !found {
+			traktItem.Reason = DiffReasonMissingOnIMDb
 			diff["remove"] = append(diff["remove"], traktItem)
 		}
 	}
 	return diff
 }
 
+// SlugRules normalise list names before the slugs of their trakt lists are inferred from them
+// the inferred slugs must not change across runs, otherwise new trakt lists are created, hence the rules only depend on the name
+type SlugRules struct {
+	// Transliterate replaces accented letters by their base letters, like trakt does, e.g. café becomes cafe
+	Transliterate bool
+	// Replacements replace text of the name before anything else, e.g. & by and, the longest text is replaced first
+	Replacements map[string]string
+}
+
+var DefaultSlugRules = SlugRules{
+	Transliterate: true,
+}
+
+// transliterations are the letters which don't decompose into a base letter and a mark
+var transliterations = strings.NewReplacer("ß", "ss", "æ", "ae", "Æ", "ae", "ø", "o", "Ø", "o", "œ", "oe", "Œ", "oe", "ł", "l", "Ł", "l", "đ", "d", "Đ", "d", "þ", "th", "Þ", "th")
+
+var slugInvalidCharacters = regexp.MustCompile(`[^-_a-z0-9]+`)
+
 func InferTraktListSlug(imdbListName string) string {
-	result := strings.ToLower(strings.Join(strings.Fields(imdbListName), "-"))
-	regex := regexp.MustCompile(`[^-_a-z0-9]+`)
-	result = removeDuplicateAdjacentCharacters(regex.ReplaceAllString(result, ""), '-')
-	return result
+	return DefaultSlugRules.InferTraktListSlug(imdbListName)
+}
+
+// InferTraktListSlug normalises the name with the rules, then joins its words with dashes, leaving out emoji and punctuation
+func (r SlugRules) InferTraktListSlug(imdbListName string) string {
+	name := r.replace(imdbListName)
+	if r.Transliterate {
+		name = transliterate(name)
+	}
+	result := strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	result = removeDuplicateAdjacentCharacters(slugInvalidCharacters.ReplaceAllString(result, ""), '-')
+	return strings.Trim(result, "-")
+}
+
+func (r SlugRules) replace(name string) string {
+	if len(r.Replacements) == 0 {
+		return name
+	}
+	// the replacements are applied in a fixed order, since map iteration order would make the slugs differ between runs
+	texts := make([]string, 0, len(r.Replacements))
+	for text := range r.Replacements {
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		if len(texts[i]) != len(texts[j]) {
+			return len(texts[i]) > len(texts[j])
+		}
+		return texts[i] < texts[j]
+	})
+	pairs := make([]string, 0, len(texts)*2)
+	for _, text := range texts {
+		pairs = append(pairs, text, " "+r.Replacements[text]+" ")
+	}
+	return strings.NewReplacer(pairs...).Replace(name)
+}
+
+// transliterate decomposes the letters of the name, dropping the marks, e.g. é becomes e
+func transliterate(name string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(transliterations.Replace(name)) {
+		if !unicode.Is(unicode.Mn, r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
 }
 
 func removeDuplicateAdjacentCharacters(value string, target rune) string {
@@ -58,3 +165,88 @@ func removeDuplicateAdjacentCharacters(value string, target rune) string {
 	}
 	return sb.String()
 }
+
+// TitleSimilarity returns how similar two titles are, from 0 for entirely different titles to 1 for equal ones
+// the titles are compared ignoring case and punctuation, by the edit distance between them relative to the longer title
+func TitleSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeTitle(a)), []rune(normalizeTitle(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// TitleIndex indexes imdb items by their type, title and year, in order to match the trakt items lacking an imdb id
+// titles are compared ignoring case and punctuation, since the imdb exports carry neither tmdb nor tvdb ids to match by
+type TitleIndex map[string][]string
+
+func NewTitleIndex(items []IMDbItem) TitleIndex {
+	index := make(TitleIndex)
+	for i := range items {
+		traktItem := items[i].ToTraktItem()
+		if traktItem.Type == TraktItemTypeEpisode {
+			continue
+		}
+		key := titleIndexKey(traktItem.Type, items[i].Title, items[i].Year)
+		if !slices.Contains(index[key], items[i].ID) {
+			index[key] = append(index[key], items[i].ID)
+		}
+	}
+	return index
+}
+
+// Match returns the imdb id of the single indexed item with the same type, title and year as the trakt item
+// episodes are never matched, nor are items matching more than one indexed item
+func (ti TitleIndex) Match(item TraktItem) (string, bool) {
+	var spec TraktItemSpec
+	switch item.Type {
+	case TraktItemTypeMovie:
+		spec = item.Movie
+	case TraktItemTypeShow:
+		spec = item.Show
+	default:
+		return "", false
+	}
+	ids := ti[titleIndexKey(item.Type, spec.Title, spec.Year)]
+	if len(ids) != 1 {
+		return "", false
+	}
+	return ids[0], true
+}
+
+func titleIndexKey(itemType, title string, year int) string {
+	return fmt.Sprintf("%s|%s|%d", itemType, normalizeTitle(title), year)
+}
+
+func normalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// editDistance returns the levenshtein distance between a and b, using a single row of the distance matrix
+func editDistance(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		previous := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			current := row[j]
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min(row[j]+1, row[j-1]+1, previous+cost)
+			previous = current
+		}
+	}
+	return row[len(b)]
+}
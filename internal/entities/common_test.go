@@ -0,0 +1,333 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListUnionDifference(t *testing.T) {
+	type args struct {
+		imdbList  IMDbList
+		siblings  []IMDbList
+		traktList TraktList
+	}
+	imdbList := func(id string, itemIDs ...string) IMDbList {
+		list := IMDbList{
+			ListID:   id,
+			ListName: "Favourites",
+		}
+		for _, itemID := range itemIDs {
+			list.ListItems = append(list.ListItems, IMDbItem{
+				ID:        itemID,
+				TitleType: imdbItemTypeMovie,
+			})
+		}
+		return list
+	}
+	traktList := func(itemIDs ...string) TraktList {
+		list := TraktList{
+			IDMeta: TraktIDMeta{
+				Slug: "favourites",
+			},
+		}
+		for _, itemID := range itemIDs {
+			list.ListItems = append(list.ListItems, TraktItem{
+				Type: TraktItemTypeMovie,
+				Movie: TraktItemSpec{
+					IDMeta: TraktIDMeta{
+						IMDb: itemID,
+					},
+				},
+			})
+		}
+		return list
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, map[string]TraktItems)
+	}{
+		{
+			name: "remove items missing from a list without siblings",
+			args: args{
+				imdbList:  imdbList("ls000000000", "tt0000001"),
+				traktList: traktList("tt0000001", "tt0000002"),
+			},
+			assertions: func(assertions *assert.Assertions, diff map[string]TraktItems) {
+				assertions.Empty(diff["add"])
+				assertions.Equal([]string{"[tt0000002]: " + DiffReasonRemovedFromIMDbList}, diff["remove"].ExplainedLabels())
+			},
+		},
+		{
+			name: "keep items still present in an overlapping sibling list",
+			args: args{
+				imdbList: imdbList("ls000000000", "tt0000001"),
+				siblings: []IMDbList{
+					imdbList("ls111111111", "tt0000001", "tt0000002"),
+				},
+				traktList: traktList("tt0000001", "tt0000002"),
+			},
+			assertions: func(assertions *assert.Assertions, diff map[string]TraktItems) {
+				assertions.Empty(diff["add"])
+				assertions.Empty(diff["remove"])
+			},
+		},
+		{
+			name: "remove items missing from all sibling lists",
+			args: args{
+				imdbList: imdbList("ls000000000", "tt0000001"),
+				siblings: []IMDbList{
+					imdbList("ls111111111", "tt0000002"),
+				},
+				traktList: traktList("tt0000001", "tt0000002", "tt0000003"),
+			},
+			assertions: func(assertions *assert.Assertions, diff map[string]TraktItems) {
+				assertions.Empty(diff["add"])
+				assertions.Equal([]string{"[tt0000003]: " + DiffReasonRemovedFromIMDbList}, diff["remove"].ExplainedLabels())
+			},
+		},
+		{
+			name: "add items regardless of sibling lists",
+			args: args{
+				imdbList: imdbList("ls000000000", "tt0000001", "tt0000002"),
+				siblings: []IMDbList{
+					imdbList("ls111111111", "tt0000002"),
+				},
+				traktList: traktList("tt0000001"),
+			},
+			assertions: func(assertions *assert.Assertions, diff map[string]TraktItems) {
+				assertions.Len(diff["add"], 1)
+				assertions.Empty(diff["remove"])
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), ListUnionDifference(tt.args.imdbList, tt.args.siblings, tt.args.traktList))
+		})
+	}
+}
+
+func TestItemsDifference(t *testing.T) {
+	rating := func(value int) *int {
+		return &value
+	}
+	ratingDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	imdbItems := map[string]IMDbItem{
+		"tt0000001": {
+			ID:         "tt0000001",
+			TitleType:  imdbItemTypeMovie,
+			Rating:     rating(9),
+			RatingDate: &ratingDate,
+		},
+		"tt0000002": {
+			ID:         "tt0000002",
+			TitleType:  imdbItemTypeMovie,
+			Rating:     rating(8),
+			RatingDate: &ratingDate,
+		},
+	}
+	traktItems := map[string]TraktItem{
+		"tt0000001": {
+			Type:   TraktItemTypeMovie,
+			Rating: 7,
+			Movie: TraktItemSpec{
+				IDMeta: TraktIDMeta{
+					IMDb: "tt0000001",
+				},
+			},
+		},
+		"tt0000003": {
+			Type:   TraktItemTypeMovie,
+			Rating: 6,
+			Movie: TraktItemSpec{
+				IDMeta: TraktIDMeta{
+					IMDb: "tt0000003",
+				},
+			},
+		},
+	}
+	diff := ItemsDifference(imdbItems, traktItems)
+	assertions := assert.New(t)
+	assertions.Equal([]string{"[tt0000002]: " + DiffReasonMissingOnTrakt}, diff["add"].ExplainedLabels())
+	assertions.Equal([]string{"[tt0000001]: rating differs 7→9"}, diff["update"].ExplainedLabels())
+	assertions.Equal([]string{"[tt0000003]: " + DiffReasonMissingOnIMDb}, diff["remove"].ExplainedLabels())
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name       string
+		a          string
+		b          string
+		assertions func(*assert.Assertions, float64)
+	}{
+		{
+			name: "equal titles ignoring case and punctuation",
+			a:    "Spider-Man: No Way Home",
+			b:    "spider man no way home",
+			assertions: func(assertions *assert.Assertions, similarity float64) {
+				assertions.Equal(1.0, similarity)
+			},
+		},
+		{
+			name: "near duplicate titles",
+			a:    "The Office",
+			b:    "The Office (US)",
+			assertions: func(assertions *assert.Assertions, similarity float64) {
+				assertions.InDelta(0.818, similarity, 0.001)
+			},
+		},
+		{
+			name: "different titles",
+			a:    "Dunkirk",
+			b:    "Inception",
+			assertions: func(assertions *assert.Assertions, similarity float64) {
+				assertions.Less(similarity, 0.5)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), TitleSimilarity(tt.a, tt.b))
+		})
+	}
+}
+
+func TestTitleIndex_Match(t *testing.T) {
+	index := NewTitleIndex([]IMDbItem{
+		{ID: "tt5013056", Title: "Dunkirk", Year: 2017, TitleType: "movie"},
+		{ID: "tt7366338", Title: "Chernobyl", Year: 2019, TitleType: "tvMiniSeries"},
+		{ID: "tt0000001", Title: "Hamlet", Year: 1990, TitleType: "movie"},
+		{ID: "tt0000002", Title: "Hamlet", Year: 1990, TitleType: "movie"},
+	})
+	tests := []struct {
+		name       string
+		item       TraktItem
+		assertions func(*assert.Assertions, string, bool)
+	}{
+		{
+			name: "match a movie by title and year ignoring case and punctuation",
+			item: TraktItem{Type: TraktItemTypeMovie, Movie: TraktItemSpec{Title: "DUNKIRK!", Year: 2017}},
+			assertions: func(assertions *assert.Assertions, id string, found bool) {
+				assertions.True(found)
+				assertions.Equal("tt5013056", id)
+			},
+		},
+		{
+			name: "match a show",
+			item: TraktItem{Type: TraktItemTypeShow, Show: TraktItemSpec{Title: "Chernobyl", Year: 2019}},
+			assertions: func(assertions *assert.Assertions, id string, found bool) {
+				assertions.True(found)
+				assertions.Equal("tt7366338", id)
+			},
+		},
+		{
+			name: "skip a different year",
+			item: TraktItem{Type: TraktItemTypeMovie, Movie: TraktItemSpec{Title: "Dunkirk", Year: 1958}},
+			assertions: func(assertions *assert.Assertions, id string, found bool) {
+				assertions.False(found)
+			},
+		},
+		{
+			name: "skip an ambiguous title",
+			item: TraktItem{Type: TraktItemTypeMovie, Movie: TraktItemSpec{Title: "Hamlet", Year: 1990}},
+			assertions: func(assertions *assert.Assertions, id string, found bool) {
+				assertions.False(found)
+			},
+		},
+		{
+			name: "skip episodes",
+			item: TraktItem{Type: TraktItemTypeEpisode, Episode: TraktItemSpec{Title: "Dunkirk", Year: 2017}},
+			assertions: func(assertions *assert.Assertions, id string, found bool) {
+				assertions.False(found)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, found := index.Match(tt.item)
+			tt.assertions(assert.New(t), id, found)
+		})
+	}
+}
+
+func TestSlugRules_InferTraktListSlug(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      SlugRules
+		listName   string
+		assertions func(*assert.Assertions, string)
+	}{
+		{
+			name:     "plain name",
+			rules:    DefaultSlugRules,
+			listName: "My   Favourite Movies",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("my-favourite-movies", slug)
+			},
+		},
+		{
+			name:     "accented name transliterated",
+			rules:    DefaultSlugRules,
+			listName: "Café Crème Brûlée",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("cafe-creme-brulee", slug)
+			},
+		},
+		{
+			name:     "letters without decomposition transliterated",
+			rules:    DefaultSlugRules,
+			listName: "Straße Ørsted Łódź",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("strasse-orsted-lodz", slug)
+			},
+		},
+		{
+			name:     "accented name without transliteration",
+			rules:    SlugRules{},
+			listName: "Café Noir",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("caf-noir", slug)
+			},
+		},
+		{
+			name:     "emoji and punctuation left out",
+			rules:    DefaultSlugRules,
+			listName: "🎬 Sci-Fi & Fantasy! 🍿",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("sci-fi-fantasy", slug)
+			},
+		},
+		{
+			name: "replacements applied longest first",
+			rules: SlugRules{
+				Transliterate: true,
+				Replacements: map[string]string{
+					"&":  "and",
+					"&&": "plus",
+				},
+			},
+			listName: "Sci-Fi & Fantasy && More",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("sci-fi-and-fantasy-plus-more", slug)
+			},
+		},
+		{
+			name:     "non latin name",
+			rules:    DefaultSlugRules,
+			listName: "Кино 2024",
+			assertions: func(assertions *assert.Assertions, slug string) {
+				assertions.Equal("2024", slug)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug := tt.rules.InferTraktListSlug(tt.listName)
+			tt.assertions(assert.New(t), slug)
+			assert.Equal(t, slug, tt.rules.InferTraktListSlug(tt.listName), "the slug must be stable across runs")
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 )
 
@@ -11,19 +12,42 @@ const (
 	imdbItemTypeTvSeries     = "tvSeries"
 )
 
+// newer imdb exports spell out the title types, e.g. "TV Episode" instead of "tvEpisode"
+var imdbItemTypeAliases = map[string]string{
+	"movie":          imdbItemTypeMovie,
+	"tv episode":     imdbItemTypeTvEpisode,
+	"tv mini series": imdbItemTypeTvMiniSeries,
+	"tv series":      imdbItemTypeTvSeries,
+}
+
+func normalizeIMDbItemType(titleType string) string {
+	if itemType, ok := imdbItemTypeAliases[strings.ToLower(strings.TrimSpace(titleType))]; ok {
+		return itemType
+	}
+	return titleType
+}
+
 type IMDbItem struct {
-	ID         string
-	TitleType  string
-	Rating     *int
-	RatingDate *time.Time
+	ID          string
+	Title       string
+	Year        int
+	TitleType   string
+	Description string
+	Genres      []string
+	Review      string // only part of ratings exports which include the reviews
+	Created     *time.Time
+	Rating      *int
+	RatingDate  *time.Time
 }
 
-func (i *IMDbItem) toTraktItem() TraktItem {
+func (i *IMDbItem) ToTraktItem() TraktItem {
 	ti := TraktItem{}
 	tiSpec := TraktItemSpec{
 		IDMeta: TraktIDMeta{
 			IMDb: i.ID,
 		},
+		Title: i.Title,
+		Year:  i.Year,
 	}
 	if i.Rating != nil {
 		ratedAt := i.RatingDate.UTC().String()
@@ -31,7 +55,7 @@ func (i *IMDbItem) toTraktItem() TraktItem {
 		tiSpec.WatchedAt = &ratedAt
 		tiSpec.Rating = i.Rating
 	}
-	switch i.TitleType {
+	switch normalizeIMDbItemType(i.TitleType) {
 	case imdbItemTypeMovie:
 		ti.Type = TraktItemTypeMovie
 		ti.Movie = tiSpec
@@ -56,4 +80,21 @@ type IMDbList struct {
 	ListName    string
 	ListItems   []IMDbItem
 	IsWatchlist bool
+	// the list was read from its web page, which lazy loads the items past the first page, hence items missing from it may still be listed
+	Partial bool
+}
+
+func (l *IMDbList) RemoveDuplicates() int {
+	seen := make(map[string]struct{}, len(l.ListItems))
+	items := make([]IMDbItem, 0, len(l.ListItems))
+	for _, item := range l.ListItems {
+		if _, found := seen[item.ID]; found {
+			continue
+		}
+		seen[item.ID] = struct{}{}
+		items = append(items, item)
+	}
+	duplicates := len(l.ListItems) - len(items)
+	l.ListItems = items
+	return duplicates
 }
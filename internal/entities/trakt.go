@@ -2,6 +2,9 @@ package entities
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
 const (
@@ -9,6 +12,9 @@ const (
 	TraktItemTypeMovie   = "movie"
 	TraktItemTypeSeason  = "season"
 	TraktItemTypeShow    = "show"
+
+	// layout of the watched dates set by SetWatchedAt, which is the layout of time.Time.String
+	watchedAtLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
 )
 
 type TraktAuthCodesBody struct {
@@ -27,17 +33,48 @@ type TraktAuthTokensBody struct {
 }
 
 type TraktAuthTokensResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+type TraktAuthRefreshBody struct {
+	RefreshToken string `json:"refresh_token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURI  string `json:"redirect_uri"`
+	GrantType    string `json:"grant_type"`
+}
+
+type TraktUserSettings struct {
+	User struct {
+		Username string `json:"username"`
+		IDs      struct {
+			Slug string `json:"slug"`
+		} `json:"ids"`
+	} `json:"user"`
 }
 
 type TraktIDMeta struct {
+	Trakt    int     `json:"trakt,omitempty"`
 	IMDb     string  `json:"imdb,omitempty"`
 	Slug     string  `json:"slug,omitempty"`
+	Owner    string  `json:"-"`
 	ListName *string `json:"-"`
 }
 
 type TraktIDMetas []TraktIDMeta
 
+func (tidm TraktIDMetas) GetIMDbIDFromSlug(slug string) string {
+	for _, idm := range tidm {
+		if idm.Slug == slug {
+			return idm.IMDb
+		}
+	}
+	return ""
+}
+
 func (tidm TraktIDMetas) GetListNameFromSlug(slug string) string {
 	for _, idm := range tidm {
 		if idm.Slug == slug {
@@ -49,6 +86,8 @@ func (tidm TraktIDMetas) GetListNameFromSlug(slug string) string {
 
 type TraktItemSpec struct {
 	IDMeta    TraktIDMeta `json:"ids"`
+	Title     string      `json:"title,omitempty"`
+	Year      int         `json:"year,omitempty"`
 	RatedAt   *string     `json:"rated_at,omitempty"`
 	Rating    *int        `json:"rating,omitempty"`
 	WatchedAt *string     `json:"watched_at,omitempty"`
@@ -57,12 +96,14 @@ type TraktItemSpec struct {
 type TraktItemSpecs []TraktItemSpec
 
 type TraktItem struct {
-	Type    string        `json:"type"`
-	RatedAt string        `json:"rated_at,omitempty"`
-	Rating  int           `json:"rating,omitempty"`
-	Movie   TraktItemSpec `json:"movie,omitempty"`
-	Show    TraktItemSpec `json:"show,omitempty"`
-	Episode TraktItemSpec `json:"episode,omitempty"`
+	Type      string        `json:"type"`
+	RatedAt   string        `json:"rated_at,omitempty"`
+	Rating    int           `json:"rating,omitempty"`
+	WatchedAt string        `json:"watched_at,omitempty"`
+	Movie     TraktItemSpec `json:"movie,omitempty"`
+	Show      TraktItemSpec `json:"show,omitempty"`
+	Episode   TraktItemSpec `json:"episode,omitempty"`
+	Reason    string        `json:"-"` // why the item is part of a difference, see ItemsDifference
 }
 
 type TraktItems []TraktItem
@@ -82,6 +123,157 @@ func (item *TraktItem) GetItemID() (*string, error) {
 	}
 }
 
+// historyKey identifies a play of the item, it is empty for items without an id, which can't be compared
+func (item *TraktItem) historyKey() string {
+	id, err := item.GetItemID()
+	if err != nil || id == nil || *id == "" {
+		return ""
+	}
+	return item.Type + "/" + *id + "@" + item.WatchedAt
+}
+
+// RemoveDuplicates drops the items which are repeated or already present in exclude, comparing them by type, id and watched date
+// It returns the remaining items and the number of dropped items
+func (items TraktItems) RemoveDuplicates(exclude TraktItems) (TraktItems, int) {
+	seen := make(map[string]struct{}, len(items)+len(exclude))
+	for i := range exclude {
+		if key := exclude[i].historyKey(); key != "" {
+			seen[key] = struct{}{}
+		}
+	}
+	unique := make(TraktItems, 0, len(items))
+	for i := range items {
+		key := items[i].historyKey()
+		if key != "" {
+			if _, found := seen[key]; found {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		unique = append(unique, items[i])
+	}
+	return unique, len(items) - len(unique)
+}
+
+// Label describes the item in a human-readable way, e.g. Dunkirk (2017) [tt5013056]
+func (item *TraktItem) Label() string {
+	var spec TraktItemSpec
+	switch item.Type {
+	case TraktItemTypeMovie:
+		spec = item.Movie
+	case TraktItemTypeShow:
+		spec = item.Show
+	case TraktItemTypeEpisode:
+		spec = item.Episode
+		if item.Show.Title != "" {
+			spec.Title = fmt.Sprintf("%s: %s", item.Show.Title, spec.Title)
+			spec.Year = item.Show.Year
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(spec.Title)
+	if spec.Year != 0 {
+		sb.WriteString(fmt.Sprintf(" (%d)", spec.Year))
+	}
+	if id := spec.IDMeta.IMDb; id != "" {
+		if sb.Len() != 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(fmt.Sprintf("[%s]", id))
+	}
+	if sb.Len() == 0 {
+		return item.Type
+	}
+	return sb.String()
+}
+
+func (items TraktItems) Labels() []string {
+	labels := make([]string, 0, len(items))
+	for i := range items {
+		labels = append(labels, items[i].Label())
+	}
+	return labels
+}
+
+// ExplainedLabels returns the labels of the items followed by the reason they are part of a difference, when known
+func (items TraktItems) ExplainedLabels() []string {
+	labels := make([]string, 0, len(items))
+	for i := range items {
+		label := items[i].Label()
+		if items[i].Reason != "" {
+			label = fmt.Sprintf("%s: %s", label, items[i].Reason)
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// WatchedAtTime returns when the item was watched, as set by SetWatchedAt or reported by trakt, reporting false for items without a watched date
+func (item *TraktItem) WatchedAtTime() (time.Time, bool) {
+	watchedAt := item.WatchedAt
+	var spec *string
+	switch item.Type {
+	case TraktItemTypeMovie:
+		spec = item.Movie.WatchedAt
+	case TraktItemTypeShow:
+		spec = item.Show.WatchedAt
+	case TraktItemTypeEpisode:
+		spec = item.Episode.WatchedAt
+	}
+	if spec != nil && *spec != "" {
+		watchedAt = *spec
+	}
+	for _, layout := range []string{time.RFC3339Nano, watchedAtLayout} {
+		if t, err := time.Parse(layout, watchedAt); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ChronologicalBatches orders the items by watched date and splits them into batches of the given size, a size of 0 or less keeps them in a single batch
+// The items without a watched date are kept in their order after the dated ones, since trakt dates them to the time they're added
+func (items TraktItems) ChronologicalBatches(size int) []TraktItems {
+	if len(items) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b TraktItem) int {
+		timeA, datedA := a.WatchedAtTime()
+		timeB, datedB := b.WatchedAtTime()
+		switch {
+		case datedA && datedB:
+			return timeA.Compare(timeB)
+		case datedA:
+			return -1
+		case datedB:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if size <= 0 {
+		return []TraktItems{sorted}
+	}
+	var batches []TraktItems
+	for size < len(sorted) {
+		sorted, batches = sorted[size:], append(batches, sorted[:size:size])
+	}
+	return append(batches, sorted)
+}
+
+func (item *TraktItem) SetWatchedAt(watchedAt time.Time) {
+	watchedAtStr := watchedAt.UTC().String()
+	switch item.Type {
+	case TraktItemTypeMovie:
+		item.Movie.WatchedAt = &watchedAtStr
+	case TraktItemTypeShow:
+		item.Show.WatchedAt = &watchedAtStr
+	case TraktItemTypeEpisode:
+		item.Episode.WatchedAt = &watchedAtStr
+	}
+}
+
 type TraktListBody struct {
 	Movies   TraktItemSpecs `json:"movies,omitempty"`
 	Shows    TraktItemSpecs `json:"shows,omitempty"`
@@ -98,12 +290,63 @@ type TraktListAddBody struct {
 	SortHow        string `json:"sort_how"`
 }
 
+type TraktCommentBody struct {
+	Movie   *TraktItemSpec `json:"movie,omitempty"`
+	Show    *TraktItemSpec `json:"show,omitempty"`
+	Episode *TraktItemSpec `json:"episode,omitempty"`
+	Comment string         `json:"comment"`
+	Spoiler bool           `json:"spoiler"`
+}
+
+type TraktScrobbleBody struct {
+	Movie    *TraktItemSpec `json:"movie,omitempty"`
+	Episode  *TraktItemSpec `json:"episode,omitempty"`
+	Progress float64        `json:"progress"`
+}
+
+type TraktListUpdateBody struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Privacy     *string `json:"privacy,omitempty"`
+	SortBy      *string `json:"sort_by,omitempty"`
+	SortHow     *string `json:"sort_how,omitempty"`
+}
+
 type TraktCrudItem struct {
 	Movies   int `json:"movies,omitempty"`
 	Shows    int `json:"shows,omitempty"`
 	Episodes int `json:"episodes,omitempty"`
 }
 
+func (c *TraktCrudItem) Total() int {
+	if c == nil {
+		return 0
+	}
+	return c.Movies + c.Shows + c.Episodes
+}
+
+func (c *TraktCrudItem) merge(other *TraktCrudItem) *TraktCrudItem {
+	if other == nil {
+		return c
+	}
+	if c == nil {
+		c = &TraktCrudItem{}
+	}
+	c.Movies += other.Movies
+	c.Shows += other.Shows
+	c.Episodes += other.Episodes
+	return c
+}
+
+type TraktLastActivities struct {
+	Lists struct {
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"lists"`
+	Watchlist struct {
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"watchlist"`
+}
+
 type TraktResponse struct {
 	Added    *TraktCrudItem `json:"added,omitempty"`
 	Deleted  *TraktCrudItem `json:"deleted,omitempty"`
@@ -111,8 +354,46 @@ type TraktResponse struct {
 	NotFound *TraktListBody `json:"not_found,omitempty"`
 }
 
+// Merge adds the counts and the items not found of another response to the response, e.g. to summarise the responses to multiple batches
+func (r *TraktResponse) Merge(other *TraktResponse) {
+	if other == nil {
+		return
+	}
+	r.Added = r.Added.merge(other.Added)
+	r.Deleted = r.Deleted.merge(other.Deleted)
+	r.Existing = r.Existing.merge(other.Existing)
+	if other.NotFound != nil {
+		if r.NotFound == nil {
+			r.NotFound = &TraktListBody{}
+		}
+		r.NotFound.Movies = append(r.NotFound.Movies, other.NotFound.Movies...)
+		r.NotFound.Shows = append(r.NotFound.Shows, other.NotFound.Shows...)
+		r.NotFound.Episodes = append(r.NotFound.Episodes, other.NotFound.Episodes...)
+	}
+}
+
+// NotFoundIDs returns the imdb ids of the items trakt couldn't find
+func (r *TraktResponse) NotFoundIDs() map[string]struct{} {
+	ids := make(map[string]struct{})
+	if r == nil || r.NotFound == nil {
+		return ids
+	}
+	for _, specs := range []TraktItemSpecs{r.NotFound.Movies, r.NotFound.Shows, r.NotFound.Episodes} {
+		for _, spec := range specs {
+			if spec.IDMeta.IMDb != "" {
+				ids[spec.IDMeta.IMDb] = struct{}{}
+			}
+		}
+	}
+	return ids
+}
+
 type TraktList struct {
 	Name        *string     `json:"name,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	Privacy     string      `json:"privacy,omitempty"`
+	SortBy      string      `json:"sort_by,omitempty"`
+	SortHow     string      `json:"sort_how,omitempty"`
 	IDMeta      TraktIDMeta `json:"ids"`
 	ListItems   TraktItems
 	IsWatchlist bool
@@ -0,0 +1,229 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraktItems_RemoveDuplicates(t *testing.T) {
+	type args struct {
+		exclude TraktItems
+	}
+	movie := func(id, watchedAt string) TraktItem {
+		return TraktItem{
+			Type:      TraktItemTypeMovie,
+			WatchedAt: watchedAt,
+			Movie: TraktItemSpec{
+				IDMeta: TraktIDMeta{
+					IMDb: id,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		items      TraktItems
+		args       args
+		assertions func(*assert.Assertions, TraktItems, int)
+	}{
+		{
+			name:  "keep unique items",
+			items: TraktItems{movie("tt0000001", ""), movie("tt0000002", "")},
+			assertions: func(assertions *assert.Assertions, items TraktItems, duplicates int) {
+				assertions.Equal(TraktItems{movie("tt0000001", ""), movie("tt0000002", "")}, items)
+				assertions.Zero(duplicates)
+			},
+		},
+		{
+			name:  "drop repeated items",
+			items: TraktItems{movie("tt0000001", ""), movie("tt0000001", ""), movie("tt0000001", "")},
+			assertions: func(assertions *assert.Assertions, items TraktItems, duplicates int) {
+				assertions.Equal(TraktItems{movie("tt0000001", "")}, items)
+				assertions.Equal(2, duplicates)
+			},
+		},
+		{
+			name:  "keep plays of the same item watched at different times",
+			items: TraktItems{movie("tt0000001", "2024-01-01T00:00:00Z"), movie("tt0000001", "2024-02-01T00:00:00Z"), movie("tt0000001", "2024-01-01T00:00:00Z")},
+			assertions: func(assertions *assert.Assertions, items TraktItems, duplicates int) {
+				assertions.Equal(TraktItems{movie("tt0000001", "2024-01-01T00:00:00Z"), movie("tt0000001", "2024-02-01T00:00:00Z")}, items)
+				assertions.Equal(1, duplicates)
+			},
+		},
+		{
+			name:  "drop excluded items",
+			items: TraktItems{movie("tt0000001", ""), movie("tt0000002", "")},
+			args: args{
+				exclude: TraktItems{movie("tt0000001", "")},
+			},
+			assertions: func(assertions *assert.Assertions, items TraktItems, duplicates int) {
+				assertions.Equal(TraktItems{movie("tt0000002", "")}, items)
+				assertions.Equal(1, duplicates)
+			},
+		},
+		{
+			name: "keep items without an id",
+			items: TraktItems{
+				{Type: TraktItemTypeSeason},
+				{Type: TraktItemTypeSeason},
+			},
+			assertions: func(assertions *assert.Assertions, items TraktItems, duplicates int) {
+				assertions.Len(items, 2)
+				assertions.Zero(duplicates)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, duplicates := tt.items.RemoveDuplicates(tt.args.exclude)
+			tt.assertions(assert.New(t), items, duplicates)
+		})
+	}
+}
+
+func TestTraktItems_ChronologicalBatches(t *testing.T) {
+	type args struct {
+		size int
+	}
+	movie := func(id string, watchedAt *time.Time) TraktItem {
+		item := TraktItem{
+			Type: TraktItemTypeMovie,
+			Movie: TraktItemSpec{
+				IDMeta: TraktIDMeta{
+					IMDb: id,
+				},
+			},
+		}
+		if watchedAt != nil {
+			item.SetWatchedAt(*watchedAt)
+		}
+		return item
+	}
+	day := func(d int) *time.Time {
+		t := time.Date(2024, time.January, d, 20, 0, 0, 0, time.UTC)
+		return &t
+	}
+	ids := func(batches []TraktItems) [][]string {
+		var result [][]string
+		for _, batch := range batches {
+			var batchIDs []string
+			for _, item := range batch {
+				id, _ := item.GetItemID()
+				batchIDs = append(batchIDs, *id)
+			}
+			result = append(result, batchIDs)
+		}
+		return result
+	}
+	tests := []struct {
+		name       string
+		items      TraktItems
+		args       args
+		assertions func(*assert.Assertions, []TraktItems)
+	}{
+		{
+			name: "batch items in the order they were watched",
+			items: TraktItems{
+				movie("tt0000003", day(3)),
+				movie("tt0000001", day(1)),
+				movie("tt0000005", day(5)),
+				movie("tt0000002", day(2)),
+				movie("tt0000004", day(4)),
+			},
+			args: args{
+				size: 2,
+			},
+			assertions: func(assertions *assert.Assertions, batches []TraktItems) {
+				assertions.Equal([][]string{{"tt0000001", "tt0000002"}, {"tt0000003", "tt0000004"}, {"tt0000005"}}, ids(batches))
+			},
+		},
+		{
+			name: "keep items without a watched date last, in their order",
+			items: TraktItems{
+				movie("tt0000009", nil),
+				movie("tt0000002", day(2)),
+				movie("tt0000008", nil),
+				movie("tt0000001", day(1)),
+			},
+			args: args{
+				size: 3,
+			},
+			assertions: func(assertions *assert.Assertions, batches []TraktItems) {
+				assertions.Equal([][]string{{"tt0000001", "tt0000002", "tt0000009"}, {"tt0000008"}}, ids(batches))
+			},
+		},
+		{
+			name: "order items dated by trakt and by the syncer together",
+			items: TraktItems{
+				movie("tt0000002", day(2)),
+				{
+					Type:      TraktItemTypeMovie,
+					WatchedAt: "2024-01-01T20:00:00.000Z",
+					Movie:     TraktItemSpec{IDMeta: TraktIDMeta{IMDb: "tt0000001"}},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, batches []TraktItems) {
+				assertions.Equal([][]string{{"tt0000001", "tt0000002"}}, ids(batches))
+			},
+		},
+		{
+			name: "keep all items in a single batch without a size",
+			items: TraktItems{
+				movie("tt0000002", day(2)),
+				movie("tt0000001", day(1)),
+			},
+			assertions: func(assertions *assert.Assertions, batches []TraktItems) {
+				assertions.Equal([][]string{{"tt0000001", "tt0000002"}}, ids(batches))
+			},
+		},
+		{
+			name: "return no batches without items",
+			args: args{
+				size: 2,
+			},
+			assertions: func(assertions *assert.Assertions, batches []TraktItems) {
+				assertions.Empty(batches)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.items.ChronologicalBatches(tt.args.size))
+		})
+	}
+}
+
+func TestTraktResponse_Merge(t *testing.T) {
+	summary := &TraktResponse{}
+	summary.Merge(&TraktResponse{
+		Added: &TraktCrudItem{
+			Movies: 2,
+		},
+		NotFound: &TraktListBody{
+			Movies: TraktItemSpecs{
+				{IDMeta: TraktIDMeta{IMDb: "tt0000001"}},
+			},
+		},
+	})
+	summary.Merge(&TraktResponse{
+		Added: &TraktCrudItem{
+			Shows: 1,
+		},
+		Existing: &TraktCrudItem{
+			Episodes: 3,
+		},
+		NotFound: &TraktListBody{
+			Shows: TraktItemSpecs{
+				{IDMeta: TraktIDMeta{IMDb: "tt0000002"}},
+			},
+		},
+	})
+	summary.Merge(nil)
+	assertions := assert.New(t)
+	assertions.Equal(3, summary.Added.Total())
+	assertions.Equal(3, summary.Existing.Total())
+	assertions.Zero(summary.Deleted.Total())
+	assertions.Equal(map[string]struct{}{"tt0000001": {}, "tt0000002": {}}, summary.NotFoundIDs())
+}
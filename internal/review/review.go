@@ -0,0 +1,194 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	ActionAdd    = "add"
+	ActionRemove = "remove"
+	ActionUpdate = "update"
+)
+
+// Change is a single item about to be added to, removed from or updated in a trakt target, e.g. a list or the ratings
+type Change struct {
+	Target   string
+	Action   string
+	ID       string
+	Label    string
+	Approved bool
+}
+
+type Model struct {
+	cursor  int
+	offset  int
+	height  int
+	changes []Change
+	err     error
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch message := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = message.Height
+	case tea.KeyMsg:
+		switch message.String() {
+		case "esc", "ctrl+c":
+			m.err = ErrUserAborted
+			return m, tea.Quit
+		case "enter":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.changes)-1 {
+				m.cursor++
+			}
+		case " ", "x":
+			if len(m.changes) != 0 {
+				m.changes[m.cursor].Approved = !m.changes[m.cursor].Approved
+			}
+		case "a":
+			m.setTarget(m.cursor, true)
+		case "r":
+			m.setTarget(m.cursor, false)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) View() string {
+	var sb strings.Builder
+	if len(m.changes) == 0 {
+		sb.WriteString("nothing to review\n")
+		sb.WriteString(helpStyle.Render(helpMessage))
+		return sb.String()
+	}
+	m.scroll()
+	start, end := m.offset, min(m.offset+m.visibleRows(), len(m.changes))
+	for i := start; i < end; i++ {
+		change := m.changes[i]
+		if i == start || change.Target != m.changes[i-1].Target {
+			sb.WriteString(headerStyle.Render(fmt.Sprintf("%s (%d approved of %d)", change.Target, m.approvedFor(change.Target), m.countFor(change.Target))) + "\n")
+		}
+		check := "[ ]"
+		if change.Approved {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %s %s", check, actionSymbols[change.Action], change.Label)
+		if i == m.cursor {
+			sb.WriteString(focusedStyle.Render("> "+line) + "\n")
+			continue
+		}
+		sb.WriteString("  " + line + "\n")
+	}
+	sb.WriteString(helpStyle.Render(helpMessage))
+	return sb.String()
+}
+
+func (m *Model) Err() error {
+	return m.err
+}
+
+// Rejected returns the changes left unapproved once the review is confirmed
+func (m *Model) Rejected() []Change {
+	var rejected []Change
+	for _, change := range m.changes {
+		if !change.Approved {
+			rejected = append(rejected, change)
+		}
+	}
+	return rejected
+}
+
+// setTarget approves or rejects all changes of the target the change at index i belongs to
+func (m *Model) setTarget(i int, approved bool) {
+	if len(m.changes) == 0 {
+		return
+	}
+	target := m.changes[i].Target
+	for j := range m.changes {
+		if m.changes[j].Target == target {
+			m.changes[j].Approved = approved
+		}
+	}
+}
+
+func (m *Model) approvedFor(target string) int {
+	var count int
+	for _, change := range m.changes {
+		if change.Target == target && change.Approved {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *Model) countFor(target string) int {
+	var count int
+	for _, change := range m.changes {
+		if change.Target == target {
+			count++
+		}
+	}
+	return count
+}
+
+// visibleRows leaves room for the target headers and the help message, all changes are shown until the terminal size is known
+func (m *Model) visibleRows() int {
+	if m.height == 0 {
+		return len(m.changes)
+	}
+	return max(m.height/2, 1)
+}
+
+// scroll keeps the cursor within the visible rows
+func (m *Model) scroll() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// NewTeaProgram shows the changes grouped by target in the given order, all of them approved until rejected
+func NewTeaProgram(changes []Change, opts ...tea.ProgramOption) *tea.Program {
+	m := Model{
+		changes: make([]Change, len(changes)),
+	}
+	for i, change := range changes {
+		change.Approved = true
+		m.changes[i] = change
+	}
+	return tea.NewProgram(&m, opts...)
+}
+
+const helpMessage = "\n—— ↑/↓ move —— SPACE toggle —— A approve target —— R reject target —— ENTER apply —— ESC abort ——\n"
+
+var (
+	actionSymbols = map[string]string{
+		ActionAdd:    "+",
+		ActionRemove: "-",
+		ActionUpdate: "~",
+	}
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#6200EE",
+		Dark:  "#BB86FC",
+	})
+	headerStyle    = lipgloss.NewStyle().Bold(true)
+	helpStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	ErrUserAborted = errors.New("user aborted")
+)
@@ -0,0 +1,233 @@
+package review
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func dummyChanges() []Change {
+	return []Change{
+		{Target: "list watched", Action: ActionAdd, ID: "tt1", Label: "One (2001) [tt1]", Approved: true},
+		{Target: "list watched", Action: ActionRemove, ID: "tt2", Label: "Two (2002) [tt2]", Approved: true},
+		{Target: "ratings", Action: ActionUpdate, ID: "tt3", Label: "Three (2003) [tt3]", Approved: true},
+	}
+}
+
+func TestModel_Update(t *testing.T) {
+	type fields struct {
+		cursor  int
+		changes []Change
+	}
+	type args struct {
+		msg tea.Msg
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		assertions func(*assert.Assertions, tea.Model, tea.Cmd)
+	}{
+		{
+			name: "escape button pressed",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyEsc},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Equal(ErrUserAborted, model.(*Model).err)
+				a.IsType(tea.QuitMsg{}, cmd())
+			},
+		},
+		{
+			name: "enter button pressed",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyEnter},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Nil(model.(*Model).err)
+				a.IsType(tea.QuitMsg{}, cmd())
+			},
+		},
+		{
+			name: "down button pressed",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyDown},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Equal(1, model.(*Model).cursor)
+				a.Nil(cmd)
+			},
+		},
+		{
+			name: "down button pressed on the last change",
+			fields: fields{
+				cursor:  2,
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyDown},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Equal(2, model.(*Model).cursor)
+			},
+		},
+		{
+			name: "up button pressed on the first change",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyUp},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Equal(0, model.(*Model).cursor)
+			},
+		},
+		{
+			name: "space button pressed",
+			fields: fields{
+				cursor:  1,
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				m := model.(*Model)
+				a.True(m.changes[0].Approved)
+				a.False(m.changes[1].Approved)
+				a.Equal([]Change{m.changes[1]}, m.Rejected())
+			},
+		},
+		{
+			name: "target rejected",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				m := model.(*Model)
+				a.Len(m.Rejected(), 2)
+				a.True(m.changes[2].Approved)
+			},
+		},
+		{
+			name: "target approved",
+			fields: fields{
+				cursor: 2,
+				changes: func() []Change {
+					changes := dummyChanges()
+					changes[2].Approved = false
+					return changes
+				}(),
+			},
+			args: args{
+				msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Empty(model.(*Model).Rejected())
+			},
+		},
+		{
+			name: "window resized",
+			fields: fields{
+				changes: dummyChanges(),
+			},
+			args: args{
+				msg: tea.WindowSizeMsg{Width: 80, Height: 24},
+			},
+			assertions: func(a *assert.Assertions, model tea.Model, cmd tea.Cmd) {
+				a.Equal(24, model.(*Model).height)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{
+				cursor:  tt.fields.cursor,
+				changes: tt.fields.changes,
+			}
+			model, cmd := m.Update(tt.args.msg)
+			tt.assertions(assert.New(t), model, cmd)
+		})
+	}
+}
+
+func TestModel_View(t *testing.T) {
+	type fields struct {
+		cursor  int
+		height  int
+		changes []Change
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		assertions func(*assert.Assertions, string)
+	}{
+		{
+			name: "changes grouped by target",
+			fields: fields{
+				changes: func() []Change {
+					changes := dummyChanges()
+					changes[1].Approved = false
+					return changes
+				}(),
+			},
+			assertions: func(a *assert.Assertions, view string) {
+				a.Contains(view, "list watched (1 approved of 2)")
+				a.Contains(view, "ratings (1 approved of 1)")
+				a.Contains(view, "> [x] + One (2001) [tt1]")
+				a.Contains(view, "  [ ] - Two (2002) [tt2]")
+				a.Contains(view, "  [x] ~ Three (2003) [tt3]")
+			},
+		},
+		{
+			name: "changes scrolled to the cursor",
+			fields: fields{
+				cursor:  2,
+				height:  2,
+				changes: dummyChanges(),
+			},
+			assertions: func(a *assert.Assertions, view string) {
+				a.NotContains(view, "One (2001)")
+				a.Contains(view, "Three (2003)")
+			},
+		},
+		{
+			name: "nothing to review",
+			assertions: func(a *assert.Assertions, view string) {
+				a.Contains(view, "nothing to review")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Model{
+				cursor:  tt.fields.cursor,
+				height:  tt.fields.height,
+				changes: tt.fields.changes,
+			}
+			tt.assertions(assert.New(t), m.View())
+		})
+	}
+}
+
+func TestNewTeaProgram(t *testing.T) {
+	changes := dummyChanges()
+	changes[0].Approved = false
+	program := NewTeaProgram(changes)
+	assert.NotNil(t, program)
+	assert.False(t, changes[0].Approved)
+}
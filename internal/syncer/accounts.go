@@ -0,0 +1,169 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+// imdbAccount is an additional imdb account, whose watchlist, ratings and lists are merged with the ones of the main account
+type imdbAccount struct {
+	name   string
+	client client.IMDbClientInterface
+}
+
+func newIMDbAccounts(ctx context.Context, conf *appconfig.Config, log *slog.Logger) ([]imdbAccount, error) {
+	accounts := make([]imdbAccount, 0, len(conf.IMDb.Accounts))
+	for _, name := range conf.IMDb.AccountNames() {
+		accountConf := conf.IMDb.Account(name)
+		newIMDbClient := client.NewIMDbClient
+		if accountConf.IsOffline() {
+			newIMDbClient = client.NewIMDbOfflineClient
+		}
+		accountClient, err := newIMDbClient(accountConf, conf.HTTP, log.With(slog.String("imdb_account", name)))
+		if err != nil {
+			return nil, fmt.Errorf("failure initialising imdb client of account %s: %w", name, err)
+		}
+		if err = accountClient.Hydrate(ctx); err != nil {
+			return nil, fmt.Errorf("failure hydrating imdb client of account %s: %w", name, err)
+		}
+		accounts = append(accounts, imdbAccount{name: name, client: accountClient})
+	}
+	return accounts, nil
+}
+
+// imdbRatingsGet returns the ratings of all imdb accounts, resolving the items rated differently by the accounts using the conflict policy
+func (s *Syncer) imdbRatingsGet(ctx context.Context) ([]entities.IMDbItem, error) {
+	ratings, err := s.imdbClient.RatingsGet(ctx)
+	if err != nil || len(s.imdbAccounts) == 0 {
+		return ratings, err
+	}
+	merged := make(map[string]int, len(ratings))
+	for i := range ratings {
+		merged[ratings[i].ID] = i
+	}
+	policy := s.imdbConf.AccountsConflictPolicy()
+	for _, account := range s.imdbAccounts {
+		accountRatings, err := account.client.RatingsGet(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching imdb ratings of account %s: %w", account.name, err)
+		}
+		var added, conflicts int
+		for _, rating := range accountRatings {
+			i, found := merged[rating.ID]
+			if !found {
+				merged[rating.ID] = len(ratings)
+				ratings = append(ratings, rating)
+				added++
+				continue
+			}
+			if ratings[i].Rating == nil || rating.Rating == nil || *ratings[i].Rating == *rating.Rating {
+				continue
+			}
+			conflicts++
+			if accountRatingWins(policy, ratings[i], rating) {
+				ratings[i] = rating
+			}
+		}
+		s.logger.Info(fmt.Sprintf("merged %d imdb rating(s) of account %s, resolved %d conflicting rating(s) using policy %s", added, account.name, conflicts, policy))
+	}
+	return ratings, nil
+}
+
+// accountRatingWins reports whether the rating of a later imdb account should replace the rating merged so far
+func accountRatingWins(policy string, merged, rating entities.IMDbItem) bool {
+	switch policy {
+	case appconfig.AccountsConflictHighest:
+		return *rating.Rating > *merged.Rating
+	case appconfig.AccountsConflictLowest:
+		return *rating.Rating < *merged.Rating
+	case appconfig.AccountsConflictMostRecent:
+		// imdb only records the day of rating, so the earlier account wins ratings submitted on the same day
+		return rating.RatingDate != nil && (merged.RatingDate == nil || rating.RatingDate.After(*merged.RatingDate))
+	default:
+		return false
+	}
+}
+
+// imdbWatchlistGet returns the watchlist of the main imdb account, along with the items of the watchlists of the other accounts
+func (s *Syncer) imdbWatchlistGet(ctx context.Context) (*entities.IMDbList, error) {
+	watchlist, err := s.imdbClient.WatchlistGet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range s.imdbAccounts {
+		accountWatchlist, err := account.client.WatchlistGet(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching imdb watchlist of account %s: %w", account.name, err)
+		}
+		watchlist.ListItems = append(watchlist.ListItems, accountWatchlist.ListItems...)
+		watchlist.Partial = watchlist.Partial || accountWatchlist.Partial
+	}
+	return watchlist, nil
+}
+
+// imdbListsGet fetches the lists from the main imdb account, falling back to the other accounts for the lists it can't access, e.g. private ones
+func (s *Syncer) imdbListsGet(ctx context.Context, listIDs []string) ([]entities.IMDbList, []error) {
+	lists, delegatedErrors := s.imdbClient.ListsGet(ctx, listIDs)
+	if len(s.imdbAccounts) == 0 {
+		return lists, delegatedErrors
+	}
+	var remaining []error
+	for _, delegatedErr := range delegatedErrors {
+		var listErr *client.IMDbListError
+		if !errors.As(delegatedErr, &listErr) {
+			remaining = append(remaining, delegatedErr)
+			continue
+		}
+		list, found := s.imdbAccountsListGet(ctx, listErr.ListID)
+		if !found {
+			remaining = append(remaining, delegatedErr)
+			continue
+		}
+		lists = append(lists, *list)
+	}
+	return lists, remaining
+}
+
+func (s *Syncer) imdbAccountsListGet(ctx context.Context, listID string) (*entities.IMDbList, bool) {
+	for _, account := range s.imdbAccounts {
+		list, err := account.client.ListGet(ctx, listID)
+		if err != nil {
+			s.logger.Debug(fmt.Sprintf("imdb list %s could not be fetched from account %s", listID, account.name), logger.Error(err))
+			continue
+		}
+		s.logger.Debug(fmt.Sprintf("fetched imdb list %s from account %s", listID, account.name))
+		return list, true
+	}
+	return nil, false
+}
+
+// imdbListsGetAll returns the lists of all imdb accounts, a list shared by multiple accounts is only returned once
+func (s *Syncer) imdbListsGetAll(ctx context.Context) ([]entities.IMDbList, []error) {
+	lists, delegatedErrors := s.imdbClient.ListsGetAll(ctx)
+	if len(s.imdbAccounts) == 0 {
+		return lists, delegatedErrors
+	}
+	seen := make(map[string]struct{}, len(lists))
+	for _, list := range lists {
+		seen[list.ListID] = struct{}{}
+	}
+	for _, account := range s.imdbAccounts {
+		accountLists, accountErrors := account.client.ListsGetAll(ctx)
+		delegatedErrors = append(delegatedErrors, accountErrors...)
+		for _, list := range accountLists {
+			if _, found := seen[list.ListID]; found {
+				continue
+			}
+			seen[list.ListID] = struct{}{}
+			lists = append(lists, list)
+		}
+	}
+	return lists, delegatedErrors
+}
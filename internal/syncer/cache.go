@@ -0,0 +1,139 @@
+package syncer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// listsCache keeps the contents of trakt lists between runs, keyed by list slug
+// the cached lists are only valid while the trakt lists activity timestamp stays the same
+// it also records the state of the last successful watchlist sync, which can be skipped while neither watchlist changes,
+// and the fingerprints of the imdb lists synced successfully, keyed by imdb list id, which are skipped while neither they nor the trakt lists change
+type listsCache struct {
+	path                 string
+	ListsUpdatedAt       time.Time                     `json:"lists_updated_at"`
+	Lists                map[string]entities.TraktList `json:"lists"`
+	WatchlistUpdatedAt   time.Time                     `json:"watchlist_updated_at"`
+	WatchlistFingerprint string                        `json:"watchlist_fingerprint,omitempty"`
+	ListFingerprints     map[string]string             `json:"list_fingerprints,omitempty"`
+}
+
+// profilePath appends the profile name to the file name, keeping the files of different profiles apart
+func profilePath(path, profile string) string {
+	if profile == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), profile, ext)
+}
+
+func newListsCache(path string) *listsCache {
+	return &listsCache{
+		path:  path,
+		Lists: make(map[string]entities.TraktList),
+	}
+}
+
+func loadListsCache(path string) (*listsCache, error) {
+	cache := newListsCache(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("failure reading lists cache file %s: %w", path, err)
+	}
+	if err = json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failure decoding lists cache file %s: %w", path, err)
+	}
+	if cache.Lists == nil {
+		cache.Lists = make(map[string]entities.TraktList)
+	}
+	return cache, nil
+}
+
+// lookup splits the requested lists into the ones that can be served from the cache and the ones that need fetching
+func (c *listsCache) lookup(updatedAt time.Time, idsMeta entities.TraktIDMetas) ([]entities.TraktList, entities.TraktIDMetas) {
+	if !c.ListsUpdatedAt.Equal(updatedAt) {
+		c.ListsUpdatedAt = updatedAt
+		c.Lists = make(map[string]entities.TraktList)
+		return nil, idsMeta
+	}
+	var (
+		cached  []entities.TraktList
+		missing entities.TraktIDMetas
+	)
+	for _, idMeta := range idsMeta {
+		list, found := c.Lists[idMeta.Slug]
+		if !found {
+			missing = append(missing, idMeta)
+			continue
+		}
+		list.IDMeta = idMeta
+		cached = append(cached, list)
+	}
+	return cached, missing
+}
+
+func (c *listsCache) store(lists []entities.TraktList) {
+	for _, list := range lists {
+		c.Lists[list.IDMeta.Slug] = list
+	}
+}
+
+// fingerprint hashes the json encoding of a value, which sorts map keys, hence equal values always have the same fingerprint
+func fingerprint(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *listsCache) invalidate(slug string) {
+	delete(c.Lists, slug)
+}
+
+// watchlistUnchanged reports whether the watchlist was synced successfully by the previous run, and neither the trakt watchlist nor the fingerprint changed since
+func (c *listsCache) watchlistUnchanged(updatedAt time.Time, fingerprint string) bool {
+	return c.WatchlistFingerprint != "" && c.WatchlistFingerprint == fingerprint && c.WatchlistUpdatedAt.Equal(updatedAt)
+}
+
+// syncedWatchlist records the state of a successful watchlist sync, an empty fingerprint forces the next run to sync the watchlist
+func (c *listsCache) syncedWatchlist(updatedAt time.Time, fingerprint string) {
+	c.WatchlistUpdatedAt = updatedAt
+	c.WatchlistFingerprint = fingerprint
+}
+
+// listUnchanged reports whether the imdb list was synced successfully by the previous run, and neither the trakt lists nor the fingerprint changed since
+// it must be called before lookup, which resets the trakt lists activity timestamp once the trakt lists changed
+func (c *listsCache) listUnchanged(updatedAt time.Time, listID, fingerprint string) bool {
+	return c.ListFingerprints[listID] != "" && c.ListFingerprints[listID] == fingerprint && c.ListsUpdatedAt.Equal(updatedAt)
+}
+
+// syncedLists replaces the fingerprints of the imdb lists synced successfully, the lists left out are synced again by the next run
+func (c *listsCache) syncedLists(fingerprints map[string]string) {
+	c.ListFingerprints = fingerprints
+}
+
+func (c *listsCache) save(updatedAt time.Time) error {
+	c.ListsUpdatedAt = updatedAt
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failure encoding lists cache: %w", err)
+	}
+	if err = os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failure writing lists cache file %s: %w", c.path, err)
+	}
+	return nil
+}
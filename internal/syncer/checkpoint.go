@@ -0,0 +1,97 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	checkpointHistoryAdd    = "history-add"
+	checkpointHistoryRemove = "history-remove"
+	checkpointCheckInsAdd   = "check-ins-add"
+
+	// the checkpoint is written after this many items are processed, besides the end of the run
+	checkpointSaveInterval = 100
+)
+
+// checkpoint records the items processed per step of the slow per-item categories, so that an interrupted sync skips them on the next run
+// a checkpoint is only resumed by a run using the same sync mode, and it is removed once a sync completes successfully
+type checkpoint struct {
+	path      string
+	unsaved   int
+	Mode      string                         `json:"mode"`
+	Processed map[string]map[string]struct{} `json:"processed"`
+}
+
+func loadCheckpoint(path, mode string) (*checkpoint, bool, error) {
+	cp := &checkpoint{
+		path:      path,
+		Mode:      mode,
+		Processed: make(map[string]map[string]struct{}),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cp, false, nil
+		}
+		return nil, false, fmt.Errorf("failure reading checkpoint file %s: %w", path, err)
+	}
+	var saved checkpoint
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return nil, false, fmt.Errorf("failure decoding checkpoint file %s: %w", path, err)
+	}
+	if saved.Mode != mode || saved.Processed == nil {
+		return cp, false, nil
+	}
+	cp.Processed = saved.Processed
+	return cp, true, nil
+}
+
+// size returns the number of processed items across all steps
+func (c *checkpoint) size() int {
+	var size int
+	for _, ids := range c.Processed {
+		size += len(ids)
+	}
+	return size
+}
+
+func (c *checkpoint) isProcessed(step, id string) bool {
+	_, found := c.Processed[step][id]
+	return found
+}
+
+// markProcessed records the items as processed, writing the checkpoint every checkpointSaveInterval items
+func (c *checkpoint) markProcessed(step string, ids ...string) error {
+	if c.Processed[step] == nil {
+		c.Processed[step] = make(map[string]struct{})
+	}
+	for _, id := range ids {
+		c.Processed[step][id] = struct{}{}
+	}
+	if c.unsaved += len(ids); c.unsaved < checkpointSaveInterval {
+		return nil
+	}
+	return c.save()
+}
+
+func (c *checkpoint) save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failure encoding checkpoint: %w", err)
+	}
+	if err = os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failure writing checkpoint file %s: %w", c.path, err)
+	}
+	c.unsaved = 0
+	return nil
+}
+
+func (c *checkpoint) clear() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failure removing checkpoint file %s: %w", c.path, err)
+	}
+	return nil
+}
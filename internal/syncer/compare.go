@@ -0,0 +1,67 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+// ComparisonRow counts the items of a category on both services, along with the items that differ between them
+type ComparisonRow struct {
+	Category string
+	IMDb     int
+	Trakt    int
+	Add      int
+	Remove   int
+	Update   int
+}
+
+// Compare hydrates the syncer and counts the items on both services, without changing anything on trakt
+// The syncer must be created in dry-run sync mode, otherwise hydrating would create the missing trakt lists
+func (s *Syncer) Compare(ctx context.Context) ([]ComparisonRow, error) {
+	if syncMode := *s.conf.Mode; syncMode != appconfig.SyncModeDryRun {
+		return nil, fmt.Errorf("comparing requires sync mode %s, got %s", appconfig.SyncModeDryRun, syncMode)
+	}
+	if err := s.hydrate(ctx); err != nil {
+		return nil, err
+	}
+	var watchlist, lists []ComparisonRow
+	for id, list := range s.user.imdbLists {
+		traktList := s.user.traktLists[id]
+		diff, err := s.listDifference(list)
+		if err != nil {
+			return nil, err
+		}
+		row := ComparisonRow{
+			Category: fmt.Sprintf("list %s", list.ListName),
+			IMDb:     len(list.ListItems),
+			Trakt:    len(traktList.ListItems),
+			Add:      len(diff["add"]),
+			Remove:   len(diff["remove"]),
+		}
+		if list.IsWatchlist {
+			row.Category = "watchlist"
+			watchlist = append(watchlist, row)
+			continue
+		}
+		lists = append(lists, row)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].Category < lists[j].Category
+	})
+	if s.imdbConf.IsAuthless() {
+		return append(watchlist, lists...), nil
+	}
+	diff := s.ratingsDifference()
+	ratings := ComparisonRow{
+		Category: "ratings",
+		IMDb:     len(s.user.imdbRatings),
+		Trakt:    len(s.user.traktRatings),
+		Add:      len(diff["add"]),
+		Remove:   len(diff["remove"]),
+		Update:   len(diff["update"]),
+	}
+	return append(append(watchlist, lists...), ratings), nil
+}
@@ -0,0 +1,106 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// duplicateMaxYearGap is the largest difference between the years of probable duplicates, e.g. for regional releases
+const duplicateMaxYearGap = 1
+
+// DuplicateItem is an imdb item along with the names of the lists it appears in
+type DuplicateItem struct {
+	Item      entities.IMDbItem
+	Locations []string
+}
+
+// DuplicatePair is a pair of distinct imdb items that are probably the same title, e.g. a regional version of a movie
+type DuplicatePair struct {
+	First      DuplicateItem
+	Second     DuplicateItem
+	Similarity float64
+}
+
+// Duplicates hydrates the syncer and reports the imdb items of the same type, with similar titles, released at most a year apart
+// The report is diagnostic only, nothing is changed on either service
+// The syncer must be created in dry-run sync mode, otherwise hydrating would create the missing trakt lists
+func (s *Syncer) Duplicates(ctx context.Context, threshold float64) ([]DuplicatePair, error) {
+	if syncMode := *s.conf.Mode; syncMode != appconfig.SyncModeDryRun {
+		return nil, fmt.Errorf("detecting duplicates requires sync mode %s, got %s", appconfig.SyncModeDryRun, syncMode)
+	}
+	if err := s.hydrate(ctx); err != nil {
+		return nil, err
+	}
+	items := s.duplicateCandidates()
+	var pairs []DuplicatePair
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			first, second := items[i].Item, items[j].Item
+			if first.ToTraktItem().Type != second.ToTraktItem().Type || !withinYearGap(first.Year, second.Year) {
+				continue
+			}
+			if similarity := entities.TitleSimilarity(first.Title, second.Title); similarity >= threshold {
+				pairs = append(pairs, DuplicatePair{
+					First:      items[i],
+					Second:     items[j],
+					Similarity: similarity,
+				})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Similarity > pairs[j].Similarity
+	})
+	return pairs, nil
+}
+
+// duplicateCandidates returns the distinct items of the imdb lists, watchlist and ratings, ordered by id
+func (s *Syncer) duplicateCandidates() []DuplicateItem {
+	candidates := make(map[string]*DuplicateItem)
+	add := func(item entities.IMDbItem, location string) {
+		candidate, found := candidates[item.ID]
+		if !found {
+			candidate = &DuplicateItem{
+				Item: item,
+			}
+			candidates[item.ID] = candidate
+		}
+		candidate.Locations = append(candidate.Locations, location)
+	}
+	for _, list := range s.user.imdbLists {
+		location := fmt.Sprintf("list %s", list.ListName)
+		if list.IsWatchlist {
+			location = "watchlist"
+		}
+		for _, item := range list.ListItems {
+			add(item, location)
+		}
+	}
+	for _, item := range s.user.imdbRatings {
+		add(item, "ratings")
+	}
+	items := make([]DuplicateItem, 0, len(candidates))
+	for _, candidate := range candidates {
+		sort.Strings(candidate.Locations)
+		items = append(items, *candidate)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Item.ID < items[j].Item.ID
+	})
+	return items
+}
+
+func withinYearGap(a, b int) bool {
+	if a == 0 || b == 0 {
+		return true
+	}
+	gap := a - b
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= duplicateMaxYearGap
+}
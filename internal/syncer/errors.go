@@ -0,0 +1,50 @@
+package syncer
+
+import (
+	"fmt"
+)
+
+// PartialSyncError is returned when one or more categories failed to sync, while at least one other category was synced.
+// Changes applied to trakt before the failure are not rolled back.
+type PartialSyncError struct {
+	Err error
+}
+
+func (e *PartialSyncError) Error() string {
+	return fmt.Sprintf("partial sync failure: %s", e.Err)
+}
+
+func (e *PartialSyncError) Unwrap() error {
+	return e.Err
+}
+
+// RemovalThresholdError is returned when a sync would remove more trakt items than the configured removal limits allow.
+type RemovalThresholdError struct {
+	Target string
+	Count  int
+	Total  int
+	Limit  string
+}
+
+func (e *RemovalThresholdError) Error() string {
+	if e.Total == 0 {
+		return fmt.Sprintf("refusing to remove %d item(s) from trakt %s, which exceeds the removal limit of %s, use --force to remove them anyway", e.Count, e.Target, e.Limit)
+	}
+	return fmt.Sprintf("refusing to remove %d out of %d item(s) from trakt %s, which exceeds the removal limit of %s, use --force to remove them anyway", e.Count, e.Total, e.Target, e.Limit)
+}
+
+// AdditionThresholdError is returned when a sync would add more trakt items than the configured addition limits allow,
+// which usually means an imdb list is mapped to the wrong trakt list.
+type AdditionThresholdError struct {
+	Target string
+	Count  int
+	Total  int
+	Limit  string
+}
+
+func (e *AdditionThresholdError) Error() string {
+	if e.Total == 0 {
+		return fmt.Sprintf("refusing to add %d item(s) to trakt %s, which exceeds the addition limit of %s, use --force to add them anyway", e.Count, e.Target, e.Limit)
+	}
+	return fmt.Sprintf("refusing to add %d item(s) to the %d item(s) of trakt %s, which exceeds the addition limit of %s, use --force to add them anyway", e.Count, e.Total, e.Target, e.Limit)
+}
@@ -0,0 +1,50 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// categoryHydration is the category of the errors raised while fetching the imdb and trakt data, before any category is synced
+const categoryHydration = "hydration"
+
+// RunError is a failure of a run, written as a json line to the errors file for automated runs to parse
+type RunError struct {
+	Category string `json:"category"`
+	Item     string `json:"item,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (s *Syncer) recordError(category, item string, err error) {
+	s.result.Errors = append(s.result.Errors, RunError{
+		Category: category,
+		Item:     item,
+		Message:  err.Error(),
+	})
+}
+
+// writeErrors replaces the errors file with one json line per error, the file is removed when the run had no errors
+// hence a file left over from a previous run never reports errors that no longer happen
+func writeErrors(path string, runErrors []RunError) error {
+	if len(runErrors) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failure removing errors file %s: %w", path, err)
+		}
+		return nil
+	}
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	for _, runError := range runErrors {
+		if err := encoder.Encode(runError); err != nil {
+			return fmt.Errorf("failure encoding error: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failure writing errors file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// ExportRow is a rated item, which the syncer considers watched on the day it was rated
+type ExportRow struct {
+	IMDbID      string
+	Title       string
+	Year        int
+	Rating      *int
+	WatchedDate *time.Time
+}
+
+// Export hydrates the syncer and returns the rated items of both services, without changing anything on trakt
+// Items rated on both services are exported with their imdb rating, since imdb is the source of the sync
+// The syncer must be created in dry-run sync mode, otherwise hydrating would create the missing trakt lists
+func (s *Syncer) Export(ctx context.Context) ([]ExportRow, error) {
+	if syncMode := *s.conf.Mode; syncMode != appconfig.SyncModeDryRun {
+		return nil, fmt.Errorf("exporting requires sync mode %s, got %s", appconfig.SyncModeDryRun, syncMode)
+	}
+	if err := s.hydrate(ctx); err != nil {
+		return nil, err
+	}
+	rows := make([]ExportRow, 0, len(s.user.imdbRatings))
+	for id, item := range s.user.imdbRatings {
+		rows = append(rows, ExportRow{
+			IMDbID:      id,
+			Title:       item.Title,
+			Year:        item.Year,
+			Rating:      item.Rating,
+			WatchedDate: item.RatingDate,
+		})
+	}
+	for id, item := range s.user.traktRatings {
+		if _, found := s.user.imdbRatings[id]; found {
+			continue
+		}
+		rows = append(rows, traktExportRow(id, item))
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].IMDbID < rows[j].IMDbID
+	})
+	return rows, nil
+}
+
+func traktExportRow(id string, item entities.TraktItem) ExportRow {
+	var spec entities.TraktItemSpec
+	switch item.Type {
+	case entities.TraktItemTypeMovie:
+		spec = item.Movie
+	case entities.TraktItemTypeShow:
+		spec = item.Show
+	case entities.TraktItemTypeEpisode:
+		spec = item.Episode
+	}
+	row := ExportRow{
+		IMDbID: id,
+		Title:  spec.Title,
+		Year:   spec.Year,
+	}
+	if item.Rating != 0 {
+		rating := item.Rating
+		row.Rating = &rating
+	}
+	if ratedAt, err := time.Parse(time.RFC3339, item.RatedAt); err == nil {
+		row.WatchedDate = &ratedAt
+	}
+	return row
+}
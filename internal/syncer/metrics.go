@@ -0,0 +1,74 @@
+package syncer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const metricsPrefix = "imdb_trakt_sync_"
+
+// writeMetrics writes the outcome of a run in the prometheus text format, as read by the node_exporter textfile collector
+// the file is replaced atomically, so that the collector never reads a partially written file
+func writeMetrics(path string, now time.Time, result *Result, syncErr error) error {
+	success := 1
+	if syncErr != nil {
+		success = 0
+	}
+	var b strings.Builder
+	writeMetric(&b, "last_run_timestamp_seconds", "Unix timestamp of the last sync run.")
+	fmt.Fprintf(&b, "%slast_run_timestamp_seconds %d\n", metricsPrefix, now.Unix())
+	writeMetric(&b, "last_run_success", "Whether the last sync run succeeded, 1 for success and 0 for failure.")
+	fmt.Fprintf(&b, "%slast_run_success{mode=%q} %d\n", metricsPrefix, result.Mode, success)
+	categories := []struct {
+		name   string
+		result CategoryResult
+	}{
+		{name: "lists", result: result.Lists},
+		{name: "watchlist", result: result.Watchlist},
+		{name: "ratings", result: result.Ratings},
+		{name: "history", result: result.History},
+		{name: "comments", result: result.Comments},
+	}
+	counts := []struct {
+		name  string
+		help  string
+		count func(CategoryResult) int
+	}{
+		{name: "items_added", help: "Number of items added to trakt by the last sync run.", count: func(c CategoryResult) int { return len(c.Added) }},
+		{name: "items_removed", help: "Number of items removed from trakt by the last sync run.", count: func(c CategoryResult) int { return len(c.Removed) }},
+		{name: "items_pending_add", help: "Number of items the last sync run would have added to trakt if the sync mode allowed it.", count: func(c CategoryResult) int { return len(c.PendingAdd) }},
+		{name: "items_pending_remove", help: "Number of items the last sync run would have removed from trakt if the sync mode allowed it.", count: func(c CategoryResult) int { return len(c.PendingRemove) }},
+	}
+	for _, count := range counts {
+		writeMetric(&b, count.name, count.help)
+		for _, category := range categories {
+			fmt.Fprintf(&b, "%s%s{category=%q} %d\n", metricsPrefix, count.name, category.name, count.count(category.result))
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failure creating temporary metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failure writing temporary metrics file %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failure closing temporary metrics file %s: %w", tmp.Name(), err)
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failure setting permissions of temporary metrics file %s: %w", tmp.Name(), err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failure replacing metrics file %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeMetric(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s%s %s\n# TYPE %s%s gauge\n", metricsPrefix, name, help, metricsPrefix, name)
+}
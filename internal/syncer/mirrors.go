@@ -0,0 +1,134 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+// hydrateWatchlistMirrors fetches the trakt lists the imdb watchlist is mirrored to, the missing ones are created unless in dry-run sync mode
+func (s *Syncer) hydrateWatchlistMirrors(ctx context.Context, imdbWatchlist entities.IMDbList) error {
+	if len(s.listsConf.WatchlistMirrors) == 0 {
+		return nil
+	}
+	s.user.watchlistMirrors = make(map[string]entities.TraktList, len(s.listsConf.WatchlistMirrors))
+	for _, slug := range s.listsConf.WatchlistMirrors {
+		mirror := entities.TraktList{
+			IDMeta: entities.TraktIDMeta{Slug: slug},
+		}
+		traktList, err := s.traktClient.ListGet(ctx, slug)
+		var notFoundErr *client.TraktListNotFoundError
+		switch {
+		case errors.As(err, &notFoundErr):
+			if syncMode := s.conf.ModeFor(appconfig.SyncCategoryWatchlist); syncMode == appconfig.SyncModeDryRun {
+				s.logger.Info(fmt.Sprintf("sync mode %s would have created trakt list %s mirroring the imdb watchlist", syncMode, slug))
+				break
+			}
+			created, err := s.traktClient.ListAdd(ctx, slug, slug, s.listsConf.PrivacyFor(imdbWatchlist.ListID), "", "")
+			if err != nil {
+				return fmt.Errorf("failure creating trakt list %s mirroring the imdb watchlist: %w", slug, err)
+			}
+			// trakt derives the slug from the list name, which may not reproduce the configured slug
+			if created.IDMeta.Slug != "" {
+				mirror = *created
+			}
+			if mirror.IDMeta.Slug != slug {
+				s.logger.Warn(fmt.Sprintf("trakt created list %s mirroring the imdb watchlist with slug %s, set the slug in config field 'LISTS_WATCHLISTMIRRORS' to keep using it", slug, mirror.IDMeta.Slug))
+			}
+		case err != nil:
+			return fmt.Errorf("failure fetching trakt list %s mirroring the imdb watchlist: %w", slug, err)
+		default:
+			mirror = *traktList
+			mirror.ListItems = s.withIMDbIDs(fmt.Sprintf("list %s", slug), traktList.ListItems, imdbWatchlist.ListItems)
+		}
+		s.user.watchlistMirrors[slug] = mirror
+	}
+	return nil
+}
+
+// syncWatchlistMirrors applies the changes of the imdb watchlist to the trakt lists it is mirrored to, following the watchlist sync mode
+// an item is removed from the trakt watchlist and the mirrors together, hence the removals of all of them are checked against the removal limits
+// before any of them is removed, the removals of the trakt watchlist itself are left to the caller
+func (s *Syncer) syncWatchlistMirrors(ctx context.Context, watchlist entities.IMDbList, watchlistRemovals entities.TraktItems) error {
+	if len(s.user.watchlistMirrors) == 0 {
+		return nil
+	}
+	syncMode := s.conf.ModeFor(appconfig.SyncCategoryWatchlist)
+	diffs := make(map[string]map[string]entities.TraktItems, len(s.user.watchlistMirrors))
+	for _, slug := range s.listsConf.WatchlistMirrors {
+		mirror, found := s.user.watchlistMirrors[slug]
+		if !found {
+			continue
+		}
+		diff, err := s.targetDifference(watchlist, mirror)
+		if err != nil {
+			return err
+		}
+		diff["add"] = s.capItems(slug, "add", s.withoutRejected("watchlist", s.withoutHidden(slug, diff["add"])))
+		diff["remove"] = s.capItems(slug, "removal", s.withoutRejected("watchlist", diff["remove"]))
+		if s.listsConf.NoRemoveFor(watchlist.ListID) || watchlist.Partial {
+			delete(diff, "remove")
+		}
+		diffs[slug] = diff
+	}
+	if syncMode != appconfig.SyncModeDryRun && syncMode != appconfig.SyncModeAddOnly {
+		if err := s.checkRemovals("watchlist", watchlistRemovals, len(s.user.traktLists[watchlist.ListID].ListItems)); err != nil {
+			return err
+		}
+		for _, slug := range s.listsConf.WatchlistMirrors {
+			diff, found := diffs[slug]
+			if !found {
+				continue
+			}
+			if err := s.checkRemovals(fmt.Sprintf("list %s", slug), diff["remove"], len(s.user.watchlistMirrors[slug].ListItems)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, configured := range s.listsConf.WatchlistMirrors {
+		diff, found := diffs[configured]
+		if !found {
+			continue
+		}
+		// the mirror is keyed by the configured slug, but trakt knows it by the slug of the list it created
+		slug := s.user.watchlistMirrors[configured].IDMeta.Slug
+		target := fmt.Sprintf("list %s", slug)
+		if len(diff["add"]) > 0 {
+			if syncMode == appconfig.SyncModeDryRun {
+				msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
+				s.logger.Info(msg, s.diffItems(slug, diff["add"]))
+				s.result.Lists.PendingAdd = append(s.result.Lists.PendingAdd, diff["add"]...)
+			} else {
+				if err := s.checkAdditions(target, diff["add"], len(s.user.watchlistMirrors[configured].ListItems)); err != nil {
+					return err
+				}
+				s.invalidateCache(slug)
+				added, err := s.addWithinLimit(target, diff["add"], len(s.user.watchlistMirrors[configured].ListItems), func(items entities.TraktItems) (*entities.TraktResponse, error) {
+					return s.traktClient.ListItemsAdd(ctx, slug, items)
+				})
+				if err != nil {
+					return fmt.Errorf("failure adding items to trakt list %s: %w", slug, err)
+				}
+				s.result.Lists.Added = append(s.result.Lists.Added, added...)
+			}
+		}
+		if len(diff["remove"]) > 0 {
+			if syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
+				s.logger.Info(msg, s.diffItems(slug, diff["remove"]))
+				s.result.Lists.PendingRemove = append(s.result.Lists.PendingRemove, diff["remove"]...)
+				continue
+			}
+			s.invalidateCache(slug)
+			if err := s.traktClient.ListItemsRemove(ctx, slug, diff["remove"]); err != nil {
+				return fmt.Errorf("failure removing items from trakt list %s: %w", slug, err)
+			}
+			s.result.Lists.Removed = append(s.result.Lists.Removed, diff["remove"]...)
+		}
+	}
+	return nil
+}
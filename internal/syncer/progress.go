@@ -0,0 +1,46 @@
+package syncer
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+)
+
+type progress struct {
+	logger    *slog.Logger
+	clock     clock.Clock
+	category  string
+	total     int
+	processed int
+	interval  time.Duration
+	lastLog   time.Time
+}
+
+func newProgress(logger *slog.Logger, clk clock.Clock, category string, total int, interval *time.Duration) *progress {
+	p := &progress{
+		logger:   logger,
+		clock:    clk,
+		category: category,
+		total:    total,
+		lastLog:  clk.Now(),
+	}
+	if interval != nil {
+		p.interval = *interval
+	}
+	return p
+}
+
+func (p *progress) add(n int) {
+	p.processed += n
+	now := p.clock.Now()
+	if p.interval <= 0 || now.Sub(p.lastLog) < p.interval {
+		return
+	}
+	p.lastLog = now
+	p.logger.Info(
+		fmt.Sprintf("processed %d/%d %s item(s)", p.processed, p.total, p.category),
+		slog.Int("percentage", p.processed*100/max(p.total, 1)),
+	)
+}
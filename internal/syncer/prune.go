@@ -0,0 +1,91 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// PrunedList is a trakt list along with its items missing from the imdb lists mirrored to it
+type PrunedList struct {
+	ListID string
+	Slug   string
+	Items  entities.TraktItems
+	// DryRun tells the items were only reported, rather than removed from the trakt list
+	DryRun bool
+}
+
+// Prune hydrates the syncer and removes the items missing from the given imdb lists from their trakt lists, leaving everything else untouched
+// All lists are hydrated, so that the items of sibling imdb lists mirrored to the same trakt list are kept
+// The items are only reported in dry-run sync mode, and the removal limits apply like they do when syncing
+// When a tracking list is configured, only the items recorded in it as added by the syncer are pruned
+func (s *Syncer) Prune(ctx context.Context, listIDs []string) ([]PrunedList, error) {
+	if err := s.hydrate(ctx); err != nil {
+		return nil, err
+	}
+	return s.pruneLists(ctx, listIDs)
+}
+
+// pruneLists removes the items missing from the given hydrated imdb lists from their trakt lists, see Prune
+func (s *Syncer) pruneLists(ctx context.Context, listIDs []string) ([]PrunedList, error) {
+	tracked, err := s.trackedItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists)
+	handledRemovals := make(map[string]map[string]struct{})
+	var pruned []PrunedList
+	for _, listID := range listIDs {
+		list, found := s.user.imdbLists[listID]
+		if !found {
+			return nil, fmt.Errorf("imdb list %s is not synced, it's either not configured or could not be fetched", listID)
+		}
+		slug := s.traktListSlug(list)
+		diff, err := s.listDifference(list)
+		if err != nil {
+			return nil, err
+		}
+		remove, err := s.unhandledRemovals(handledRemovals, slug, diff["remove"])
+		if err != nil {
+			return nil, err
+		}
+		remove = s.withoutUntracked(slug, tracked, remove)
+		if len(remove) == 0 {
+			s.logger.Info(fmt.Sprintf("nothing to prune from trakt list %s", slug))
+			continue
+		}
+		if s.listsConf.NoRemoveFor(listID) {
+			s.logger.Info(fmt.Sprintf("skipping prune of %d trakt list item(s), removals are disabled for imdb list %s", len(remove), listID))
+			continue
+		}
+		if s.isPartial(list) {
+			s.logger.Warn(fmt.Sprintf("skipping prune of %d trakt list item(s), imdb list %s was read from its web page, which may not show all of its items", len(remove), listID))
+			continue
+		}
+		prunedList := PrunedList{
+			ListID: listID,
+			Slug:   slug,
+			Items:  remove,
+			DryRun: syncMode == appconfig.SyncModeDryRun,
+		}
+		if prunedList.DryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have pruned %d trakt list item(s)", syncMode, len(remove)), s.diffItems(slug, remove))
+			pruned = append(pruned, prunedList)
+			continue
+		}
+		if err = s.checkRemovals(fmt.Sprintf("list %s", slug), remove, len(s.user.traktLists[listID].ListItems)); err != nil {
+			return pruned, err
+		}
+		if err = s.archiveItems(ctx, list, remove); err != nil {
+			return pruned, err
+		}
+		s.invalidateCache(slug)
+		if err = s.traktClient.ListItemsRemove(ctx, slug, remove); err != nil {
+			return pruned, fmt.Errorf("failure pruning items from trakt list %s: %w", slug, err)
+		}
+		pruned = append(pruned, prunedList)
+	}
+	return pruned, nil
+}
@@ -0,0 +1,73 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// ratingsState records the imdb items seen rated by previous runs, so that only items which were rated and then unrated on imdb lose their trakt rating
+// items missing from an incomplete imdb export, but never seen rated before, are left untouched
+type ratingsState struct {
+	path    string
+	existed bool
+	seen    map[string]struct{}
+}
+
+type ratingsStateFile struct {
+	Rated []string `json:"rated"`
+}
+
+func loadRatingsState(path string) (*ratingsState, error) {
+	state := &ratingsState{
+		path: path,
+		seen: make(map[string]struct{}),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failure reading ratings state file %s: %w", path, err)
+	}
+	var saved ratingsStateFile
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failure decoding ratings state file %s: %w", path, err)
+	}
+	for _, id := range saved.Rated {
+		state.seen[id] = struct{}{}
+	}
+	state.existed = true
+	return state, nil
+}
+
+func (r *ratingsState) wasRated(id string) bool {
+	_, found := r.seen[id]
+	return found
+}
+
+// save records the items currently rated on imdb, along with the previously seen items still rated on trakt
+// the latter keep their tombstone until their trakt rating is removed, e.g. by a run outside of dry-run sync mode
+func (r *ratingsState) save(imdbRated, traktRated map[string]struct{}) error {
+	var saved ratingsStateFile
+	for id := range imdbRated {
+		saved.Rated = append(saved.Rated, id)
+	}
+	for id := range r.seen {
+		_, imdbFound := imdbRated[id]
+		if _, traktFound := traktRated[id]; traktFound && !imdbFound {
+			saved.Rated = append(saved.Rated, id)
+		}
+	}
+	slices.Sort(saved.Rated)
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("failure encoding ratings state: %w", err)
+	}
+	if err = os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failure writing ratings state file %s: %w", r.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+// TraktSnapshot is the trakt data the syncer manages at a point in time, which trakt can be restored to after a sync
+// Ratings are nil when they weren't part of the snapshot, e.g. when imdb is accessed anonymously, so that restoring leaves them untouched
+type TraktSnapshot struct {
+	TakenAt   time.Time            `json:"taken_at"`
+	Watchlist *entities.TraktList  `json:"watchlist,omitempty"`
+	Lists     []entities.TraktList `json:"lists"`
+	Ratings   entities.TraktItems  `json:"ratings"`
+}
+
+// RestoredTarget is a trakt target along with the items added and removed to restore it to a snapshot
+type RestoredTarget struct {
+	Target  string
+	Added   entities.TraktItems
+	Removed entities.TraktItems
+	// DryRun tells the changes were only reported, rather than applied to trakt
+	DryRun bool
+}
+
+// TakeTraktSnapshot hydrates the syncer and captures the trakt watchlist, ratings and the trakt lists mirroring imdb lists
+// The syncer must be created in dry-run sync mode, otherwise hydrating would create the missing trakt lists
+func (s *Syncer) TakeTraktSnapshot(ctx context.Context) (*TraktSnapshot, error) {
+	if syncMode := *s.conf.Mode; syncMode != appconfig.SyncModeDryRun {
+		return nil, fmt.Errorf("taking a trakt snapshot requires sync mode %s, got %s", appconfig.SyncModeDryRun, syncMode)
+	}
+	if err := s.hydrate(ctx); err != nil {
+		return nil, err
+	}
+	snapshot := &TraktSnapshot{
+		TakenAt: s.clock.Now().UTC(),
+	}
+	for id, traktList := range s.user.traktLists {
+		if traktList.IsWatchlist || s.user.imdbLists[id].IsWatchlist {
+			watchlist := traktList
+			snapshot.Watchlist = &watchlist
+			continue
+		}
+		if traktList.Name == nil {
+			traktList.Name = traktList.IDMeta.ListName
+		}
+		if traktList.Name == nil {
+			name := s.traktListName(s.user.imdbLists[id])
+			traktList.Name = &name
+		}
+		traktList.IDMeta.IMDb = id
+		snapshot.Lists = append(snapshot.Lists, traktList)
+	}
+	sort.Slice(snapshot.Lists, func(i, j int) bool {
+		return snapshot.Lists[i].IDMeta.Slug < snapshot.Lists[j].IDMeta.Slug
+	})
+	if !s.imdbConf.IsAuthless() {
+		snapshot.Ratings = make(entities.TraktItems, 0, len(s.user.traktRatings))
+		for _, rating := range s.user.traktRatings {
+			snapshot.Ratings = append(snapshot.Ratings, rating)
+		}
+		sortItems(snapshot.Ratings)
+	}
+	return snapshot, nil
+}
+
+func LoadTraktSnapshot(path string) (*TraktSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading trakt snapshot file %s: %w", path, err)
+	}
+	var snapshot TraktSnapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failure decoding trakt snapshot file %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+func (s *TraktSnapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure encoding trakt snapshot: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failure writing trakt snapshot file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Restore reconciles the trakt watchlist, lists and ratings with a snapshot, adding the items missing from them and removing the items added since
+// The trakt lists deleted since the snapshot are created again. Nothing is changed in dry-run sync mode, removals are skipped in add-only sync mode,
+// and the removal limits apply like they do when syncing
+func (s *Syncer) Restore(ctx context.Context, snapshot *TraktSnapshot) ([]RestoredTarget, error) {
+	var restored []RestoredTarget
+	if snapshot.Watchlist != nil {
+		target, err := s.restoreWatchlist(ctx, snapshot.Watchlist.ListItems)
+		if err != nil {
+			return restored, err
+		}
+		restored = appendRestored(restored, target)
+	}
+	for _, list := range snapshot.Lists {
+		target, err := s.restoreList(ctx, list)
+		if err != nil {
+			return restored, err
+		}
+		restored = appendRestored(restored, target)
+	}
+	if snapshot.Ratings != nil {
+		target, err := s.restoreRatings(ctx, snapshot.Ratings)
+		if err != nil {
+			return restored, err
+		}
+		restored = appendRestored(restored, target)
+	}
+	return restored, nil
+}
+
+func (s *Syncer) restoreWatchlist(ctx context.Context, items entities.TraktItems) (RestoredTarget, error) {
+	current, err := s.traktClient.WatchlistGet(ctx)
+	if err != nil {
+		return RestoredTarget{}, fmt.Errorf("failure fetching trakt watchlist: %w", err)
+	}
+	return s.restoreTarget(appconfig.SyncCategoryWatchlist, "watchlist", s.withIMDbIDs("watchlist", current.ListItems, nil), items, restoreActions{
+		add: func(items entities.TraktItems) error {
+			_, err := s.traktClient.WatchlistItemsAdd(ctx, items)
+			return err
+		},
+		remove: func(items entities.TraktItems) error {
+			return s.traktClient.WatchlistItemsRemove(ctx, items)
+		},
+	})
+}
+
+func (s *Syncer) restoreList(ctx context.Context, list entities.TraktList) (RestoredTarget, error) {
+	slug := list.IDMeta.Slug
+	target := fmt.Sprintf("list %s", slug)
+	var currentItems entities.TraktItems
+	current, err := s.traktClient.ListGet(ctx, slug)
+	var notFoundErr *client.TraktListNotFoundError
+	switch {
+	case errors.As(err, &notFoundErr):
+		if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have created trakt list %s deleted since the snapshot", syncMode, slug))
+			break
+		}
+		name := slug
+		if list.Name != nil {
+			name = *list.Name
+		}
+		privacy := s.listsConf.PrivacyFor(list.IDMeta.IMDb)
+		sortBy, sortHow := s.listsConf.SortFor(list.IDMeta.IMDb)
+		if _, err = s.traktClient.ListAdd(ctx, slug, name, privacy, sortBy, sortHow); err != nil {
+			return RestoredTarget{}, fmt.Errorf("failure creating trakt list %s: %w", slug, err)
+		}
+	case err != nil:
+		return RestoredTarget{}, fmt.Errorf("failure fetching trakt list %s: %w", slug, err)
+	default:
+		currentItems = s.withIMDbIDs(target, current.ListItems, nil)
+	}
+	s.invalidateCache(slug)
+	return s.restoreTarget(appconfig.SyncCategoryLists, target, currentItems, list.ListItems, restoreActions{
+		add: func(items entities.TraktItems) error {
+			_, err := s.traktClient.ListItemsAdd(ctx, slug, items)
+			return err
+		},
+		remove: func(items entities.TraktItems) error {
+			return s.traktClient.ListItemsRemove(ctx, slug, items)
+		},
+	})
+}
+
+// restoreRatings adds the ratings changed since the snapshot as well, which overwrites their current trakt rating
+func (s *Syncer) restoreRatings(ctx context.Context, items entities.TraktItems) (RestoredTarget, error) {
+	current, err := s.traktClient.RatingsGet(ctx)
+	if err != nil {
+		return RestoredTarget{}, fmt.Errorf("failure fetching trakt ratings: %w", err)
+	}
+	return s.restoreTarget(appconfig.SyncCategoryRatings, "ratings", s.withIMDbIDs("ratings", current, nil), items, restoreActions{
+		add: func(items entities.TraktItems) error {
+			return s.traktClient.RatingsAdd(ctx, items)
+		},
+		remove: func(items entities.TraktItems) error {
+			return s.traktClient.RatingsRemove(ctx, items)
+		},
+	})
+}
+
+type restoreActions struct {
+	add    func(entities.TraktItems) error
+	remove func(entities.TraktItems) error
+}
+
+func (s *Syncer) restoreTarget(category, target string, current, snapshot entities.TraktItems, actions restoreActions) (RestoredTarget, error) {
+	add, remove := restoreDifference(current, snapshot)
+	restored := RestoredTarget{
+		Target: target,
+		DryRun: s.conf.ModeFor(category) == appconfig.SyncModeDryRun,
+	}
+	if restored.DryRun {
+		s.logger.Info(fmt.Sprintf("sync mode %s would have restored trakt %s, adding %d and removing %d item(s)", appconfig.SyncModeDryRun, target, len(add), len(remove)), s.diffItems(target, append(slices.Clone(add), remove...)))
+		restored.Added, restored.Removed = add, remove
+		return restored, nil
+	}
+	if len(add) > 0 {
+		if err := actions.add(add); err != nil {
+			return restored, fmt.Errorf("failure restoring items of trakt %s: %w", target, err)
+		}
+		restored.Added = add
+	}
+	if len(remove) > 0 {
+		if syncMode := s.conf.ModeFor(category); syncMode == appconfig.SyncModeAddOnly {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have removed %d trakt %s item(s) added since the snapshot", syncMode, len(remove), target), s.diffItems(target, remove))
+			return restored, nil
+		}
+		if err := s.checkRemovals(target, remove, len(current)); err != nil {
+			return restored, err
+		}
+		if category == appconfig.SyncCategoryRatings {
+			confirmed, err := s.confirmRatingsRemoval(remove)
+			if err != nil {
+				return restored, fmt.Errorf("failure confirming trakt ratings removal: %w", err)
+			}
+			if !confirmed {
+				msg := fmt.Sprintf("skipping removal of %d trakt rating item(s) added since the snapshot, set SYNC_REMOVALS_ALLOWRATINGS to true or confirm with the --%s flag", len(remove), flagNameInteractive)
+				s.logger.Warn(msg, s.diffItems(target, remove))
+				return restored, nil
+			}
+		}
+		if err := actions.remove(remove); err != nil {
+			return restored, fmt.Errorf("failure removing items added to trakt %s since the snapshot: %w", target, err)
+		}
+		restored.Removed = remove
+	}
+	return restored, nil
+}
+
+// restoreDifference compares the current items of a trakt target with the items of the snapshot, by imdb id
+// an item rated differently than in the snapshot is added again, so that its rating is restored
+func restoreDifference(current, snapshot entities.TraktItems) (add, remove entities.TraktItems) {
+	currentItems := make(map[string]entities.TraktItem, len(current))
+	for _, item := range current {
+		if id, _ := item.GetItemID(); id != nil {
+			currentItems[*id] = item
+		}
+	}
+	snapshotItems := make(map[string]struct{}, len(snapshot))
+	for _, item := range snapshot {
+		id, _ := item.GetItemID()
+		if id == nil {
+			continue
+		}
+		snapshotItems[*id] = struct{}{}
+		if currentItem, found := currentItems[*id]; !found || currentItem.Rating != item.Rating {
+			add = append(add, item)
+		}
+	}
+	for _, item := range current {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := snapshotItems[*id]; !found {
+				remove = append(remove, item)
+			}
+		}
+	}
+	sortItems(add)
+	sortItems(remove)
+	return add, remove
+}
+
+func appendRestored(restored []RestoredTarget, target RestoredTarget) []RestoredTarget {
+	if len(target.Added) == 0 && len(target.Removed) == 0 {
+		return restored
+	}
+	return append(restored, target)
+}
+
+func sortItems(items entities.TraktItems) {
+	sort.Slice(items, func(i, j int) bool {
+		idI, _ := items[i].GetItemID()
+		idJ, _ := items[j].GetItemID()
+		return idI != nil && idJ != nil && *idI < *idJ
+	})
+}
@@ -0,0 +1,154 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/review"
+)
+
+const (
+	reviewTargetRatings   = "ratings"
+	reviewTargetWatchlist = "watchlist"
+)
+
+// review shows the list and rating changes in the terminal once hydrated, the changes rejected there are left out when syncing
+// the review is skipped when stdin isn't a terminal, e.g. in ci, and aborting it stops the sync before anything is changed
+func (s *Syncer) review() error {
+	if !isTerminal(os.Stdin) {
+		s.logger.Info("skipping review of the changes, stdin is not a terminal")
+		return nil
+	}
+	changes, err := s.reviewChanges()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		s.logger.Info("nothing to review, the lists and ratings are in sync")
+		return nil
+	}
+	teaModel, err := review.NewTeaProgram(changes, tea.WithInput(os.Stdin), tea.WithOutput(os.Stdout)).Run()
+	if err != nil {
+		return fmt.Errorf("failure running the review of the changes: %w", err)
+	}
+	model, ok := teaModel.(*review.Model)
+	if !ok {
+		return fmt.Errorf("failure type asserting tea.Model to *review.Model")
+	}
+	if err = model.Err(); err != nil {
+		if errors.Is(err, review.ErrUserAborted) {
+			return fmt.Errorf("review of the changes aborted, nothing was synced: %w", err)
+		}
+		return fmt.Errorf("failure reviewing the changes: %w", err)
+	}
+	rejected := model.Rejected()
+	s.rejected = make(map[string]map[string]struct{})
+	for _, change := range rejected {
+		if s.rejected[change.Target] == nil {
+			s.rejected[change.Target] = make(map[string]struct{})
+		}
+		s.rejected[change.Target][change.ID] = struct{}{}
+	}
+	s.logger.Info(fmt.Sprintf("approved %d of %d change(s) in the review", len(changes)-len(rejected), len(changes)))
+	return nil
+}
+
+// reviewChanges lists the changes of the lists and ratings categories about to be synced, ordered by target
+func (s *Syncer) reviewChanges() ([]review.Change, error) {
+	var changes []review.Change
+	seen := make(map[string]struct{})
+	appendChanges := func(target string, diff map[string]entities.TraktItems) {
+		for _, action := range []string{review.ActionAdd, review.ActionUpdate, review.ActionRemove} {
+			for _, item := range diff[action] {
+				id, _ := item.GetItemID()
+				if id == nil || *id == "" {
+					continue
+				}
+				// imdb lists mirrored to the same trakt list would otherwise show the same change more than once
+				key := strings.Join([]string{target, action, *id}, "|")
+				if _, found := seen[key]; found {
+					continue
+				}
+				seen[key] = struct{}{}
+				changes = append(changes, review.Change{
+					Target: target,
+					Action: action,
+					ID:     *id,
+					Label:  entities.TraktItems{item}.ExplainedLabels()[0],
+				})
+			}
+		}
+	}
+	if s.isReviewed(appconfig.SyncCategoryLists) {
+		lists := make([]entities.IMDbList, 0, len(s.user.imdbLists))
+		for _, list := range s.user.imdbLists {
+			lists = append(lists, list)
+		}
+		slices.SortFunc(lists, func(a, b entities.IMDbList) int {
+			return strings.Compare(s.reviewTarget(a), s.reviewTarget(b))
+		})
+		for _, list := range lists {
+			diff, err := s.listDifference(list)
+			if err != nil {
+				return nil, err
+			}
+			appendChanges(s.reviewTarget(list), diff)
+		}
+	}
+	if s.isReviewed(appconfig.SyncCategoryRatings) {
+		appendChanges(reviewTargetRatings, s.ratingsDifference())
+	}
+	return changes, nil
+}
+
+// isReviewed reports whether the category is about to be synced, hence whether its changes are worth reviewing
+func (s *Syncer) isReviewed(category string) bool {
+	if s.imdbConf.IsAuthless() && requiresRatings(category) {
+		return false
+	}
+	if enabled, ok := s.conf.CategoryOverride(category); ok {
+		return enabled
+	}
+	return true
+}
+
+func (s *Syncer) reviewTarget(list entities.IMDbList) string {
+	if list.IsWatchlist {
+		return reviewTargetWatchlist
+	}
+	return fmt.Sprintf("list %s", s.traktListSlug(list))
+}
+
+// withoutRejected leaves the items whose changes were rejected in the review out of the items of the target, logging every skipped change
+func (s *Syncer) withoutRejected(target string, items entities.TraktItems) entities.TraktItems {
+	rejected := s.rejected[target]
+	if len(rejected) == 0 || len(items) == 0 {
+		return items
+	}
+	var kept, skipped entities.TraktItems
+	for _, item := range items {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := rejected[*id]; found {
+				skipped = append(skipped, item)
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	if len(skipped) > 0 {
+		s.logger.Info(fmt.Sprintf("skipping %d %s change(s), they were rejected in the review", len(skipped), target), s.diffItems(target, skipped))
+	}
+	return kept
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
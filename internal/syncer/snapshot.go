@@ -0,0 +1,192 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	DiffReasonAddedSinceSnapshot   = "added since the snapshot"
+	DiffReasonRemovedSinceSnapshot = "removed since the snapshot"
+)
+
+// Snapshot is the imdb data of a user at a point in time, which later imdb data can be compared against without touching trakt
+type Snapshot struct {
+	TakenAt time.Time           `json:"taken_at"`
+	Lists   []entities.IMDbList `json:"lists"`
+	Ratings []entities.IMDbItem `json:"ratings,omitempty"`
+}
+
+// SnapshotDiff is the delta of the imdb data since a snapshot, keyed like ItemsDifference
+type SnapshotDiff struct {
+	Lists   []ListDelta
+	Ratings map[string]entities.TraktItems
+}
+
+type ListDelta struct {
+	ListID   string
+	ListName string
+	Diff     map[string]entities.TraktItems
+}
+
+func (d SnapshotDiff) IsEmpty() bool {
+	return len(d.Lists) == 0 && len(d.Ratings) == 0
+}
+
+// TakeSnapshot reads the imdb lists, watchlist and ratings selected by the config, trakt is neither authenticated nor read
+func TakeSnapshot(ctx context.Context, conf *appconfig.Config) (*Snapshot, error) {
+	level, err := logger.ParseLevel(conf.Log.LevelOrDefault())
+	if err != nil {
+		return nil, err
+	}
+	log := logger.NewLoggerWithLevel(os.Stdout, level)
+	newIMDbClient := client.NewIMDbClient
+	if conf.IMDb.IsOffline() {
+		newIMDbClient = client.NewIMDbOfflineClient
+	}
+	imdbClient, err := newIMDbClient(conf.IMDb, conf.HTTP, log)
+	if err != nil {
+		return nil, fmt.Errorf("failure initialising imdb client: %w", err)
+	}
+	if err = imdbClient.Hydrate(ctx); err != nil {
+		return nil, fmt.Errorf("failure hydrating imdb client: %w", err)
+	}
+	return takeSnapshot(ctx, imdbClient, conf.IMDb, clock.New(), log)
+}
+
+func takeSnapshot(ctx context.Context, imdbClient client.IMDbClientInterface, imdbConf appconfig.IMDb, clk clock.Clock, log *slog.Logger) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		TakenAt: clk.Now().UTC(),
+	}
+	var delegatedErrors []error
+	if len(imdbConf.Lists) != 0 {
+		snapshot.Lists, delegatedErrors = imdbClient.ListsGet(ctx, imdbConf.Lists)
+	} else {
+		snapshot.Lists, delegatedErrors = imdbClient.ListsGetAll(ctx)
+	}
+	for _, delegatedErr := range delegatedErrors {
+		var listErr *client.IMDbListError
+		if errors.As(delegatedErr, &listErr) {
+			log.Warn(fmt.Sprintf("skipping imdb list %s, it could not be fetched", listErr.ListID), logger.Error(delegatedErr))
+			continue
+		}
+		return nil, fmt.Errorf("failure fetching imdb lists: %w", delegatedErr)
+	}
+	if !imdbConf.IsRestricted() && !imdbConf.IsAuthless() {
+		watchlist, err := imdbClient.WatchlistGet(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching imdb watchlist: %w", err)
+		}
+		snapshot.Lists = append(snapshot.Lists, *watchlist)
+	}
+	for i := range snapshot.Lists {
+		snapshot.Lists[i].RemoveDuplicates()
+	}
+	if !imdbConf.IsAuthless() {
+		ratings, err := imdbClient.RatingsGet(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching imdb ratings: %w", err)
+		}
+		snapshot.Ratings = ratings
+	}
+	return snapshot, nil
+}
+
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading snapshot file %s: %w", path, err)
+	}
+	var snapshot Snapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failure decoding snapshot file %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure encoding snapshot: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failure writing snapshot file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Diff compares the snapshot with an older one, the older items take the place of the trakt items in ItemsDifference
+// Lists are matched by id, so a list missing from either snapshot has all of its items added or removed
+func (s *Snapshot) Diff(previous *Snapshot) SnapshotDiff {
+	previousLists := make(map[string]entities.IMDbList, len(previous.Lists))
+	for _, list := range previous.Lists {
+		previousLists[list.ListID] = list
+	}
+	var diff SnapshotDiff
+	seen := make(map[string]struct{}, len(s.Lists))
+	for _, list := range s.Lists {
+		seen[list.ListID] = struct{}{}
+		if delta := snapshotItemsDifference(list.ListItems, previousLists[list.ListID].ListItems); len(delta) != 0 {
+			diff.Lists = append(diff.Lists, ListDelta{ListID: list.ListID, ListName: list.ListName, Diff: delta})
+		}
+	}
+	for _, list := range previous.Lists {
+		if _, found := seen[list.ListID]; found {
+			continue
+		}
+		if delta := snapshotItemsDifference(nil, list.ListItems); len(delta) != 0 {
+			diff.Lists = append(diff.Lists, ListDelta{ListID: list.ListID, ListName: list.ListName, Diff: delta})
+		}
+	}
+	sort.Slice(diff.Lists, func(i, j int) bool {
+		return diff.Lists[i].ListID < diff.Lists[j].ListID
+	})
+	if delta := snapshotItemsDifference(s.Ratings, previous.Ratings); len(delta) != 0 {
+		diff.Ratings = delta
+	}
+	return diff
+}
+
+func snapshotItemsDifference(current, previous []entities.IMDbItem) map[string]entities.TraktItems {
+	currentItems := make(map[string]entities.IMDbItem, len(current))
+	for _, item := range current {
+		currentItems[item.ID] = item
+	}
+	previousItems := make(map[string]entities.TraktItem, len(previous))
+	for _, item := range previous {
+		traktItem := item.ToTraktItem()
+		if item.Rating != nil {
+			traktItem.Rating = *item.Rating
+		}
+		previousItems[item.ID] = traktItem
+	}
+	diff := entities.ItemsDifference(currentItems, previousItems)
+	for action, items := range diff {
+		for i := range items {
+			switch action {
+			case "add":
+				items[i].Reason = DiffReasonAddedSinceSnapshot
+			case "remove":
+				items[i].Reason = DiffReasonRemovedSinceSnapshot
+			}
+		}
+		sort.Slice(items, func(i, j int) bool {
+			idI, _ := items[i].GetItemID()
+			idJ, _ := items[j].GetItemID()
+			return *idI < *idJ
+		})
+	}
+	return diff
+}
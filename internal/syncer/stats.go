@@ -0,0 +1,67 @@
+package syncer
+
+import (
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+type CategoryStats struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
+type Stats struct {
+	Lists     CategoryStats `json:"lists"`
+	Watchlist CategoryStats `json:"watchlist"`
+	Ratings   CategoryStats `json:"ratings"`
+	History   CategoryStats `json:"history"`
+	Comments  CategoryStats `json:"comments"`
+}
+
+// CategoryResult holds the items changed in trakt, and the ones that would have been changed if the sync mode allowed it
+type CategoryResult struct {
+	Added         entities.TraktItems `json:"added,omitempty"`
+	Removed       entities.TraktItems `json:"removed,omitempty"`
+	PendingAdd    entities.TraktItems `json:"pending_add,omitempty"`
+	PendingRemove entities.TraktItems `json:"pending_remove,omitempty"`
+}
+
+func (c CategoryResult) stats() CategoryStats {
+	return CategoryStats{
+		Added:   len(c.Added),
+		Removed: len(c.Removed),
+	}
+}
+
+type Result struct {
+	// RunID identifies the run in the logs, the summary and the notifications
+	RunID     string         `json:"run_id"`
+	Mode      string         `json:"mode"`
+	Lists     CategoryResult `json:"lists"`
+	Watchlist CategoryResult `json:"watchlist"`
+	Ratings   CategoryResult `json:"ratings"`
+	History   CategoryResult `json:"history"`
+	Comments  CategoryResult `json:"comments"`
+	// SkippedLists are the imdb lists that could not be fetched, hence were left out of the sync
+	SkippedLists []SkippedList `json:"skipped_lists,omitempty"`
+	// SkippedWatchlist is the reason the watchlist was left out of the sync, when the trakt watchlist is unavailable
+	SkippedWatchlist string `json:"skipped_watchlist,omitempty"`
+	// SkippedWithoutIMDbID is the number of trakt items left out of the comparison with imdb, since they lack an imdb id
+	SkippedWithoutIMDbID int `json:"skipped_without_imdb_id,omitempty"`
+	// Errors are the failures of the run, including the ones the run continued past
+	Errors []RunError `json:"errors,omitempty"`
+}
+
+type SkippedList struct {
+	ListID string `json:"list_id"`
+	Reason string `json:"reason"`
+}
+
+func (r *Result) Stats() Stats {
+	return Stats{
+		Lists:     r.Lists.stats(),
+		Watchlist: r.Watchlist.stats(),
+		Ratings:   r.Ratings.stats(),
+		History:   r.History.stats(),
+		Comments:  r.Comments.stats(),
+	}
+}
@@ -0,0 +1,61 @@
+package syncer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// writeSummary appends the outcome of a run to a file as a markdown table, e.g. the GitHub Actions job step summary
+// the file is appended to rather than replaced, since the step summary is shared by all steps and profiles of a job
+func writeSummary(path, profile string, result *Result, syncErr error) error {
+	var b strings.Builder
+	b.WriteString("## imdb-trakt-sync")
+	if profile != "" {
+		fmt.Fprintf(&b, " (%s)", profile)
+	}
+	fmt.Fprintf(&b, "\n\n**Status:** %s | **Mode:** %s | **Run:** `%s`\n\n", syncStatus(syncErr), result.Mode, result.RunID)
+	b.WriteString("| Category | Added | Removed | Pending add | Pending remove |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	categories := []struct {
+		name   string
+		result CategoryResult
+	}{
+		{name: "Lists", result: result.Lists},
+		{name: "Watchlist", result: result.Watchlist},
+		{name: "Ratings", result: result.Ratings},
+		{name: "History", result: result.History},
+		{name: "Comments", result: result.Comments},
+	}
+	for _, category := range categories {
+		c := category.result
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d |\n", category.name, len(c.Added), len(c.Removed), len(c.PendingAdd), len(c.PendingRemove))
+	}
+	if len(result.SkippedLists) > 0 {
+		b.WriteString("\n**Skipped IMDb lists:**\n\n")
+		for _, list := range result.SkippedLists {
+			fmt.Fprintf(&b, "- %s: %s\n", list.ListID, list.Reason)
+		}
+	}
+	if result.SkippedWatchlist != "" {
+		fmt.Fprintf(&b, "\n**Skipped watchlist:** %s\n", result.SkippedWatchlist)
+	}
+	if result.SkippedWithoutIMDbID > 0 {
+		fmt.Fprintf(&b, "\n**Skipped Trakt items without an IMDb ID:** %d\n", result.SkippedWithoutIMDbID)
+	}
+	if syncErr != nil {
+		b.WriteString("\n```\n")
+		b.WriteString(syncErr.Error())
+		b.WriteString("\n```\n")
+	}
+	b.WriteString("\n")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failure opening summary file %s: %w", path, err)
+	}
+	if _, err = file.WriteString(b.String()); err != nil {
+		file.Close()
+		return fmt.Errorf("failure writing summary file %s: %w", path, err)
+	}
+	return file.Close()
+}
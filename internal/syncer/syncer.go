@@ -1,23 +1,67 @@
 package syncer
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/version"
 	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+	"github.com/cecobask/imdb-trakt-sync/pkg/notifier"
+)
+
+const (
+	// trakt rejects comments shorter than this many words
+	traktCommentMinWords = 5
+
+	flagNameInteractive = "interactive"
 )
 
 type Syncer struct {
 	logger      *slog.Logger
+	clock       clock.Clock
 	imdbClient  client.IMDbClientInterface
 	traktClient client.TraktClientInterface
-	user        *user
-	conf        appconfig.Sync
+	// additional imdb accounts, whose data is merged with the data of the main account
+	imdbAccounts []imdbAccount
+	notifier     notifier.Notifier
+	user         *user
+	conf         appconfig.Sync
+	listsConf    appconfig.Lists
+	imdbConf     appconfig.IMDb
+	cache        *listsCache
+	checkpoint   *checkpoint
+	ratings      *ratingsState
+	// fingerprint of the config and imdb watchlist, the watchlist sync is skipped while it and the trakt watchlist don't change
+	configFingerprint    string
+	watchlistFingerprint string
+	watchlistSynced      bool
+	// fingerprints of the imdb lists, the lists unchanged since the previous run are kept apart from the ones synced
+	listFingerprints map[string]string
+	unchangedLists   map[string]entities.IMDbList
+	syncedLists      map[string]struct{}
+	// ids of the items whose changes were rejected in the review, by target
+	rejected    map[string]map[string]struct{}
+	metricsPath string
+	summaryPath string
+	errorsPath  string
+	profile     string
+	result      Result
 }
 
 type user struct {
@@ -25,35 +69,123 @@ type user struct {
 	imdbRatings  map[string]entities.IMDbItem
 	traktLists   map[string]entities.TraktList
 	traktRatings map[string]entities.TraktItem
+	traktHidden  map[string]struct{}
+	// trakt lists the imdb watchlist is mirrored to besides the trakt watchlist, by slug
+	watchlistMirrors map[string]entities.TraktList
 }
 
-func NewSyncer(conf *appconfig.Config) (*Syncer, error) {
-	log := logger.NewLogger(os.Stdout)
-	imdbClient, err := client.NewIMDbClient(conf.IMDb, log)
+func NewSyncer(ctx context.Context, conf *appconfig.Config) (*Syncer, error) {
+	level, err := logger.ParseLevel(conf.Log.LevelOrDefault())
+	if err != nil {
+		return nil, err
+	}
+	// a syncer is created for every run, hence the run id is generated here rather than in Sync, so that the clients log it as well
+	runID, err := newRunID()
+	if err != nil {
+		return nil, err
+	}
+	log := logger.NewLoggerWithLevel(os.Stdout, level).With(slog.String("run_id", runID))
+	if profile := conf.ProfileName(); profile != "" {
+		log = log.With(slog.String("profile", profile))
+	}
+	log.Debug(fmt.Sprintf("running version %s", version.Get()))
+	if path := conf.Path(); path != "" {
+		log.Info(fmt.Sprintf("loaded config file %s", path))
+	}
+	for _, warning := range conf.Warnings() {
+		log.Warn(warning)
+	}
+	newIMDbClient := client.NewIMDbClient
+	if conf.IMDb.IsOffline() {
+		newIMDbClient = client.NewIMDbOfflineClient
+	}
+	imdbClient, err := newIMDbClient(conf.IMDb, conf.HTTP, log)
 	if err != nil {
 		return nil, fmt.Errorf("failure initialising imdb client: %w", err)
 	}
-	if err = imdbClient.Hydrate(); err != nil {
+	if err = imdbClient.Hydrate(ctx); err != nil {
 		return nil, fmt.Errorf("failure hydrating imdb client: %w", err)
 	}
-	traktClient, err := client.NewTraktClient(conf.Trakt, log)
+	imdbAccounts, err := newIMDbAccounts(ctx, conf, log)
+	if err != nil {
+		return nil, err
+	}
+	traktClient, err := client.NewTraktClient(conf.Trakt, conf.HTTP, log)
 	if err != nil {
 		return nil, fmt.Errorf("failure initialising trakt client: %w", err)
 	}
-	if err = traktClient.Hydrate(); err != nil {
+	if err = traktClient.Hydrate(ctx); err != nil {
 		return nil, fmt.Errorf("failure hydrating trakt client: %w", err)
 	}
 	syncer := &Syncer{
-		logger:      log,
-		imdbClient:  imdbClient,
-		traktClient: traktClient,
+		logger:       log,
+		clock:        clock.New(),
+		imdbClient:   imdbClient,
+		imdbAccounts: imdbAccounts,
+		traktClient:  traktClient,
 		user: &user{
 			imdbLists:    make(map[string]entities.IMDbList),
 			imdbRatings:  make(map[string]entities.IMDbItem),
 			traktLists:   make(map[string]entities.TraktList),
 			traktRatings: make(map[string]entities.TraktItem),
 		},
-		conf: conf.Sync,
+		conf:      conf.Sync,
+		listsConf: conf.Lists,
+		imdbConf:  conf.IMDb,
+		profile:   conf.ProfileName(),
+		result: Result{
+			RunID: runID,
+			Mode:  *conf.Sync.Mode,
+		},
+	}
+	if conf.Notify.IsEnabled() {
+		preset := ""
+		if conf.Notify.Preset != nil {
+			preset = *conf.Notify.Preset
+		}
+		if syncer.notifier, err = notifier.NewWebhookNotifier(*conf.Notify.WebhookURL, preset); err != nil {
+			return nil, fmt.Errorf("failure initialising notifier: %w", err)
+		}
+	}
+	if metricsFile := conf.Sync.MetricsFile; metricsFile != nil && *metricsFile != "" {
+		syncer.metricsPath = profilePath(*metricsFile, conf.ProfileName())
+	}
+	if summaryFile := conf.Sync.SummaryFile; summaryFile != nil && *summaryFile != "" {
+		syncer.summaryPath = profilePath(*summaryFile, conf.ProfileName())
+	}
+	if errorsFile := conf.Sync.ErrorsFile; errorsFile != nil && *errorsFile != "" {
+		syncer.errorsPath = profilePath(*errorsFile, conf.ProfileName())
+	}
+	if cacheFile := conf.Sync.CacheFile; cacheFile != nil && *cacheFile != "" {
+		path := profilePath(*cacheFile, conf.ProfileName())
+		if conf.Sync.IsFullFetch() {
+			log.Info(fmt.Sprintf("ignoring lists cache %s, fetching everything", path))
+			syncer.cache = newListsCache(path)
+		} else if syncer.cache, err = loadListsCache(path); err != nil {
+			return nil, fmt.Errorf("failure initialising lists cache: %w", err)
+		}
+		if syncer.configFingerprint, err = fingerprint(conf.Flatten()); err != nil {
+			return nil, fmt.Errorf("failure fingerprinting config: %w", err)
+		}
+	}
+	if checkpointFile := conf.Sync.CheckpointFile; checkpointFile != nil && *checkpointFile != "" {
+		// the checkpoint only covers history, hence it follows the history sync mode
+		if historyMode := conf.Sync.ModeFor(appconfig.SyncCategoryHistory); historyMode == appconfig.SyncModeDryRun {
+			log.Info("skipping checkpoint, nothing is changed in dry-run sync mode")
+		} else {
+			var resumed bool
+			if syncer.checkpoint, resumed, err = loadCheckpoint(profilePath(*checkpointFile, conf.ProfileName()), historyMode); err != nil {
+				return nil, fmt.Errorf("failure initialising checkpoint: %w", err)
+			}
+			if resumed {
+				log.Info(fmt.Sprintf("resuming interrupted sync from checkpoint %s, skipping %d processed item(s)", syncer.checkpoint.path, syncer.checkpoint.size()))
+			}
+		}
+	}
+	if ratingsStateFile := conf.Sync.RatingsStateFile; ratingsStateFile != nil && *ratingsStateFile != "" {
+		if syncer.ratings, err = loadRatingsState(profilePath(*ratingsStateFile, conf.ProfileName())); err != nil {
+			return nil, fmt.Errorf("failure initialising ratings state: %w", err)
+		}
 	}
 	if len(conf.IMDb.Lists) != 0 {
 		for _, listID := range conf.IMDb.Lists {
@@ -63,65 +195,349 @@ func NewSyncer(conf *appconfig.Config) (*Syncer, error) {
 	return syncer, nil
 }
 
-func (s *Syncer) Sync() error {
-	if err := s.hydrate(); err != nil {
+// Sync runs the syncer and returns what changed, along with the items left untouched due to the sync mode
+// The result is returned even when the sync fails, describing the changes applied up to that point
+func (s *Syncer) Sync(ctx context.Context) (*Result, error) {
+	err := s.sync(ctx)
+	s.saveCache(ctx)
+	s.saveCheckpoint(err)
+	s.saveRatingsState(err)
+	s.notify(err)
+	if s.metricsPath != "" {
+		if metricsErr := writeMetrics(s.metricsPath, s.clock.Now(), &s.result, err); metricsErr != nil {
+			s.logger.Warn("failure writing the metrics file", logger.Error(metricsErr))
+		}
+	}
+	if s.summaryPath != "" {
+		if summaryErr := writeSummary(s.summaryPath, s.profile, &s.result, err); summaryErr != nil {
+			s.logger.Warn("failure writing the summary file", logger.Error(summaryErr))
+		}
+	}
+	if s.errorsPath != "" {
+		if errorsErr := writeErrors(s.errorsPath, s.result.Errors); errorsErr != nil {
+			s.logger.Warn("failure writing the errors file", logger.Error(errorsErr))
+		}
+	}
+	return &s.result, err
+}
+
+func (s *Syncer) sync(ctx context.Context) error {
+	if err := s.hydrate(ctx); err != nil {
 		s.logger.Error("failure hydrating imdb client", logger.Error(err))
+		s.recordError(categoryHydration, "", err)
 		return err
 	}
-	if err := s.syncLists(); err != nil {
-		s.logger.Error("failure syncing lists", logger.Error(err))
-		return err
+	if s.conf.ShouldReview() {
+		if err := s.review(); err != nil {
+			s.logger.Error("failure reviewing the changes", logger.Error(err))
+			return err
+		}
 	}
-	if err := s.syncRatings(); err != nil {
-		s.logger.Error("failure syncing ratings", logger.Error(err))
-		return err
+	categories := []struct {
+		name string
+		sync func(context.Context) error
+	}{
+		{name: appconfig.SyncCategoryLists, sync: s.syncTrackedLists},
+		{name: appconfig.SyncCategoryRatings, sync: s.syncRatings},
+		{name: appconfig.SyncCategoryCheckIns, sync: s.syncCheckIns},
+		{name: appconfig.SyncCategoryHistory, sync: s.syncHistory},
+		{name: appconfig.SyncCategoryComments, sync: s.syncComments},
 	}
-	if err := s.syncHistory(); err != nil {
-		s.logger.Error("failure syncing history", logger.Error(err))
-		return err
+	continueOnError := s.conf.ContinueOnError != nil && *s.conf.ContinueOnError
+	var (
+		errs   []error
+		synced int
+	)
+	for _, category := range categories {
+		if s.imdbConf.IsAuthless() && requiresRatings(category.name) {
+			s.logger.Info(fmt.Sprintf("skipping %s sync, imdb ratings can't be fetched without imdb authentication", category.name))
+			continue
+		}
+		if enabled, ok := s.conf.CategoryOverride(category.name); ok {
+			if !enabled {
+				s.logger.Info(fmt.Sprintf("skipping %s sync, force-disabled via command line flags", category.name))
+				continue
+			}
+			s.logger.Info(fmt.Sprintf("%s sync force-enabled via command line flags", category.name))
+		}
+		if err := category.sync(ctx); err != nil {
+			s.logger.Error(fmt.Sprintf("failure syncing %s", category.name), logger.Error(err))
+			s.recordError(category.name, "", err)
+			if !continueOnError || ctx.Err() != nil || isFatal(err) {
+				return syncError(synced, append(errs, err))
+			}
+			errs = append(errs, fmt.Errorf("failure syncing %s: %w", category.name, err))
+			continue
+		}
+		synced++
+	}
+	if len(errs) > 0 {
+		return syncError(synced, errs)
 	}
 	s.logger.Info("successfully ran the syncer")
 	return nil
 }
 
-func (s *Syncer) hydrate() (err error) {
-	var imdbLists []entities.IMDbList
+func (s *Syncer) saveCache(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	activities, err := s.traktClient.LastActivitiesGet(ctx)
+	if err != nil {
+		s.logger.Warn("failure fetching trakt last activities, the lists cache was not saved", logger.Error(err))
+		return
+	}
+	if s.conf.ModeFor(appconfig.SyncCategoryLists) != appconfig.SyncModeDryRun {
+		listFingerprints := make(map[string]string, len(s.unchangedLists)+len(s.syncedLists))
+		for id := range s.unchangedLists {
+			listFingerprints[id] = s.listFingerprints[id]
+		}
+		for id := range s.syncedLists {
+			if listFingerprint, found := s.listFingerprints[id]; found {
+				listFingerprints[id] = listFingerprint
+			}
+		}
+		s.cache.syncedLists(listFingerprints)
+	}
+	if s.conf.ModeFor(appconfig.SyncCategoryWatchlist) != appconfig.SyncModeDryRun {
+		var watchlistFingerprint string
+		if s.watchlistSynced {
+			watchlistFingerprint = s.watchlistFingerprint
+		}
+		s.cache.syncedWatchlist(activities.Watchlist.UpdatedAt, watchlistFingerprint)
+	}
+	if err = s.cache.save(activities.Lists.UpdatedAt); err != nil {
+		s.logger.Warn("failure saving the lists cache", logger.Error(err))
+	}
+}
+
+// saveCheckpoint keeps the checkpoint of a failed sync for the next run to resume from, and removes it once a sync succeeds
+func (s *Syncer) saveCheckpoint(syncErr error) {
+	if s.checkpoint == nil {
+		return
+	}
+	if syncErr == nil {
+		if err := s.checkpoint.clear(); err != nil {
+			s.logger.Warn("failure clearing the checkpoint", logger.Error(err))
+		}
+		return
+	}
+	if err := s.checkpoint.save(); err != nil {
+		s.logger.Warn("failure saving the checkpoint", logger.Error(err))
+		return
+	}
+	s.logger.Info(fmt.Sprintf("saved checkpoint %s, the next run resumes from it", s.checkpoint.path))
+}
+
+// saveRatingsState records the items seen rated once a sync succeeds, a failed sync may have fetched incomplete ratings
+func (s *Syncer) saveRatingsState(syncErr error) {
+	if s.ratings == nil || syncErr != nil || s.imdbConf.IsAuthless() {
+		return
+	}
+	imdbRated := make(map[string]struct{}, len(s.user.imdbRatings))
+	for id := range s.user.imdbRatings {
+		imdbRated[id] = struct{}{}
+	}
+	traktRated := make(map[string]struct{}, len(s.user.traktRatings))
+	for id := range s.user.traktRatings {
+		traktRated[id] = struct{}{}
+	}
+	if err := s.ratings.save(imdbRated, traktRated); err != nil {
+		s.logger.Warn("failure saving the ratings state", logger.Error(err))
+	}
+}
+
+// isProcessed reports whether an item was processed by an interrupted sync, according to the checkpoint
+func (s *Syncer) isProcessed(step, id string) bool {
+	return s.checkpoint != nil && s.checkpoint.isProcessed(step, id)
+}
+
+func (s *Syncer) markProcessed(step string, ids ...string) {
+	if s.checkpoint == nil {
+		return
+	}
+	if err := s.checkpoint.markProcessed(step, ids...); err != nil {
+		s.logger.Warn("failure saving the checkpoint", logger.Error(err))
+	}
+}
+
+// requiresRatings reports whether a category is synced from imdb ratings
+func requiresRatings(category string) bool {
+	return category == appconfig.SyncCategoryRatings || category == appconfig.SyncCategoryHistory
+}
+
+// isFatal reports whether an error would make the remaining categories fail too, in which case there's no point in continuing
+func isFatal(err error) bool {
+	var (
+		traktUnauthorizedErr *client.TraktUnauthorizedError
+		imdbAuthExpiredErr   *client.IMDbAuthExpiredError
+	)
+	return errors.As(err, &traktUnauthorizedErr) || errors.As(err, &imdbAuthExpiredErr)
+}
+
+// syncStatus describes the outcome of a run, a partial failure means some categories were synced despite the error
+func syncStatus(err error) string {
+	if err == nil {
+		return notifier.StatusSuccess
+	}
+	var partialSyncError *PartialSyncError
+	if errors.As(err, &partialSyncError) {
+		return notifier.StatusPartialFailure
+	}
+	return notifier.StatusFailure
+}
+
+// newRunID returns a random id telling the logs of concurrent or consecutive runs apart
+func newRunID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failure generating run id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// syncError joins the errors of the failed categories, the run is a partial failure only when at least one category was synced
+func syncError(synced int, errs []error) error {
+	if synced == 0 {
+		return errors.Join(errs...)
+	}
+	return &PartialSyncError{Err: errors.Join(errs...)}
+}
+
+func (s *Syncer) notify(err error) {
+	if s.notifier == nil {
+		return
+	}
+	notification := notifier.Notification{
+		RunID:  s.result.RunID,
+		Status: syncStatus(err),
+		Stats:  s.result.Stats(),
+	}
+	if err != nil {
+		notification.Error = err.Error()
+	}
+	if notifyErr := s.notifier.Notify(notification); notifyErr != nil {
+		s.logger.Warn("failure sending sync notification", logger.Error(notifyErr))
+	}
+}
+
+func (s *Syncer) hydrate(ctx context.Context) (err error) {
+	var (
+		imdbLists       []entities.IMDbList
+		imdbListsErrors []error
+		imdbRatings     []entities.IMDbItem
+		ratingsFetched  bool
+	)
+	if s.conf.HasHiddenSections() {
+		if err = s.hydrateHidden(ctx); err != nil {
+			return err
+		}
+	}
+	// the genre lists are made of the imdb ratings, which are fetched ahead of the lists in that case
+	if s.listsConf.HasGenreLists() && !s.imdbConf.IsRestricted() && !s.imdbConf.IsAuthless() {
+		if imdbRatings, err = s.imdbRatingsGet(ctx); err != nil {
+			return fmt.Errorf("failure fetching imdb ratings: %w", err)
+		}
+		ratingsFetched = true
+	}
 	if len(s.user.imdbLists) != 0 {
 		listIDs := make([]string, 0, len(s.user.imdbLists))
 		for id := range s.user.imdbLists {
 			listIDs = append(listIDs, id)
 		}
-		imdbLists, err = s.imdbClient.ListsGet(listIDs)
-		if err != nil {
-			return fmt.Errorf("failure hydrating imdb lists: %w", err)
-		}
+		imdbLists, imdbListsErrors = s.imdbListsGet(ctx, listIDs)
 	} else {
-		imdbLists, err = s.imdbClient.ListsGetAll()
-		if err != nil {
-			return fmt.Errorf("failure fetching all imdb lists: %w", err)
+		imdbLists, imdbListsErrors = s.imdbListsGetAll(ctx)
+	}
+	for _, delegatedErr := range imdbListsErrors {
+		var listErr *client.IMDbListError
+		if errors.As(delegatedErr, &listErr) {
+			s.logger.Warn(fmt.Sprintf("skipping imdb list %s, it could not be fetched", listErr.ListID), logger.Error(delegatedErr))
+			s.recordError(appconfig.SyncCategoryLists, listErr.ListID, delegatedErr)
+			delete(s.user.imdbLists, listErr.ListID)
+			s.result.SkippedLists = append(s.result.SkippedLists, SkippedList{
+				ListID: listErr.ListID,
+				Reason: delegatedErr.Error(),
+			})
+			continue
+		}
+		return fmt.Errorf("failure hydrating imdb lists: %w", delegatedErr)
+	}
+	if ratingsFetched {
+		imdbLists = append(imdbLists, s.genreLists(imdbRatings)...)
+	}
+	for i := range imdbLists {
+		s.removeDuplicates(&imdbLists[i])
+	}
+	imdbLists = s.routeLists(imdbLists)
+	var activities *entities.TraktLastActivities
+	if s.cache != nil {
+		if activities, err = s.traktClient.LastActivitiesGet(ctx); err != nil {
+			return fmt.Errorf("failure fetching trakt last activities: %w", err)
+		}
+		if imdbLists, err = s.skipUnchangedLists(imdbLists, activities); err != nil {
+			return err
 		}
 	}
 	traktIDMetas := make(entities.TraktIDMetas, 0, len(imdbLists))
 	for i := range imdbLists {
 		imdbList := imdbLists[i]
 		s.user.imdbLists[imdbList.ListID] = imdbList
+		owner, _ := s.listsConf.TraktListFor(imdbList.ListID)
+		traktListName := s.traktListName(imdbList)
 		traktIDMetas = append(traktIDMetas, entities.TraktIDMeta{
 			IMDb:     imdbList.ListID,
-			Slug:     entities.InferTraktListSlug(imdbList.ListName),
-			ListName: &imdbList.ListName,
+			Slug:     s.traktListSlug(imdbList),
+			Owner:    owner,
+			ListName: &traktListName,
 		})
 	}
-	traktLists, delegatedErrors := s.traktClient.ListsGet(traktIDMetas)
+	lookupIDMetas := traktIDMetas
+	var cachedLists []entities.TraktList
+	if s.cache != nil {
+		cachedLists, lookupIDMetas = s.cache.lookup(activities.Lists.UpdatedAt, traktIDMetas)
+		s.logger.Debug(fmt.Sprintf("reusing %d cached trakt list(s), fetching %d trakt list(s)", len(cachedLists), len(lookupIDMetas)))
+	}
+	traktLists, delegatedErrors := s.traktClient.ListsGet(ctx, lookupIDMetas)
+	if s.cache != nil {
+		s.cache.store(traktLists)
+		traktLists = append(traktLists, cachedLists...)
+	}
 	for _, delegatedErr := range delegatedErrors {
+		var notOwnedError *client.TraktListNotOwnedError
+		if errors.As(delegatedErr, &notOwnedError) {
+			var imdbListID string
+			for _, idMeta := range traktIDMetas {
+				if idMeta.Slug == notOwnedError.Slug && idMeta.Owner == notOwnedError.Owner {
+					imdbListID = idMeta.IMDb
+				}
+			}
+			s.logger.Warn(fmt.Sprintf("skipping imdb list %s, trakt list %s is owned by %s and can't be edited", imdbListID, notOwnedError.Slug, notOwnedError.Owner))
+			delete(s.user.imdbLists, imdbListID)
+			if err = s.likeList(ctx, imdbListID, notOwnedError.Owner, notOwnedError.Slug); err != nil {
+				return err
+			}
+			continue
+		}
 		var notFoundError *client.TraktListNotFoundError
 		if errors.As(delegatedErr, &notFoundError) {
 			listName := traktIDMetas.GetListNameFromSlug(notFoundError.Slug)
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-				msg := fmt.Sprintf("sync mode %s would have created trakt list %s to backfill imdb list %s", syncMode, notFoundError.Slug, listName)
+			imdbListID := traktIDMetas.GetIMDbIDFromSlug(notFoundError.Slug)
+			privacy := s.listsConf.PrivacyFor(imdbListID)
+			sortBy, sortHow := s.listsConf.SortFor(imdbListID)
+			if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun {
+				msg := fmt.Sprintf("sync mode %s would have created %s trakt list %s to backfill imdb list %s", syncMode, privacy, notFoundError.Slug, listName)
 				s.logger.Info(msg)
 				continue
 			}
-			if err = s.traktClient.ListAdd(notFoundError.Slug, listName); err != nil {
+			if _, err = s.traktClient.ListAdd(ctx, notFoundError.Slug, listName, privacy, sortBy, sortHow); err != nil {
+				var vipErr *client.TraktVIPRequiredError
+				if errors.As(err, &vipErr) {
+					s.logger.Warn(fmt.Sprintf("skipping imdb list %s, the trakt list could not be created", listName), logger.Error(err))
+					s.recordError(appconfig.SyncCategoryLists, imdbListID, err)
+					delete(s.user.imdbLists, imdbListID)
+					continue
+				}
 				return fmt.Errorf("failure creating trakt list: %w", err)
 			}
 			continue
@@ -130,30 +546,43 @@ func (s *Syncer) hydrate() (err error) {
 	}
 	for i := range traktLists {
 		traktList := traktLists[i]
+		traktList.ListItems = s.withIMDbIDs(fmt.Sprintf("list %s", traktList.IDMeta.Slug), traktList.ListItems, s.user.imdbLists[traktList.IDMeta.IMDb].ListItems)
 		s.user.traktLists[traktList.IDMeta.IMDb] = traktList
 	}
-	imdbWatchlist, err := s.imdbClient.WatchlistGet()
-	if err != nil {
-		return fmt.Errorf("failure fetching imdb watchlist: %w", err)
+	if err = s.reconcileLists(ctx, traktLists); err != nil {
+		return fmt.Errorf("failure reconciling trakt lists settings: %w", err)
 	}
-	s.user.imdbLists[imdbWatchlist.ListID] = *imdbWatchlist
-	traktWatchlist, err := s.traktClient.WatchlistGet()
-	if err != nil {
-		return fmt.Errorf("failure fetching trakt watchlist: %w", err)
+	if s.imdbConf.IsRestricted() {
+		s.logger.Info(fmt.Sprintf("skipping watchlist, syncing only imdb list(s) %s", strings.Join(s.imdbConf.Lists, ", ")))
+	} else if s.imdbConf.IsAuthless() {
+		s.logger.Info("skipping watchlist, it can't be fetched without imdb authentication")
+	} else {
+		imdbWatchlist, err := s.imdbWatchlistGet(ctx)
+		if err != nil {
+			return fmt.Errorf("failure fetching imdb watchlist: %w", err)
+		}
+		s.removeDuplicates(imdbWatchlist)
+		if err = s.hydrateWatchlist(ctx, *imdbWatchlist, activities); err != nil {
+			return err
+		}
 	}
-	s.user.traktLists[imdbWatchlist.ListID] = *traktWatchlist
-	imdbRatings, err := s.imdbClient.RatingsGet()
-	if err != nil {
-		return fmt.Errorf("failure fetching imdb ratings: %w", err)
+	if s.imdbConf.IsAuthless() {
+		return nil
+	}
+	if !ratingsFetched {
+		if imdbRatings, err = s.imdbRatingsGet(ctx); err != nil {
+			return fmt.Errorf("failure fetching imdb ratings: %w", err)
+		}
 	}
 	for i := range imdbRatings {
 		imdbRating := imdbRatings[i]
 		s.user.imdbRatings[imdbRating.ID] = imdbRating
 	}
-	traktRatings, err := s.traktClient.RatingsGet()
+	traktRatings, err := s.traktClient.RatingsGet(ctx)
 	if err != nil {
 		return fmt.Errorf("failure fetching trakt ratings: %w", err)
 	}
+	traktRatings = s.withIMDbIDs("ratings", traktRatings, imdbRatings)
 	for i := range traktRatings {
 		traktRating := traktRatings[i]
 		id, err := traktRating.GetItemID()
@@ -167,144 +596,1719 @@ func (s *Syncer) hydrate() (err error) {
 	return nil
 }
 
-func (s *Syncer) syncLists() error {
-	for _, list := range s.user.imdbLists {
-		traktListSlug := entities.InferTraktListSlug(list.ListName)
-		diff := entities.ListDifference(list, s.user.traktLists[list.ListID])
-		if list.IsWatchlist {
-			if len(diff["add"]) > 0 {
-				if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-					msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
-					s.logger.Info(msg, slog.Any("watchlist", diff["add"]))
-					continue
-				}
-				if err := s.traktClient.WatchlistItemsAdd(diff["add"]); err != nil {
-					return fmt.Errorf("failure adding items to trakt watchlist: %w", err)
-				}
-			}
-			if len(diff["remove"]) > 0 {
-				if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-					msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
-					s.logger.Info(msg, slog.Any("watchlist", diff["remove"]))
-					continue
-				}
-				if err := s.traktClient.WatchlistItemsRemove(diff["remove"]); err != nil {
-					return fmt.Errorf("failure removing items from trakt watchlist: %w", err)
-				}
-			}
+// skipUnchangedLists leaves out the imdb lists synced successfully by the previous run, when neither they nor the trakt lists changed since
+// the lists mirrored to the same trakt list are only skipped together, since their items are compared with the trakt list as a whole,
+// while the lists with a rating threshold are never skipped, since the imdb ratings they depend on are not part of their fingerprint
+func (s *Syncer) skipUnchangedLists(imdbLists []entities.IMDbList, activities *entities.TraktLastActivities) ([]entities.IMDbList, error) {
+	s.listFingerprints = make(map[string]string, len(imdbLists))
+	s.unchangedLists = make(map[string]entities.IMDbList)
+	changedSlugs := make(map[string]struct{})
+	for _, list := range imdbLists {
+		slug := s.traktListSlug(list)
+		if s.listsConf.RatingThresholdFor(list.ListID) != 0 {
+			changedSlugs[slug] = struct{}{}
 			continue
 		}
-		if len(diff["add"]) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-				msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
-				s.logger.Info(msg, slog.Any(traktListSlug, diff["add"]))
-				continue
-			}
-			if err := s.traktClient.ListItemsAdd(traktListSlug, diff["add"]); err != nil {
-				return fmt.Errorf("failure adding items to trakt list %s: %w", traktListSlug, err)
-			}
+		ids := make([]string, 0, len(list.ListItems))
+		for _, item := range list.ListItems {
+			ids = append(ids, item.ID)
 		}
-		if len(diff["remove"]) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
-				s.logger.Info(msg, slog.Any(traktListSlug, diff["remove"]))
-				continue
-			}
-			if err := s.traktClient.ListItemsRemove(traktListSlug, diff["remove"]); err != nil {
-				return fmt.Errorf("failure removing items from trakt list %s: %w", traktListSlug, err)
-			}
+		slices.Sort(ids)
+		listFingerprint, err := fingerprint([]any{s.configFingerprint, list.ListName, ids})
+		if err != nil {
+			return nil, fmt.Errorf("failure fingerprinting imdb list %s: %w", list.ListID, err)
+		}
+		s.listFingerprints[list.ListID] = listFingerprint
+		if s.conf.ModeFor(appconfig.SyncCategoryLists) == appconfig.SyncModeDryRun || !s.cache.listUnchanged(activities.Lists.UpdatedAt, list.ListID, listFingerprint) {
+			changedSlugs[slug] = struct{}{}
 		}
 	}
-	return nil
+	changed := make([]entities.IMDbList, 0, len(imdbLists))
+	for _, list := range imdbLists {
+		if _, found := changedSlugs[s.traktListSlug(list)]; found {
+			changed = append(changed, list)
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("skipping imdb list %s, neither it nor the trakt lists changed since the previous run", list.ListID))
+		delete(s.user.imdbLists, list.ListID)
+		s.unchangedLists[list.ListID] = list
+	}
+	return changed, nil
 }
 
-func (s *Syncer) syncRatings() error {
-	diff := entities.ItemsDifference(s.user.imdbRatings, s.user.traktRatings)
-	if len(diff["add"]) > 0 {
-		if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-			msg := fmt.Sprintf("sync mode %s would have added %d trakt rating item(s)", syncMode, len(diff["add"]))
-			s.logger.Info(msg, slog.Any("ratings", diff["add"]))
-		} else {
-			if err := s.traktClient.RatingsAdd(diff["add"]); err != nil {
-				return fmt.Errorf("failure adding trakt ratings: %w", err)
-			}
+// hydrateWatchlist fetches the trakt watchlist to be synced with the imdb watchlist, unless neither of them changed since the previous run
+// the previous run is only taken into account when the lists cache is enabled, and never in dry-run sync mode, which doesn't change anything
+func (s *Syncer) hydrateWatchlist(ctx context.Context, imdbWatchlist entities.IMDbList, activities *entities.TraktLastActivities) error {
+	if s.cache != nil {
+		ids := make([]string, 0, len(imdbWatchlist.ListItems))
+		for _, item := range imdbWatchlist.ListItems {
+			ids = append(ids, item.ID)
+		}
+		slices.Sort(ids)
+		var err error
+		if s.watchlistFingerprint, err = fingerprint([]any{s.configFingerprint, ids}); err != nil {
+			return fmt.Errorf("failure fingerprinting imdb watchlist: %w", err)
+		}
+		// the watchlist mirrors are trakt lists, which may change independently of the trakt watchlist
+		if s.conf.ModeFor(appconfig.SyncCategoryWatchlist) != appconfig.SyncModeDryRun && len(s.listsConf.WatchlistMirrors) == 0 && s.cache.watchlistUnchanged(activities.Watchlist.UpdatedAt, s.watchlistFingerprint) {
+			s.logger.Info("skipping watchlist, neither the imdb nor the trakt watchlist changed since the previous run")
+			s.watchlistSynced = true
+			return nil
 		}
 	}
-	if len(diff["remove"]) > 0 {
-		if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt rating item(s)", syncMode, len(diff["remove"]))
-			s.logger.Info(msg, slog.Any("ratings", diff["remove"]))
-		} else {
-			if err := s.traktClient.RatingsRemove(diff["remove"]); err != nil {
-				return fmt.Errorf("failure removing trakt ratings: %w", err)
-			}
+	traktWatchlist, err := s.traktClient.WatchlistGet(ctx)
+	if err != nil {
+		if s.skipWatchlist(err) {
+			return nil
 		}
+		return fmt.Errorf("failure fetching trakt watchlist: %w", err)
 	}
-	return nil
+	traktWatchlist.ListItems = s.withIMDbIDs("watchlist", traktWatchlist.ListItems, imdbWatchlist.ListItems)
+	s.user.imdbLists[imdbWatchlist.ListID] = imdbWatchlist
+	s.user.traktLists[imdbWatchlist.ListID] = *traktWatchlist
+	return s.hydrateWatchlistMirrors(ctx, imdbWatchlist)
 }
 
-func (s *Syncer) syncHistory() error {
-	if *s.conf.SkipHistory {
-		s.logger.Info("skipping history sync")
-		return nil
+// withIMDbIDs leaves out the trakt items lacking an imdb id, since items are compared by imdb id, unless they're matched to one of the imdb items
+// by title and year when enabled. Their number is logged and reported, so that such gaps in the comparison aren't silent
+func (s *Syncer) withIMDbIDs(target string, items entities.TraktItems, imdbItems []entities.IMDbItem) entities.TraktItems {
+	var index entities.TitleIndex
+	if s.conf.ShouldMatchMissingIDs() {
+		index = entities.NewTitleIndex(imdbItems)
+		// the matched ids are set on a copy, leaving the items of the lists cache as trakt returned them
+		items = slices.Clone(items)
 	}
-	// imdb doesn't offer functionality similar to trakt history, hence why there can't be a direct mapping between them
-	// the syncer will assume a user to have watched an item if they've submitted a rating for it
-	// if the above is satisfied and the user's history for this item is empty, a new history entry is added!
-	diff := entities.ItemsDifference(s.user.imdbRatings, s.user.traktRatings)
-	if len(diff["add"]) > 0 {
-		var historyToAdd entities.TraktItems
-		for i := range diff["add"] {
-			traktItemID, err := diff["add"][i].GetItemID()
-			if err != nil {
-				return fmt.Errorf("failure fetching trakt item id: %w", err)
-			}
-			history, err := s.traktClient.HistoryGet(diff["add"][i].Type, *traktItemID)
-			if err != nil {
-				return fmt.Errorf("failure fetching trakt history for %s %s: %w", diff["add"][i].Type, *traktItemID, err)
-			}
-			if len(history) > 0 {
+	var matched, skipped entities.TraktItems
+	kept := make(entities.TraktItems, 0, len(items))
+	for i := range items {
+		id, err := items[i].GetItemID()
+		if err != nil || id == nil || *id != "" {
+			kept = append(kept, items[i])
+			continue
+		}
+		if index != nil {
+			if imdbID, found := index.Match(items[i]); found {
+				*id = imdbID
+				matched = append(matched, items[i])
+				kept = append(kept, items[i])
 				continue
 			}
-			historyToAdd = append(historyToAdd, diff["add"][i])
 		}
-		if len(historyToAdd) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-				msg := fmt.Sprintf("sync mode %s would have added %d trakt history item(s)", syncMode, len(historyToAdd))
-				s.logger.Info(msg, slog.Any("history", historyToAdd))
-			} else {
-				if err := s.traktClient.HistoryAdd(historyToAdd); err != nil {
-					return fmt.Errorf("failure adding trakt history: %w", err)
-				}
+		skipped = append(skipped, items[i])
+	}
+	if len(matched) > 0 {
+		s.logger.Info(fmt.Sprintf("matched %d trakt %s item(s) without an imdb id by title and year", len(matched), target), s.diffItems(target, matched))
+	}
+	if len(skipped) > 0 {
+		s.logger.Info(fmt.Sprintf("skipping %d trakt %s item(s) without an imdb id", len(skipped), target), s.diffItems(target, skipped))
+		s.result.SkippedWithoutIMDbID += len(skipped)
+	}
+	return kept
+}
+
+// skipWatchlist reports whether the error tells the trakt watchlist is unavailable, in which case the watchlist is skipped rather than failing the sync
+func (s *Syncer) skipWatchlist(err error) bool {
+	var unavailableErr *client.TraktWatchlistUnavailableError
+	if !errors.As(err, &unavailableErr) {
+		return false
+	}
+	s.logger.Warn("skipping watchlist, the trakt watchlist is unavailable", logger.Error(err))
+	s.recordError(appconfig.SyncCategoryWatchlist, "", err)
+	s.result.SkippedWatchlist = err.Error()
+	return true
+}
+
+// likeList likes the trakt list of another user an imdb list is mirrored to, when enabled for the imdb list
+func (s *Syncer) likeList(ctx context.Context, imdbListID, owner, slug string) error {
+	if !s.listsConf.ShouldLike(imdbListID) {
+		return nil
+	}
+	if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun {
+		s.logger.Info(fmt.Sprintf("sync mode %s would have liked trakt list %s of user %s", syncMode, slug, owner))
+		return nil
+	}
+	if err := s.traktClient.ListLike(ctx, owner, slug); err != nil {
+		return fmt.Errorf("failure liking trakt list %s of user %s: %w", slug, owner, err)
+	}
+	return nil
+}
+
+// genreLists returns a list per configured imdb genre, made of the rated items of the genre, which is synced like an imdb list
+// the genres come along with the imdb ratings export, hence no requests are sent besides the ones for the trakt lists
+func (s *Syncer) genreLists(imdbRatings []entities.IMDbItem) []entities.IMDbList {
+	genreLists := make([]entities.IMDbList, 0, len(s.listsConf.Genres))
+	for _, genre := range s.listsConf.Genres {
+		list := entities.IMDbList{
+			ListID:   appconfig.GenreListID(genre),
+			ListName: genre,
+		}
+		for _, item := range imdbRatings {
+			if slices.ContainsFunc(item.Genres, func(itemGenre string) bool {
+				return strings.EqualFold(itemGenre, genre)
+			}) {
+				list.ListItems = append(list.ListItems, item)
 			}
 		}
+		s.logger.Debug(fmt.Sprintf("adding %d rated item(s) of imdb genre %s to genre list %s", len(list.ListItems), genre, list.ListID))
+		genreLists = append(genreLists, list)
 	}
-	if len(diff["remove"]) > 0 {
-		var historyToRemove entities.TraktItems
-		for i := range diff["remove"] {
-			traktItemID, err := diff["remove"][i].GetItemID()
-			if err != nil {
-				return fmt.Errorf("failure fetching trakt item id: %w", err)
-			}
-			history, err := s.traktClient.HistoryGet(diff["remove"][i].Type, *traktItemID)
-			if err != nil {
-				return fmt.Errorf("failure fetching trakt history for %s %s: %w", diff["remove"][i].Type, *traktItemID, err)
+	return genreLists
+}
+
+// routeLists splits the imdb lists with rules into parts, one per trakt list the rules route items to, which are synced like separate imdb lists
+// the rules are evaluated in order and the first one an item matches takes precedence, the items matching none of them stay in the imdb list
+// that is synced to its own trakt list as usual, unless unmatched items are skipped
+func (s *Syncer) routeLists(imdbLists []entities.IMDbList) []entities.IMDbList {
+	routed := make([]entities.IMDbList, 0, len(imdbLists))
+	for _, list := range imdbLists {
+		rules := s.listsConf.RulesFor(list.ListID)
+		if len(rules) == 0 {
+			routed = append(routed, list)
+			continue
+		}
+		parts := make(map[string]*entities.IMDbList, len(rules))
+		var slugs []string
+		for _, rule := range rules {
+			slug := *rule.TraktList
+			if _, found := parts[slug]; !found {
+				// routed lists are named after their slug, since trakt derives the slug of a list from its name
+				parts[slug] = &entities.IMDbList{
+					ListID:   appconfig.RoutedListID(list.ListID, slug),
+					ListName: slug,
+					Partial:  list.Partial,
+				}
+				slugs = append(slugs, slug)
 			}
-			if len(history) == 0 {
+		}
+		unmatched := list
+		unmatched.ListItems = nil
+		for _, item := range list.ListItems {
+			itemType := item.ToTraktItem().Type
+			index := slices.IndexFunc(rules, func(rule appconfig.ListRule) bool {
+				return rule.Matches(itemType, item.Genres, item.Year)
+			})
+			if index == -1 {
+				unmatched.ListItems = append(unmatched.ListItems, item)
 				continue
 			}
-			historyToRemove = append(historyToRemove, diff["remove"][i])
+			part := parts[*rules[index].TraktList]
+			part.ListItems = append(part.ListItems, item)
 		}
-		if len(historyToRemove) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt history item(s)", syncMode, len(historyToRemove))
-				s.logger.Info(msg, slog.Any("history", historyToRemove))
-			} else {
-				if err := s.traktClient.HistoryRemove(historyToRemove); err != nil {
-					return fmt.Errorf("failure removing trakt history: %w", err)
-				}
-			}
+		for _, slug := range slugs {
+			s.logger.Debug(fmt.Sprintf("routing %d item(s) of imdb list %s to trakt list %s", len(parts[slug].ListItems), list.ListID, slug))
+			routed = append(routed, *parts[slug])
+		}
+		if s.listsConf.ShouldSkipUnmatched(list.ListID) {
+			s.logger.Debug(fmt.Sprintf("skipping %d item(s) of imdb list %s matching none of its rules", len(unmatched.ListItems), list.ListID))
+			delete(s.user.imdbLists, list.ListID)
+			continue
 		}
+		routed = append(routed, unmatched)
 	}
-	return nil
+	return routed
+}
+
+// traktListName returns the name the trakt list mirroring an imdb list is created with
+func (s *Syncer) traktListName(list entities.IMDbList) string {
+	if appconfig.IsRoutedListID(list.ListID) {
+		return list.ListName
+	}
+	return s.listsConf.TraktListName(list.ListName)
+}
+
+// traktListSlug returns the slug of the trakt list an imdb list is mirrored to, unless overridden it is inferred from the trakt list name
+// trakt derives the slug from the name the list is created with, hence the name prefix and suffix are part of it
+func (s *Syncer) traktListSlug(list entities.IMDbList) string {
+	if _, slug := s.listsConf.TraktListFor(list.ListID); slug != "" {
+		return slug
+	}
+	rules := entities.SlugRules{
+		Transliterate: s.listsConf.Slug.ShouldTransliterate(),
+		Replacements:  s.listsConf.Slug.Replacements,
+	}
+	return rules.InferTraktListSlug(s.listsConf.TraktListName(list.ListName))
+}
+
+// reconcileLists updates the settings of existing trakt lists which differ from the configured ones
+// privacy, name and description are only reconciled when enabled, while the sort settings are reconciled whenever they are configured
+func (s *Syncer) reconcileLists(ctx context.Context, traktLists []entities.TraktList) error {
+	reconcilePrivacy := s.listsConf.ShouldReconcilePrivacy()
+	for _, traktList := range traktLists {
+		sortBy, sortHow := s.listsConf.SortFor(traktList.IDMeta.IMDb)
+		reconcileDetails := s.shouldReconcileDetails(traktList.IDMeta.IMDb)
+		if !reconcilePrivacy && !reconcileDetails && sortBy == "" && sortHow == "" {
+			continue
+		}
+		summary, err := s.traktClient.ListSummaryGet(ctx, traktList.IDMeta.Slug)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt list %s summary: %w", traktList.IDMeta.Slug, err)
+		}
+		var (
+			body    entities.TraktListUpdateBody
+			changes []string
+		)
+		if privacy := s.listsConf.PrivacyFor(traktList.IDMeta.IMDb); reconcilePrivacy && summary.Privacy != privacy {
+			body.Privacy = &privacy
+			changes = append(changes, fmt.Sprintf("privacy from %s to %s", summary.Privacy, privacy))
+		}
+		if sortBy != "" && summary.SortBy != sortBy {
+			body.SortBy = &sortBy
+			changes = append(changes, fmt.Sprintf("sort by from %s to %s", summary.SortBy, sortBy))
+		}
+		if sortHow != "" && summary.SortHow != sortHow {
+			body.SortHow = &sortHow
+			changes = append(changes, fmt.Sprintf("sort how from %s to %s", summary.SortHow, sortHow))
+		}
+		if reconcileDetails {
+			detailChanges, err := s.reconcileListDetails(ctx, s.user.imdbLists[traktList.IDMeta.IMDb], summary, &body)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, detailChanges...)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have changed trakt list %s %s", syncMode, traktList.IDMeta.Slug, strings.Join(changes, ", "))
+			s.logger.Info(msg)
+			continue
+		}
+		if err = s.traktClient.ListUpdate(ctx, traktList.IDMeta.Slug, body); err != nil {
+			return fmt.Errorf("failure updating trakt list %s: %w", traktList.IDMeta.Slug, err)
+		}
+	}
+	return nil
+}
+
+// shouldReconcileDetails reports whether the name and description of the trakt list mirroring an imdb list follow the imdb list
+// lists routed by rules or mirrored to a trakt list set via override are named by the user, hence they are left untouched
+func (s *Syncer) shouldReconcileDetails(imdbListID string) bool {
+	if !s.listsConf.ShouldReconcileDetails() || appconfig.IsRoutedListID(imdbListID) || appconfig.IsGenreListID(imdbListID) {
+		return false
+	}
+	_, slug := s.listsConf.TraktListFor(imdbListID)
+	return slug == ""
+}
+
+// reconcileListDetails sets the name and description of the imdb list on the update body, when they differ from the trakt list
+func (s *Syncer) reconcileListDetails(ctx context.Context, imdbList entities.IMDbList, summary *entities.TraktList, body *entities.TraktListUpdateBody) ([]string, error) {
+	var changes []string
+	var traktName, traktDescription string
+	if summary.Name != nil {
+		traktName = *summary.Name
+	}
+	if summary.Description != nil {
+		traktDescription = *summary.Description
+	}
+	if name := s.traktListName(imdbList); name != "" && name != traktName {
+		body.Name = &name
+		changes = append(changes, fmt.Sprintf("name from %q to %q", traktName, name))
+	}
+	description, err := s.imdbClient.ListDescriptionGet(ctx, imdbList.ListID)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching imdb list %s description: %w", imdbList.ListID, err)
+	}
+	if description != "" && description != traktDescription {
+		body.Description = &description
+		changes = append(changes, fmt.Sprintf("description from %q to %q", traktDescription, description))
+	}
+	return changes, nil
+}
+
+func (s *Syncer) removeDuplicates(list *entities.IMDbList) {
+	if duplicates := list.RemoveDuplicates(); duplicates > 0 {
+		s.logger.Info(fmt.Sprintf("collapsed %d duplicate item(s) in imdb list %s", duplicates, list.ListID))
+	}
+}
+
+func (s *Syncer) syncLists(ctx context.Context) error {
+	// lists mirrored to the same trakt list would otherwise each remove the items missing from all of them
+	handledRemovals := make(map[string]map[string]struct{})
+	// the lists capped by SYNC_MAXITEMS are left out, since items of theirs are left to sync by the next run
+	synced := make(map[string]struct{})
+	for _, list := range s.user.imdbLists {
+		traktListSlug := s.traktListSlug(list)
+		diff, err := s.listDifference(list)
+		if err != nil {
+			return err
+		}
+		diff["add"] = s.withoutHidden(traktListSlug, diff["add"])
+		adds, removals := len(diff["add"]), len(diff["remove"])
+		// like capped lists, the lists with rejected changes are left to be reviewed again by the next run
+		diff["add"] = s.withoutRejected(s.reviewTarget(list), diff["add"])
+		diff["remove"] = s.withoutRejected(s.reviewTarget(list), diff["remove"])
+		diff["add"] = s.capItems(traktListSlug, "add", diff["add"])
+		diff["remove"] = s.capItems(traktListSlug, "removal", diff["remove"])
+		if !list.IsWatchlist && len(diff["add"]) == adds && len(diff["remove"]) == removals {
+			synced[list.ListID] = struct{}{}
+		}
+		if len(diff["remove"]) > 0 && s.listsConf.NoRemoveFor(list.ListID) {
+			msg := fmt.Sprintf("skipping removal of %d trakt list item(s), removals are disabled for imdb list %s", len(diff["remove"]), list.ListID)
+			s.logger.Info(msg, s.diffItems(traktListSlug, diff["remove"]))
+			delete(diff, "remove")
+		}
+		if len(diff["remove"]) > 0 && s.isPartial(list) {
+			msg := fmt.Sprintf("skipping removal of %d trakt list item(s), imdb list %s was read from its web page, which may not show all of its items", len(diff["remove"]), list.ListID)
+			s.logger.Warn(msg, s.diffItems(traktListSlug, diff["remove"]))
+			delete(diff, "remove")
+		}
+		if !list.IsWatchlist {
+			if diff["remove"], err = s.unhandledRemovals(handledRemovals, traktListSlug, diff["remove"]); err != nil {
+				return err
+			}
+		}
+		if list.IsWatchlist {
+			if err = s.syncWatchlistMirrors(ctx, list, diff["remove"]); err != nil {
+				return err
+			}
+			if len(diff["add"]) > 0 {
+				if syncMode := s.conf.ModeFor(appconfig.SyncCategoryWatchlist); syncMode == appconfig.SyncModeDryRun {
+					msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
+					s.logger.Info(msg, s.diffItems("watchlist", diff["add"]))
+					s.result.Watchlist.PendingAdd = append(s.result.Watchlist.PendingAdd, diff["add"]...)
+					continue
+				}
+				if err := s.checkAdditions("watchlist", diff["add"], len(s.user.traktLists[list.ListID].ListItems)); err != nil {
+					return err
+				}
+				added, err := s.addWithinLimit("watchlist", diff["add"], len(s.user.traktLists[list.ListID].ListItems), func(items entities.TraktItems) (*entities.TraktResponse, error) {
+					return s.traktClient.WatchlistItemsAdd(ctx, items)
+				})
+				if err != nil {
+					if s.skipWatchlist(err) {
+						continue
+					}
+					return fmt.Errorf("failure adding items to trakt watchlist: %w", err)
+				}
+				s.result.Watchlist.Added = append(s.result.Watchlist.Added, added...)
+			}
+			if len(diff["remove"]) > 0 {
+				if syncMode := s.conf.ModeFor(appconfig.SyncCategoryWatchlist); syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+					msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
+					s.logger.Info(msg, s.diffItems("watchlist", diff["remove"]))
+					s.result.Watchlist.PendingRemove = append(s.result.Watchlist.PendingRemove, diff["remove"]...)
+					// the history adds are only reported, which requires the history sync mode to be a dry run as well
+					if syncMode == appconfig.SyncModeDryRun && s.conf.ModeFor(appconfig.SyncCategoryHistory) == appconfig.SyncModeDryRun && s.conf.ShouldWatchlistRemovalImplyWatched() {
+						if err := s.addWatchlistRemovalsToHistory(ctx, list, diff["remove"]); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				if err := s.checkRemovals("watchlist", diff["remove"], len(s.user.traktLists[list.ListID].ListItems)); err != nil {
+					return err
+				}
+				if err := s.archiveItems(ctx, list, diff["remove"]); err != nil {
+					return err
+				}
+				if err := s.traktClient.WatchlistItemsRemove(ctx, diff["remove"]); err != nil {
+					if s.skipWatchlist(err) {
+						continue
+					}
+					return fmt.Errorf("failure removing items from trakt watchlist: %w", err)
+				}
+				s.result.Watchlist.Removed = append(s.result.Watchlist.Removed, diff["remove"]...)
+				if s.conf.ShouldWatchlistRemovalImplyWatched() {
+					if err := s.addWatchlistRemovalsToHistory(ctx, list, diff["remove"]); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if len(diff["add"]) > 0 {
+			if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun {
+				msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
+				s.logger.Info(msg, s.diffItems(traktListSlug, diff["add"]))
+				s.result.Lists.PendingAdd = append(s.result.Lists.PendingAdd, diff["add"]...)
+				continue
+			}
+			if err := s.checkAdditions(fmt.Sprintf("list %s", traktListSlug), diff["add"], len(s.user.traktLists[list.ListID].ListItems)); err != nil {
+				return err
+			}
+			s.invalidateCache(traktListSlug)
+			added, err := s.addWithinLimit(fmt.Sprintf("list %s", traktListSlug), diff["add"], len(s.user.traktLists[list.ListID].ListItems), func(items entities.TraktItems) (*entities.TraktResponse, error) {
+				return s.traktClient.ListItemsAdd(ctx, traktListSlug, items)
+			})
+			if err != nil {
+				return fmt.Errorf("failure adding items to trakt list %s: %w", traktListSlug, err)
+			}
+			s.result.Lists.Added = append(s.result.Lists.Added, added...)
+		}
+		if len(diff["remove"]) > 0 {
+			if syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists); syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
+				s.logger.Info(msg, s.diffItems(traktListSlug, diff["remove"]))
+				s.result.Lists.PendingRemove = append(s.result.Lists.PendingRemove, diff["remove"]...)
+				continue
+			}
+			if err := s.checkRemovals(fmt.Sprintf("list %s", traktListSlug), diff["remove"], len(s.user.traktLists[list.ListID].ListItems)); err != nil {
+				return err
+			}
+			if err := s.archiveItems(ctx, list, diff["remove"]); err != nil {
+				return err
+			}
+			s.invalidateCache(traktListSlug)
+			if err := s.traktClient.ListItemsRemove(ctx, traktListSlug, diff["remove"]); err != nil {
+				return fmt.Errorf("failure removing items from trakt list %s: %w", traktListSlug, err)
+			}
+			s.result.Lists.Removed = append(s.result.Lists.Removed, diff["remove"]...)
+		}
+	}
+	if s.listsConf.ShouldRemoveEmpty() {
+		if err := s.removeEmptyLists(ctx); err != nil {
+			return fmt.Errorf("failure removing empty trakt lists: %w", err)
+		}
+	}
+	s.watchlistSynced = s.watchlistFingerprint != "" && s.result.SkippedWatchlist == ""
+	s.syncedLists = synced
+	return nil
+}
+
+// listDifference compares an imdb list with its trakt list, leaving out the imdb items rated below the rating threshold of the list,
+// as well as the watchlist items of types left out by the watchlist types. Such trakt items are kept, unless their removal is enabled
+func (s *Syncer) listDifference(list entities.IMDbList) (map[string]entities.TraktItems, error) {
+	return s.targetDifference(list, s.user.traktLists[list.ListID])
+}
+
+// targetDifference compares an imdb list with a trakt list it is mirrored to, see listDifference
+func (s *Syncer) targetDifference(list entities.IMDbList, traktList entities.TraktList) (map[string]entities.TraktItems, error) {
+	siblings := s.listSiblings(list)
+	threshold := s.listsConf.RatingThresholdFor(list.ListID)
+	var types []string
+	if list.IsWatchlist {
+		types = s.conf.WatchlistTypes
+	}
+	if threshold == 0 && len(types) == 0 {
+		return entities.ListUnionDifference(list, siblings, traktList), nil
+	}
+	isFiltered := func(itemType string) bool {
+		return len(types) != 0 && !slices.Contains(types, itemType)
+	}
+	filtered := list
+	filtered.ListItems = nil
+	below := make(map[string]struct{})
+	for _, item := range list.ListItems {
+		if isFiltered(item.ToTraktItem().Type) {
+			continue
+		}
+		if threshold == 0 || s.meetsRatingThreshold(item.ID, threshold) {
+			filtered.ListItems = append(filtered.ListItems, item)
+			continue
+		}
+		below[item.ID] = struct{}{}
+	}
+	diff := entities.ListUnionDifference(filtered, siblings, traktList)
+	removeBelow := s.listsConf.RemoveBelowMinRatingFor(list.ListID)
+	removeFiltered := s.conf.ShouldRemoveFilteredWatchlistItems()
+	var remove entities.TraktItems
+	for _, item := range diff["remove"] {
+		// the trakt items of filtered types are kept, whether they are part of the imdb list or not
+		if isFiltered(item.Type) && !removeFiltered {
+			continue
+		}
+		id, err := item.GetItemID()
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id != nil && !removeBelow {
+			if _, found := below[*id]; found {
+				continue
+			}
+		}
+		remove = append(remove, item)
+	}
+	diff["remove"] = remove
+	return diff, nil
+}
+
+// unhandledRemovals drops the items whose removal from a trakt list was already handled while syncing a sibling imdb list, marking the rest as handled
+func (s *Syncer) unhandledRemovals(handled map[string]map[string]struct{}, slug string, items entities.TraktItems) (entities.TraktItems, error) {
+	if handled[slug] == nil {
+		handled[slug] = make(map[string]struct{})
+	}
+	var unhandled entities.TraktItems
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id == nil || *id == "" {
+			continue
+		}
+		if _, found := handled[slug][*id]; found {
+			continue
+		}
+		handled[slug][*id] = struct{}{}
+		unhandled = append(unhandled, item)
+	}
+	return unhandled, nil
+}
+
+// isPartial tells whether the imdb list, or another one mirrored to the same trakt list, may be missing items, which can't be removed from trakt then
+func (s *Syncer) isPartial(list entities.IMDbList) bool {
+	if list.Partial {
+		return true
+	}
+	return slices.ContainsFunc(s.listSiblings(list), func(sibling entities.IMDbList) bool {
+		return sibling.Partial
+	})
+}
+
+// listSiblings returns the other imdb lists mirrored to the same trakt list as the given one, since their names map to the same slug
+func (s *Syncer) listSiblings(list entities.IMDbList) []entities.IMDbList {
+	if list.IsWatchlist {
+		return nil
+	}
+	slug := s.traktListSlug(list)
+	var siblings []entities.IMDbList
+	for id, sibling := range s.user.imdbLists {
+		if id != list.ListID && !sibling.IsWatchlist && s.traktListSlug(sibling) == slug {
+			siblings = append(siblings, sibling)
+		}
+	}
+	return siblings
+}
+
+// invalidateCache drops a trakt list from the lists cache before it gets modified, so that a failed modification can't leave stale contents behind
+func (s *Syncer) invalidateCache(slug string) {
+	if s.cache != nil {
+		s.cache.invalidate(slug)
+	}
+}
+
+// hydrateHidden collects the ids of the items hidden in the configured trakt sections, which are left out of the additions
+func (s *Syncer) hydrateHidden(ctx context.Context) error {
+	s.user.traktHidden = make(map[string]struct{})
+	for _, section := range s.conf.HiddenSections {
+		items, err := s.traktClient.HiddenGet(ctx, section)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt items hidden from %s: %w", section, err)
+		}
+		for _, item := range items {
+			if id, _ := item.GetItemID(); id != nil && *id != "" {
+				s.user.traktHidden[*id] = struct{}{}
+			}
+		}
+	}
+	return nil
+}
+
+// withoutHidden leaves the items hidden on trakt out of the items to be added to the target, logging every suppressed addition
+func (s *Syncer) withoutHidden(target string, items entities.TraktItems) entities.TraktItems {
+	if len(s.user.traktHidden) == 0 || len(items) == 0 {
+		return items
+	}
+	var kept, hidden entities.TraktItems
+	for _, item := range items {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := s.user.traktHidden[*id]; found {
+				hidden = append(hidden, item)
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	if len(hidden) > 0 {
+		s.logger.Info(fmt.Sprintf("skipping addition of %d %s item(s), they are hidden on trakt", len(hidden), target), s.diffItems(target, hidden))
+	}
+	return kept
+}
+
+// diffItems describes the items left untouched due to the sync mode, unless listing them is disabled to keep large diffs out of the logs
+func (s *Syncer) diffItems(key string, items entities.TraktItems) slog.Attr {
+	if !s.conf.ShouldLogItems() {
+		return slog.Attr{}
+	}
+	return slog.Any(key, items.ExplainedLabels())
+}
+
+// addWithinLimit adds the items using the add func, trimming them to fit the account limit when trakt requires vip for adding all of them
+// it returns the items trakt confirmed, according to its response
+func (s *Syncer) addWithinLimit(target string, items entities.TraktItems, existing int, add func(entities.TraktItems) (*entities.TraktResponse, error)) (entities.TraktItems, error) {
+	response, err := add(items)
+	if err == nil {
+		return s.confirmedItems(target, items, response), nil
+	}
+	var vipErr *client.TraktVIPRequiredError
+	if !errors.As(err, &vipErr) {
+		return nil, err
+	}
+	available := vipErr.Limit - existing
+	if vipErr.Limit == 0 || available <= 0 {
+		s.logger.Warn(fmt.Sprintf("skipping %d item(s), trakt account limit reached", len(items)), logger.Error(err))
+		return nil, nil
+	}
+	if available >= len(items) {
+		return nil, err
+	}
+	s.logger.Warn(fmt.Sprintf("skipping %d item(s) exceeding the trakt account limit of %d", len(items)-available, vipErr.Limit), logger.Error(err))
+	if response, err = add(items[:available]); err != nil {
+		return nil, err
+	}
+	return s.confirmedItems(target, items[:available], response), nil
+}
+
+// confirmedItems drops the items trakt reported as not found from the items sent to it, the items that already existed are kept, since they are synced
+func (s *Syncer) confirmedItems(target string, items entities.TraktItems, response *entities.TraktResponse) entities.TraktItems {
+	if response == nil {
+		return items
+	}
+	if existing := response.Existing.Total(); existing > 0 {
+		s.logger.Debug(fmt.Sprintf("trakt %s already contained %d of the item(s)", target, existing))
+	}
+	notFound := response.NotFoundIDs()
+	if len(notFound) == 0 {
+		return items
+	}
+	var confirmed, skipped entities.TraktItems
+	for _, item := range items {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := notFound[*id]; found {
+				skipped = append(skipped, item)
+				continue
+			}
+		}
+		confirmed = append(confirmed, item)
+	}
+	if len(skipped) > 0 {
+		s.logger.Warn(fmt.Sprintf("trakt couldn't find %d item(s) added to %s", len(skipped), target), s.diffItems(target, skipped))
+		category := appconfig.SyncCategoryLists
+		if target == appconfig.SyncCategoryWatchlist {
+			category = appconfig.SyncCategoryWatchlist
+		}
+		for _, item := range skipped {
+			id, _ := item.GetItemID()
+			s.recordError(category, *id, fmt.Errorf("trakt couldn't find the item added to %s", target))
+		}
+	}
+	return confirmed
+}
+
+// checkRemovals guards against wiping trakt data when the imdb data is incomplete, e.g. due to a failed export, unless the removals are forced or confirmed in the terminal
+// the percentage is relative to the items of the target, hence it doesn't apply to targets of unknown size, e.g. the history, which are guarded by the count only
+func (s *Syncer) checkRemovals(target string, items entities.TraktItems, total int) error {
+	removals := s.conf.Removals
+	count := len(items)
+	if removals.IsForced() || count == 0 {
+		return nil
+	}
+	var thresholdErr *RemovalThresholdError
+	if maxCount := removals.MaxCount; maxCount != nil && *maxCount > 0 && count > *maxCount {
+		thresholdErr = &RemovalThresholdError{
+			Target: target,
+			Count:  count,
+			Total:  total,
+			Limit:  fmt.Sprintf("%d item(s)", *maxCount),
+		}
+	} else if maxPercent := removals.MaxPercent; maxPercent != nil && *maxPercent > 0 && total > 0 && count*100 > *maxPercent*total {
+		thresholdErr = &RemovalThresholdError{
+			Target: target,
+			Count:  count,
+			Total:  total,
+			Limit:  fmt.Sprintf("%d%%", *maxPercent),
+		}
+	}
+	if thresholdErr == nil {
+		return nil
+	}
+	if !removals.IsInteractive() {
+		return thresholdErr
+	}
+	prompt := fmt.Sprintf("%d item(s) are about to be removed from trakt %s, exceeding the removal limit of %s:\n  %s\nremove them? [y/N]: ", count, target, thresholdErr.Limit, strings.Join(items.Labels(), "\n  "))
+	confirmed, err := confirm(os.Stdin, os.Stdout, prompt)
+	if err != nil {
+		return fmt.Errorf("failure confirming trakt %s removals: %w", target, err)
+	}
+	if !confirmed {
+		return thresholdErr
+	}
+	return nil
+}
+
+// checkAdditions fails when the items to be added to a target exceed the addition limits, unless they're forced or confirmed in the terminal
+// the percentage is relative to the items of the target, hence it doesn't apply to empty targets or targets of unknown size, e.g. the history, which are guarded by the count only
+func (s *Syncer) checkAdditions(target string, items entities.TraktItems, total int) error {
+	additions := s.conf.Additions
+	count := len(items)
+	if additions.IsForced() || count == 0 {
+		return nil
+	}
+	var thresholdErr *AdditionThresholdError
+	if maxCount := additions.MaxCount; maxCount != nil && *maxCount > 0 && count > *maxCount {
+		thresholdErr = &AdditionThresholdError{
+			Target: target,
+			Count:  count,
+			Total:  total,
+			Limit:  fmt.Sprintf("%d item(s)", *maxCount),
+		}
+	} else if maxPercent := additions.MaxPercent; maxPercent != nil && *maxPercent > 0 && total > 0 && count*100 > *maxPercent*total {
+		thresholdErr = &AdditionThresholdError{
+			Target: target,
+			Count:  count,
+			Total:  total,
+			Limit:  fmt.Sprintf("%d%%", *maxPercent),
+		}
+	}
+	if thresholdErr == nil {
+		return nil
+	}
+	if !additions.IsInteractive() {
+		return thresholdErr
+	}
+	prompt := fmt.Sprintf("%d item(s) are about to be added to the %d item(s) of trakt %s, exceeding the addition limit of %s:\n  %s\nadd them? [y/N]: ", count, total, target, thresholdErr.Limit, strings.Join(items.Labels(), "\n  "))
+	confirmed, err := confirm(os.Stdin, os.Stdout, prompt)
+	if err != nil {
+		return fmt.Errorf("failure confirming trakt %s additions: %w", target, err)
+	}
+	if !confirmed {
+		return thresholdErr
+	}
+	return nil
+}
+
+// confirmRatingsRemoval guards the irreversible removal of trakt ratings, which requires an explicit opt-in via config or the terminal
+func (s *Syncer) confirmRatingsRemoval(items entities.TraktItems) (bool, error) {
+	removals := s.conf.Removals
+	if removals.RatingsAllowed() {
+		return true, nil
+	}
+	if !removals.IsInteractive() {
+		return false, nil
+	}
+	prompt := fmt.Sprintf("the following %d trakt rating(s) are about to be removed:\n  %s\nremove them? [y/N]: ", len(items), strings.Join(items.Labels(), "\n  "))
+	return confirm(os.Stdin, os.Stdout, prompt)
+}
+
+// confirm writes the prompt and reports whether the answer read afterwards is yes
+func confirm(r io.Reader, w io.Writer, prompt string) (bool, error) {
+	if _, err := fmt.Fprint(w, prompt); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// archiveItems adds the items about to be removed from the trakt list mirroring an imdb list to its archive list, if any, so that nothing is lost
+// the archive list is created when it doesn't exist yet, the items are removed only once they are archived
+func (s *Syncer) archiveItems(ctx context.Context, list entities.IMDbList, items entities.TraktItems) error {
+	slug := s.listsConf.ArchiveFor(list.ListID)
+	if list.IsWatchlist {
+		slug = s.listsConf.WatchlistArchiveSlug()
+	}
+	if slug == "" {
+		return nil
+	}
+	s.invalidateCache(slug)
+	_, err := s.traktClient.ListItemsAdd(ctx, slug, items)
+	var notFoundErr *client.TraktNotFoundError
+	if errors.As(err, &notFoundErr) {
+		if _, err = s.traktClient.ListAdd(ctx, slug, slug, s.listsConf.PrivacyFor(list.ListID), "", ""); err != nil {
+			return fmt.Errorf("failure creating trakt archive list %s: %w", slug, err)
+		}
+		_, err = s.traktClient.ListItemsAdd(ctx, slug, items)
+	}
+	if err != nil {
+		return fmt.Errorf("failure archiving items to trakt list %s: %w", slug, err)
+	}
+	s.logger.Info(fmt.Sprintf("archived %d trakt list item(s) to trakt list %s", len(items), slug))
+	return nil
+}
+
+// removeEmptyLists deletes the trakt lists mirroring empty imdb lists, the items of which have been removed by syncLists
+// only the trakt lists left without items are deleted, hence the items kept by the sync keep their list as well
+func (s *Syncer) removeEmptyLists(ctx context.Context) error {
+	syncMode := s.conf.ModeFor(appconfig.SyncCategoryLists)
+	if syncMode == appconfig.SyncModeAddOnly {
+		return nil
+	}
+	for id, list := range s.user.imdbLists {
+		if s.isPartial(list) || !s.isRemovableWhenEmpty(list) {
+			continue
+		}
+		if _, found := s.user.traktLists[id]; !found {
+			continue
+		}
+		// the trakt list is shared with the sibling imdb lists, hence it's only deleted once all of them are empty
+		if slices.ContainsFunc(s.listSiblings(list), func(sibling entities.IMDbList) bool {
+			return !s.isRemovableWhenEmpty(sibling)
+		}) {
+			continue
+		}
+		traktListSlug := s.traktListSlug(list)
+		if syncMode == appconfig.SyncModeDryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have deleted empty trakt list %s", syncMode, traktListSlug))
+			continue
+		}
+		// the trakt list is fetched again, so that it is only deleted when the sync left it without items
+		traktList, err := s.traktClient.ListGet(ctx, traktListSlug)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt list %s: %w", traktListSlug, err)
+		}
+		if len(traktList.ListItems) != 0 {
+			s.logger.Info(fmt.Sprintf("keeping trakt list %s of empty imdb list %s, it still holds %d item(s)", traktListSlug, id, len(traktList.ListItems)))
+			continue
+		}
+		s.invalidateCache(traktListSlug)
+		if err := s.traktClient.ListRemove(ctx, traktListSlug); err != nil {
+			return fmt.Errorf("failure removing trakt list %s: %w", traktListSlug, err)
+		}
+		delete(s.user.traktLists, id)
+		for _, sibling := range s.listSiblings(list) {
+			delete(s.user.traktLists, sibling.ListID)
+		}
+	}
+	return nil
+}
+
+// isRemovableWhenEmpty reports whether an imdb list lets its trakt list be deleted by removeEmptyLists, which requires it to be empty
+func (s *Syncer) isRemovableWhenEmpty(list entities.IMDbList) bool {
+	if list.IsWatchlist || len(list.ListItems) != 0 || s.listsConf.NoRemoveFor(list.ListID) {
+		return false
+	}
+	// the trakt lists named in the config or routed to by list rules weren't created for the imdb list, hence they are kept
+	// as are the genre lists, which only lack items until an item of their genre is rated
+	_, slug := s.listsConf.TraktListFor(list.ListID)
+	return slug == "" && !appconfig.IsGenreListID(list.ListID)
+}
+
+func (s *Syncer) syncRatings(ctx context.Context) error {
+	diff := s.ratingsDifference()
+	// hidden items already rated on trakt keep following the imdb ratings, only new ratings are suppressed
+	diff["add"] = s.withoutHidden("ratings", diff["add"])
+	for _, change := range []string{"add", "update", "remove"} {
+		diff[change] = s.withoutRejected(reviewTargetRatings, diff[change])
+	}
+	for _, item := range diff["update"] {
+		imdbWins, err := s.resolveRatingConflict(item)
+		if err != nil {
+			return fmt.Errorf("failure resolving rating conflict: %w", err)
+		}
+		if imdbWins {
+			diff["add"] = append(diff["add"], item)
+		}
+	}
+	diff["add"] = s.capItems("ratings", "add", diff["add"])
+	diff["remove"] = s.capItems("ratings", "removal", diff["remove"])
+	progress := newProgress(s.logger, s.clock, "ratings", len(diff["add"])+len(diff["remove"]), s.conf.ProgressInterval)
+	if len(diff["add"]) > 0 {
+		if syncMode := s.conf.ModeFor(appconfig.SyncCategoryRatings); syncMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added %d trakt rating item(s)", syncMode, len(diff["add"]))
+			s.logger.Info(msg, s.diffItems("ratings", diff["add"]))
+			s.result.Ratings.PendingAdd = append(s.result.Ratings.PendingAdd, diff["add"]...)
+		} else {
+			if err := s.checkAdditions("ratings", diff["add"], len(s.user.traktRatings)); err != nil {
+				return err
+			}
+			if err := s.traktClient.RatingsAdd(ctx, diff["add"]); err != nil {
+				return fmt.Errorf("failure adding trakt ratings: %w", err)
+			}
+			s.result.Ratings.Added = append(s.result.Ratings.Added, diff["add"]...)
+		}
+		progress.add(len(diff["add"]))
+		if s.conf.ShouldRatingImplyWatched() {
+			historyProgress := newProgress(s.logger, s.clock, "history", len(diff["add"]), s.conf.ProgressInterval)
+			if err := s.addHistory(ctx, diff["add"], historyProgress); err != nil {
+				return err
+			}
+		}
+		if err := s.removeRatedFromWatchlist(ctx, diff["add"]); err != nil {
+			return err
+		}
+	}
+	if len(diff["remove"]) > 0 {
+		if syncMode := s.conf.ModeFor(appconfig.SyncCategoryRatings); syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt rating item(s)", syncMode, len(diff["remove"]))
+			s.logger.Info(msg, s.diffItems("ratings", diff["remove"]))
+			s.result.Ratings.PendingRemove = append(s.result.Ratings.PendingRemove, diff["remove"]...)
+		} else {
+			if err := s.checkRemovals("ratings", diff["remove"], len(s.user.traktRatings)); err != nil {
+				return err
+			}
+			confirmed, err := s.confirmRatingsRemoval(diff["remove"])
+			if err != nil {
+				return fmt.Errorf("failure confirming trakt ratings removal: %w", err)
+			}
+			if !confirmed {
+				msg := fmt.Sprintf("skipping removal of %d trakt rating item(s), set SYNC_REMOVALS_ALLOWRATINGS to true or confirm with the --%s flag", len(diff["remove"]), flagNameInteractive)
+				s.logger.Warn(msg, slog.Any("ratings", diff["remove"].Labels()))
+				s.result.Ratings.PendingRemove = append(s.result.Ratings.PendingRemove, diff["remove"]...)
+			} else {
+				if err = s.traktClient.RatingsRemove(ctx, diff["remove"]); err != nil {
+					return fmt.Errorf("failure removing trakt ratings: %w", err)
+				}
+				s.result.Ratings.Removed = append(s.result.Ratings.Removed, diff["remove"]...)
+			}
+		}
+		progress.add(len(diff["remove"]))
+	}
+	return s.addPlaceholderRatings(ctx)
+}
+
+// addPlaceholderRatings rates the movies and shows watched on trakt, but rated on neither imdb nor trakt, with the same rating on imdb
+// this is lossy, since the placeholder ratings can't be told apart from real ones afterwards, hence it's only done once enabled
+// the items rated on trakt are left to the ratings sync, which would otherwise replace their trakt ratings with the placeholder
+func (s *Syncer) addPlaceholderRatings(ctx context.Context) error {
+	rating := s.conf.PlaceholderRating()
+	if rating == 0 {
+		return nil
+	}
+	watched, err := s.traktClient.WatchedGet(ctx)
+	if err != nil {
+		return fmt.Errorf("failure fetching trakt watched items: %w", err)
+	}
+	var unrated entities.TraktItems
+	for _, item := range watched {
+		id, err := item.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id == nil || *id == "" {
+			continue
+		}
+		if _, found := s.user.imdbRatings[*id]; found {
+			continue
+		}
+		if _, found := s.user.traktRatings[*id]; found {
+			continue
+		}
+		item.Reason = "watched on trakt, but not rated on imdb"
+		unrated = append(unrated, item)
+	}
+	unrated = s.capItems("imdb ratings", "add", unrated)
+	if len(unrated) == 0 {
+		return nil
+	}
+	if syncMode := s.conf.ModeFor(appconfig.SyncCategoryRatings); syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d imdb rating item(s) with placeholder rating %d", syncMode, len(unrated), rating)
+		s.logger.Info(msg, s.diffItems("imdb ratings", unrated))
+		return nil
+	}
+	items := make([]entities.IMDbItem, 0, len(unrated))
+	for _, item := range unrated {
+		id, _ := item.GetItemID()
+		items = append(items, entities.IMDbItem{
+			ID:     *id,
+			Rating: &rating,
+		})
+	}
+	if err = s.imdbClient.RatingsAdd(ctx, items); err != nil {
+		return fmt.Errorf("failure adding imdb placeholder ratings: %w", err)
+	}
+	s.logger.Info(fmt.Sprintf("added %d imdb rating item(s) with placeholder rating %d, they are synced to trakt by the next run", len(items), rating))
+	return nil
+}
+
+// removeRatedFromWatchlist removes the newly rated items from the trakt watchlist, assuming they were rated because they were watched
+// only items rated at or above the watchlist removal rating, which were on the trakt watchlist before the sync, are removed
+func (s *Syncer) removeRatedFromWatchlist(ctx context.Context, rated entities.TraktItems) error {
+	minRating := s.conf.WatchlistRemovalRating()
+	if minRating == 0 {
+		return nil
+	}
+	var watchlist *entities.TraktList
+	for id, list := range s.user.imdbLists {
+		if list.IsWatchlist {
+			if traktList, found := s.user.traktLists[id]; found {
+				watchlist = &traktList
+			}
+			break
+		}
+	}
+	if watchlist == nil {
+		return nil
+	}
+	ratedIDs := make(map[string]struct{}, len(rated))
+	for i := range rated {
+		id, err := rated[i].GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id == nil || *id == "" {
+			continue
+		}
+		if rating, found := s.user.imdbRatings[*id]; found && rating.Rating != nil && *rating.Rating >= minRating {
+			ratedIDs[*id] = struct{}{}
+		}
+	}
+	// items removed by the watchlist sync are already gone
+	for _, id := range itemIDs(s.result.Watchlist.Removed) {
+		delete(ratedIDs, id)
+	}
+	var items entities.TraktItems
+	for i := range watchlist.ListItems {
+		id, err := watchlist.ListItems[i].GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id == nil || *id == "" {
+			continue
+		}
+		if _, found := ratedIDs[*id]; found {
+			item := watchlist.ListItems[i]
+			item.Reason = fmt.Sprintf("rated on imdb, at or above %d", minRating)
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if syncMode := s.conf.ModeFor(appconfig.SyncCategoryWatchlist); syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+		msg := fmt.Sprintf("sync mode %s would have deleted %d rated item(s) from the trakt watchlist", syncMode, len(items))
+		s.logger.Info(msg, s.diffItems("watchlist", items))
+		s.result.Watchlist.PendingRemove = append(s.result.Watchlist.PendingRemove, items...)
+		return nil
+	}
+	if err := s.checkRemovals("watchlist", items, len(watchlist.ListItems)); err != nil {
+		return err
+	}
+	if err := s.traktClient.WatchlistItemsRemove(ctx, items); err != nil {
+		return fmt.Errorf("failure removing rated items from trakt watchlist: %w", err)
+	}
+	s.logger.Info(fmt.Sprintf("removed %d rated item(s) from the trakt watchlist", len(items)), s.diffItems("watchlist", items))
+	s.result.Watchlist.Removed = append(s.result.Watchlist.Removed, items...)
+	return nil
+}
+
+// ratingsDifference compares the imdb ratings at or above the rating threshold with the trakt ratings
+// trakt ratings of items rated below the threshold on imdb are kept, unless their removal is enabled
+func (s *Syncer) ratingsDifference() map[string]entities.TraktItems {
+	ratedIMDb, ratedTrakt := s.ratingsInDateRange()
+	threshold := s.conf.RatingThreshold()
+	if threshold == 0 {
+		return s.keepUnratedRemovals(entities.ItemsDifference(ratedIMDb, ratedTrakt))
+	}
+	imdbRatings := make(map[string]entities.IMDbItem, len(ratedIMDb))
+	traktRatings := maps.Clone(ratedTrakt)
+	for id, item := range ratedIMDb {
+		if s.meetsRatingThreshold(id, threshold) {
+			imdbRatings[id] = item
+			continue
+		}
+		if !s.conf.ShouldRemoveBelowMinRating() {
+			delete(traktRatings, id)
+		}
+	}
+	diff := entities.ItemsDifference(imdbRatings, traktRatings)
+	for i, item := range diff["remove"] {
+		if id, _ := item.GetItemID(); id != nil {
+			if rating, found := s.user.imdbRatings[*id]; found && rating.Rating != nil {
+				diff["remove"][i].Reason = fmt.Sprintf("rated %d on imdb, below the minimum rating %d", *rating.Rating, threshold)
+			}
+		}
+	}
+	return s.keepUnratedRemovals(diff)
+}
+
+// ratingsInDateRange returns the ratings of the items rated within the date range, an item is dated by its imdb rating when rated on imdb
+// hence an item rated on both services on different days is either added, updated and removed as a whole, or left out as a whole
+func (s *Syncer) ratingsInDateRange() (map[string]entities.IMDbItem, map[string]entities.TraktItem) {
+	if !s.conf.HasDateRange() {
+		return s.user.imdbRatings, s.user.traktRatings
+	}
+	imdbRatings := make(map[string]entities.IMDbItem)
+	traktRatings := make(map[string]entities.TraktItem)
+	for id, item := range s.user.imdbRatings {
+		if item.RatingDate != nil && s.conf.InDateRange(*item.RatingDate) {
+			imdbRatings[id] = item
+			if traktItem, found := s.user.traktRatings[id]; found {
+				traktRatings[id] = traktItem
+			}
+		}
+	}
+	for id, item := range s.user.traktRatings {
+		if _, found := s.user.imdbRatings[id]; found {
+			continue
+		}
+		if ratedAt, err := time.Parse(time.RFC3339, item.RatedAt); err == nil && s.conf.InDateRange(ratedAt) {
+			traktRatings[id] = item
+		}
+	}
+	s.logger.Debug(fmt.Sprintf("syncing %d imdb and %d trakt rating(s) within the date range", len(imdbRatings), len(traktRatings)))
+	return imdbRatings, traktRatings
+}
+
+// keepUnratedRemovals drops the removal of trakt ratings missing on imdb, unless the ratings state has seen them rated on imdb before
+func (s *Syncer) keepUnratedRemovals(diff map[string]entities.TraktItems) map[string]entities.TraktItems {
+	if s.ratings == nil {
+		return diff
+	}
+	var ignored entities.TraktItems
+	diff["remove"] = slices.DeleteFunc(diff["remove"], func(item entities.TraktItem) bool {
+		id, _ := item.GetItemID()
+		if id == nil {
+			return false
+		}
+		if _, found := s.user.imdbRatings[*id]; found || s.ratings.wasRated(*id) {
+			return false
+		}
+		ignored = append(ignored, item)
+		return true
+	})
+	if len(ignored) > 0 {
+		msg := fmt.Sprintf("ignoring removal of %d trakt rating item(s) never seen rated on imdb", len(ignored))
+		if !s.ratings.existed {
+			msg += fmt.Sprintf(", the ratings state %s is recorded by the first successful run", s.ratings.path)
+		}
+		s.logger.Info(msg, s.diffItems("ratings", ignored))
+	}
+	return diff
+}
+
+// meetsRatingThreshold reports whether an item is rated on imdb at or above the threshold, unrated items never meet it
+func (s *Syncer) meetsRatingThreshold(id string, threshold int) bool {
+	rating, found := s.user.imdbRatings[id]
+	return found && rating.Rating != nil && *rating.Rating >= threshold
+}
+
+// resolveRatingConflict reports whether the imdb rating of an item rated differently on both sides should overwrite the trakt one
+func (s *Syncer) resolveRatingConflict(item entities.TraktItem) (bool, error) {
+	id, err := item.GetItemID()
+	if err != nil {
+		return false, fmt.Errorf("failure fetching trakt item id: %w", err)
+	}
+	if id == nil || *id == "" {
+		return false, nil
+	}
+	imdbRating, traktRating := s.user.imdbRatings[*id], s.user.traktRatings[*id]
+	policy := s.conf.RatingsConflictPolicy()
+	imdbWins, err := ratingConflictIMDbWins(policy, imdbRating, traktRating)
+	if err != nil {
+		return false, fmt.Errorf("failure resolving rating conflict of %s: %w", *id, err)
+	}
+	if policy == appconfig.RatingsConflictMostRecent && imdbRating.RatingDate == nil {
+		s.logger.Warn(fmt.Sprintf("keeping the trakt rating of %s, the imdb rating has no date to compare using policy %s", *id, policy))
+	}
+	s.logger.Debug(
+		fmt.Sprintf("resolved rating conflict of %s using policy %s", *id, policy),
+		slog.Any("imdb", imdbRating.Rating),
+		slog.Int("trakt", traktRating.Rating),
+		slog.Bool("imdbWins", imdbWins),
+	)
+	return imdbWins, nil
+}
+
+// ratingConflictIMDbWins applies the conflict policy to an item rated differently on both sides, an imdb rating lacking a value or a date loses
+func ratingConflictIMDbWins(policy string, imdbRating entities.IMDbItem, traktRating entities.TraktItem) (bool, error) {
+	if imdbRating.Rating == nil {
+		return false, nil
+	}
+	switch policy {
+	case appconfig.RatingsConflictIMDbWins:
+		return true, nil
+	case appconfig.RatingsConflictHighest:
+		return *imdbRating.Rating > traktRating.Rating, nil
+	case appconfig.RatingsConflictMostRecent:
+		if imdbRating.RatingDate == nil {
+			return false, nil
+		}
+		traktRatedAt, err := time.Parse(time.RFC3339, traktRating.RatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failure parsing trakt rating date: %w", err)
+		}
+		// imdb only records the day of rating, so trakt wins ratings submitted on the same day
+		return imdbRating.RatingDate.After(traktRatedAt.Truncate(24 * time.Hour)), nil
+	default:
+		return false, nil
+	}
+}
+
+func (s *Syncer) syncHistory(ctx context.Context) error {
+	if *s.conf.SkipHistory {
+		s.logger.Info("skipping history sync")
+		return nil
+	}
+	// imdb doesn't offer functionality similar to trakt history, hence why there can't be a direct mapping between them
+	// the syncer will assume a user to have watched an item if they've submitted a rating for it, or added it to one of the watched lists
+	// if the above is satisfied and the user's history for this item is empty, a new history entry is added!
+	diff := s.ratingsDifference()
+	diff["add"] = append(diff["add"], diff["update"]...)
+	if s.conf.ShouldRatingImplyWatched() {
+		s.logger.Debug("skipping trakt history adds, they were handled by ratings sync")
+		diff["add"] = nil
+	}
+	watched, err := s.watchedListsItems(ctx)
+	if err != nil {
+		return err
+	}
+	diff["add"] = mergeWatched(diff["add"], watched)
+	// items still on a watched list were watched, regardless of their rating
+	diff["remove"] = slices.DeleteFunc(diff["remove"], func(item entities.TraktItem) bool {
+		if id, _ := item.GetItemID(); id != nil {
+			_, found := watched[*id]
+			return found
+		}
+		return false
+	})
+	diff["add"] = s.capItems("history", "add", diff["add"])
+	diff["remove"] = s.capItems("history", "removal", diff["remove"])
+	progress := newProgress(s.logger, s.clock, "history", len(diff["add"])+len(diff["remove"]), s.conf.ProgressInterval)
+	if len(diff["add"]) > 0 {
+		if err := s.addHistory(ctx, diff["add"], progress); err != nil {
+			return err
+		}
+	}
+	if len(diff["remove"]) > 0 && !s.conf.ShouldRemoveHistory() {
+		s.logger.Info(fmt.Sprintf("skipping removal of %d trakt history item(s), history removals are disabled", len(diff["remove"])))
+		progress.add(len(diff["remove"]))
+		diff["remove"] = nil
+	}
+	if len(diff["remove"]) > 0 {
+		var historyToRemove entities.TraktItems
+		for i := range diff["remove"] {
+			traktItemID, err := diff["remove"][i].GetItemID()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt item id: %w", err)
+			}
+			if s.isProcessed(checkpointHistoryRemove, *traktItemID) {
+				progress.add(1)
+				continue
+			}
+			history, err := s.traktClient.HistoryGet(ctx, diff["remove"][i].Type, *traktItemID)
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt history for %s %s: %w", diff["remove"][i].Type, *traktItemID, err)
+			}
+			progress.add(1)
+			if len(history) == 0 {
+				s.logger.Debug(fmt.Sprintf("skipping trakt history removal for %s %s, it has no history", diff["remove"][i].Type, *traktItemID))
+				s.markProcessed(checkpointHistoryRemove, *traktItemID)
+				continue
+			}
+			historyToRemove = append(historyToRemove, diff["remove"][i])
+		}
+		if len(historyToRemove) > 0 {
+			if syncMode := s.conf.ModeFor(appconfig.SyncCategoryHistory); syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
+				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt history item(s)", syncMode, len(historyToRemove))
+				s.logger.Info(msg, s.diffItems("history", historyToRemove))
+				s.result.History.PendingRemove = append(s.result.History.PendingRemove, historyToRemove...)
+			} else {
+				// the size of the trakt history isn't fetched, hence only the count limit applies to it
+				if err := s.checkRemovals("history", historyToRemove, 0); err != nil {
+					return err
+				}
+				if err := s.traktClient.HistoryRemove(ctx, historyToRemove); err != nil {
+					return fmt.Errorf("failure removing trakt history: %w", err)
+				}
+				s.result.History.Removed = append(s.result.History.Removed, historyToRemove...)
+				s.markProcessed(checkpointHistoryRemove, itemIDs(historyToRemove)...)
+			}
+		}
+	}
+	return nil
+}
+
+// addWatchlistRemovalsToHistory assumes the movies removed from the imdb watchlist to have been watched, adding them to trakt history
+// shows are skipped, since removing a show from the watchlist doesn't tell which of its episodes were watched
+func (s *Syncer) addWatchlistRemovalsToHistory(ctx context.Context, watchlist entities.IMDbList, removed entities.TraktItems) error {
+	if len(watchlist.ListItems) == 0 {
+		s.logger.Warn("skipping trakt history adds for watchlist removals, the imdb watchlist is empty, which usually means the export is incomplete")
+		return nil
+	}
+	var movies entities.TraktItems
+	for _, item := range removed {
+		if item.Type != entities.TraktItemTypeMovie {
+			s.logger.Debug(fmt.Sprintf("skipping trakt history add for watchlist removal of %s %s, only movies are considered watched", item.Type, item.Label()))
+			continue
+		}
+		movies = append(movies, item)
+	}
+	if len(movies) == 0 {
+		return nil
+	}
+	progress := newProgress(s.logger, s.clock, "watchlist history", len(movies), s.conf.ProgressInterval)
+	if err := s.addHistory(ctx, movies, progress); err != nil {
+		return fmt.Errorf("failure adding watchlist removals to trakt history: %w", err)
+	}
+	return nil
+}
+
+// uniqueHistory drops the items that would be added to trakt history more than once in the same run
+// duplicates can be part of the items themselves, or have been added by an earlier category, e.g. by ratings sync before check-ins sync
+func (s *Syncer) uniqueHistory(items entities.TraktItems) entities.TraktItems {
+	added := append(slices.Clone(s.result.History.Added), s.result.History.PendingAdd...)
+	unique, duplicates := items.RemoveDuplicates(added)
+	if duplicates > 0 {
+		s.logger.Debug(fmt.Sprintf("skipping %d duplicate trakt history item(s)", duplicates))
+	}
+	return unique
+}
+
+// capItems limits the items of a change to SYNC_MAXITEMS, ordered by id so that repeated runs pick the same items
+func (s *Syncer) capItems(target, change string, items entities.TraktItems) entities.TraktItems {
+	maxItems := s.conf.MaxItemsPerChange()
+	if maxItems == 0 || len(items) <= maxItems {
+		return items
+	}
+	capped := slices.Clone(items)
+	slices.SortStableFunc(capped, func(a, b entities.TraktItem) int {
+		idA, _ := a.GetItemID()
+		idB, _ := b.GetItemID()
+		if idA == nil || idB == nil {
+			return 0
+		}
+		return strings.Compare(*idA, *idB)
+	})
+	s.logger.Info(fmt.Sprintf("processing %d of %d %s %s item(s), limited by SYNC_MAXITEMS", maxItems, len(items), target, change))
+	return capped[:maxItems]
+}
+
+// watchedListsItems returns the items of the watched lists by id, watched on the date they were added to the list
+// the lists synced to trakt or skipped as unchanged are reused from hydration, the others are fetched from imdb
+func (s *Syncer) watchedListsItems(ctx context.Context) (map[string]entities.TraktItem, error) {
+	watched := make(map[string]entities.TraktItem)
+	for _, listID := range s.conf.WatchedLists {
+		list, found := s.user.imdbLists[listID]
+		if !found {
+			list, found = s.unchangedLists[listID]
+		}
+		if !found || list.ListItems == nil {
+			fetched, err := s.imdbClient.ListGet(ctx, listID)
+			if err != nil {
+				return nil, fmt.Errorf("failure fetching imdb watched list %s: %w", listID, err)
+			}
+			list = *fetched
+		}
+		for _, item := range list.ListItems {
+			if _, found = watched[item.ID]; found {
+				continue
+			}
+			// the items are dated by when they were added to the list, undated items are left out of a date range
+			if s.conf.HasDateRange() && (item.Created == nil || !s.conf.InDateRange(*item.Created)) {
+				continue
+			}
+			traktItem := item.ToTraktItem()
+			if item.Created != nil {
+				traktItem.SetWatchedAt(*item.Created)
+			}
+			traktItem.Reason = fmt.Sprintf("on imdb watched list %s", list.ListName)
+			watched[item.ID] = traktItem
+		}
+	}
+	return watched, nil
+}
+
+// mergeWatched appends the watched items to the rated ones, an item both rated and on a watched list is kept once, as rated
+func mergeWatched(rated entities.TraktItems, watched map[string]entities.TraktItem) entities.TraktItems {
+	if len(watched) == 0 {
+		return rated
+	}
+	merged := slices.Clone(rated)
+	seen := make(map[string]struct{}, len(rated))
+	for _, id := range itemIDs(rated) {
+		seen[id] = struct{}{}
+	}
+	ids := make([]string, 0, len(watched))
+	for id := range watched {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if _, found := seen[id]; !found {
+			merged = append(merged, watched[id])
+		}
+	}
+	return merged
+}
+
+// addHistory adds the items which don't have any trakt history yet to trakt history
+func (s *Syncer) addHistory(ctx context.Context, items entities.TraktItems, progress *progress) error {
+	var historyToAdd entities.TraktItems
+	for i := range items {
+		traktItemID, err := items[i].GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if s.isProcessed(checkpointHistoryAdd, *traktItemID) {
+			progress.add(1)
+			continue
+		}
+		history, err := s.traktClient.HistoryGet(ctx, items[i].Type, *traktItemID)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt history for %s %s: %w", items[i].Type, *traktItemID, err)
+		}
+		progress.add(1)
+		if len(history) > 0 {
+			s.logger.Debug(fmt.Sprintf("skipping trakt history add for %s %s, it already has history", items[i].Type, *traktItemID))
+			s.markProcessed(checkpointHistoryAdd, *traktItemID)
+			continue
+		}
+		historyToAdd = append(historyToAdd, items[i])
+	}
+	historyToAdd = s.uniqueHistory(historyToAdd)
+	if len(historyToAdd) == 0 {
+		return nil
+	}
+	if syncMode := s.conf.ModeFor(appconfig.SyncCategoryHistory); syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d trakt history item(s)", syncMode, len(historyToAdd))
+		s.logger.Info(msg, s.diffItems("history", historyToAdd))
+		s.result.History.PendingAdd = append(s.result.History.PendingAdd, historyToAdd...)
+		return nil
+	}
+	// the size of the trakt history isn't fetched, hence only the count limit applies to it
+	if err := s.checkAdditions("history", historyToAdd, 0); err != nil {
+		return err
+	}
+	return s.addHistoryBatches(ctx, "history", checkpointHistoryAdd, historyToAdd, itemIDs)
+}
+
+// addHistoryBatches adds the history in batches of the configured size, recording the keys of every added batch in the checkpoint step
+func (s *Syncer) addHistoryBatches(ctx context.Context, target, step string, items entities.TraktItems, keys func(entities.TraktItems) []string) error {
+	// the batches are added in the order the items were watched, so that the trakt activity feed follows the watched dates
+	batches := items.ChronologicalBatches(s.conf.HistoryBatchSizeOrDefault())
+	for i, batch := range batches {
+		if len(batches) > 1 {
+			s.logger.Debug(fmt.Sprintf("adding trakt %s batch %d/%d of %d item(s)", target, i+1, len(batches), len(batch)))
+		}
+		if err := s.traktClient.HistoryAdd(ctx, batch); err != nil {
+			return fmt.Errorf("failure adding trakt %s: %w", target, err)
+		}
+		s.result.History.Added = append(s.result.History.Added, batch...)
+		s.markProcessed(step, keys(batch)...)
+	}
+	return nil
+}
+
+// itemIDs returns the ids of the items, skipping the ones without an id
+func itemIDs(items entities.TraktItems) []string {
+	ids := make([]string, 0, len(items))
+	for i := range items {
+		if id, _ := items[i].GetItemID(); id != nil && *id != "" {
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+func (s *Syncer) syncCheckIns(ctx context.Context) error {
+	if !s.conf.CheckIns.IsEnabled() {
+		return nil
+	}
+	list, err := s.imdbClient.ListGet(ctx, *s.conf.CheckIns.List)
+	if err != nil {
+		return fmt.Errorf("failure fetching imdb check-ins list %s: %w", *s.conf.CheckIns.List, err)
+	}
+	// an item checked in multiple times was rewatched, each check-in becomes a separate play
+	var (
+		checkIns = make(map[string][]time.Time)
+		items    []entities.IMDbItem
+	)
+	for _, item := range list.ListItems {
+		// the date an item was added to the check-ins list is the date it was watched
+		// fall back to the rating date when the export doesn't provide one
+		watchedAt := item.Created
+		if watchedAt == nil {
+			if rating, found := s.user.imdbRatings[item.ID]; found {
+				watchedAt = rating.RatingDate
+			}
+		}
+		if watchedAt == nil {
+			s.logger.Warn("skipping check-in without a watched date", slog.String("id", item.ID))
+			continue
+		}
+		if _, found := checkIns[item.ID]; !found {
+			items = append(items, item)
+		}
+		checkIns[item.ID] = append(checkIns[item.ID], *watchedAt)
+	}
+	var historyToAdd entities.TraktItems
+	for _, item := range items {
+		traktItem := item.ToTraktItem()
+		traktItemID, err := traktItem.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		history, err := s.traktClient.HistoryGet(ctx, traktItem.Type, *traktItemID)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt history for %s %s: %w", traktItem.Type, *traktItemID, err)
+		}
+		// scrobbles are watched at the time of the sync, hence they can't be matched with the check-in dates
+		if s.conf.CheckIns.ShouldScrobble() {
+			if len(history) > 0 {
+				s.logger.Debug(fmt.Sprintf("skipping check-in for %s %s, it already has history", traktItem.Type, *traktItemID))
+				continue
+			}
+			play := traktItem
+			play.SetWatchedAt(checkIns[item.ID][0])
+			if !s.isProcessed(checkpointCheckInsAdd, checkInKey(play)) {
+				historyToAdd = append(historyToAdd, play)
+			}
+			continue
+		}
+		plays := make(map[int64]bool, len(history))
+		for _, play := range history {
+			if watchedAt, err := time.Parse(time.RFC3339, play.WatchedAt); err == nil {
+				plays[watchedAt.Unix()] = true
+			}
+		}
+		for _, watchedAt := range checkIns[item.ID] {
+			// a play watched at the same time already exists, either in trakt history or earlier in the check-ins list
+			if plays[watchedAt.Unix()] {
+				s.logger.Debug(fmt.Sprintf("skipping check-in for %s %s, a play watched at %s already exists", traktItem.Type, *traktItemID, watchedAt.UTC().Format(time.DateTime)))
+				continue
+			}
+			plays[watchedAt.Unix()] = true
+			play := traktItem
+			play.SetWatchedAt(watchedAt)
+			if s.isProcessed(checkpointCheckInsAdd, checkInKey(play)) {
+				continue
+			}
+			historyToAdd = append(historyToAdd, play)
+		}
+	}
+	historyToAdd = s.capItems("check-ins", "add", s.uniqueHistory(historyToAdd))
+	if len(historyToAdd) == 0 {
+		return nil
+	}
+	if syncMode := s.conf.ModeFor(appconfig.SyncCategoryCheckIns); syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d trakt check-in history item(s)", syncMode, len(historyToAdd))
+		s.logger.Info(msg, s.diffItems("history", historyToAdd))
+		s.result.History.PendingAdd = append(s.result.History.PendingAdd, historyToAdd...)
+		return nil
+	}
+	if err = s.checkAdditions("check-in history", historyToAdd, 0); err != nil {
+		return err
+	}
+	if s.conf.CheckIns.ShouldScrobble() {
+		if historyToAdd, err = s.scrobbleCheckIns(ctx, historyToAdd); err != nil {
+			return err
+		}
+	}
+	return s.addHistoryBatches(ctx, "check-in history", checkpointCheckInsAdd, historyToAdd, checkInKeys)
+}
+
+// checkInKey identifies a check-in play in the checkpoint, since an item checked in multiple times has a play per check-in
+func checkInKey(play entities.TraktItem) string {
+	id, _ := play.GetItemID()
+	watchedAt, dated := play.WatchedAtTime()
+	if id == nil || *id == "" || !dated {
+		return ""
+	}
+	return *id + "@" + strconv.FormatInt(watchedAt.Unix(), 10)
+}
+
+func checkInKeys(plays entities.TraktItems) []string {
+	keys := make([]string, 0, len(plays))
+	for i := range plays {
+		if key := checkInKey(plays[i]); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// scrobbleCheckIns scrobbles the movies and episodes, returning the remaining items which trakt can't scrobble
+func (s *Syncer) scrobbleCheckIns(ctx context.Context, items entities.TraktItems) (entities.TraktItems, error) {
+	var remaining entities.TraktItems
+	for _, item := range items {
+		if item.Type != entities.TraktItemTypeMovie && item.Type != entities.TraktItemTypeEpisode {
+			remaining = append(remaining, item)
+			continue
+		}
+		if err := s.traktClient.Scrobble(ctx, item); err != nil {
+			return nil, fmt.Errorf("failure scrobbling trakt check-in: %w", err)
+		}
+		s.result.History.Added = append(s.result.History.Added, item)
+		s.markProcessed(checkpointCheckInsAdd, checkInKey(item))
+	}
+	return remaining, nil
+}
+
+func (s *Syncer) syncComments(ctx context.Context) error {
+	if !s.conf.Comments.IsEnabled() {
+		s.logger.Info("skipping comments sync")
+		return nil
+	}
+	// the descriptions of the items in the configured imdb list are used, followed by the reviews of the ratings when enabled
+	// items that already have a trakt comment from the user are skipped, so that comments are not posted on every run
+	var items []entities.IMDbItem
+	if s.conf.Comments.HasList() {
+		list, err := s.imdbClient.ListGet(ctx, *s.conf.Comments.List)
+		if err != nil {
+			return fmt.Errorf("failure fetching imdb comments list %s: %w", *s.conf.Comments.List, err)
+		}
+		items = append(items, list.ListItems...)
+	}
+	if s.conf.Comments.ShouldSyncReviews() {
+		items = append(items, s.reviewedItems()...)
+	}
+	traktComments, err := s.traktClient.CommentsGet(ctx)
+	if err != nil {
+		return fmt.Errorf("failure fetching trakt comments: %w", err)
+	}
+	commented := make(map[string]struct{}, len(traktComments))
+	for i := range traktComments {
+		id, err := traktComments[i].GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		commented[*id] = struct{}{}
+	}
+	var processed int
+	for _, item := range items {
+		comment := strings.TrimSpace(item.Description)
+		if comment == "" {
+			continue
+		}
+		if maxItems := s.conf.MaxItemsPerChange(); maxItems > 0 && processed == maxItems {
+			s.logger.Info(fmt.Sprintf("skipping the remaining trakt comments, limited to %d by SYNC_MAXITEMS", maxItems))
+			break
+		}
+		if _, found := commented[item.ID]; found {
+			s.logger.Debug(fmt.Sprintf("skipping comment for %s, it has already been commented on", item.ID))
+			continue
+		}
+		if words := len(strings.Fields(comment)); words < traktCommentMinWords {
+			s.logger.Warn(fmt.Sprintf("skipping comment for %s, trakt requires at least %d words, got %d", item.ID, traktCommentMinWords, words))
+			continue
+		}
+		spoiler := strings.Contains(strings.ToLower(comment), "[spoiler]")
+		if s.conf.Comments.Spoiler != nil && *s.conf.Comments.Spoiler {
+			spoiler = true
+		}
+		processed++
+		// an item with both a description and a review is only commented on once
+		commented[item.ID] = struct{}{}
+		if syncMode := s.conf.ModeFor(appconfig.SyncCategoryComments); syncMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added trakt comment for %s", syncMode, item.ID)
+			s.logger.Info(msg, slog.String("comment", comment), slog.Bool("spoiler", spoiler))
+			s.result.Comments.PendingAdd = append(s.result.Comments.PendingAdd, item.ToTraktItem())
+			continue
+		}
+		traktItem := item.ToTraktItem()
+		if err = s.traktClient.CommentAdd(ctx, traktItem, comment, spoiler); err != nil {
+			return fmt.Errorf("failure adding trakt comment for %s: %w", item.ID, err)
+		}
+		s.result.Comments.Added = append(s.result.Comments.Added, traktItem)
+	}
+	return nil
+}
+
+// reviewedItems returns the rated items with a review, the review taking the place of the description which is posted as comment
+// the ratings export only includes the reviews in some layouts, hence it's logged when none of the ratings has one
+func (s *Syncer) reviewedItems() []entities.IMDbItem {
+	var reviewed []entities.IMDbItem
+	for _, item := range s.user.imdbRatings {
+		if strings.TrimSpace(item.Review) == "" {
+			continue
+		}
+		item.Description = item.Review
+		reviewed = append(reviewed, item)
+	}
+	if len(reviewed) == 0 {
+		s.logger.Info("skipping reviews, none of the imdb ratings has a review, the ratings export might not include them")
+		return nil
+	}
+	slices.SortFunc(reviewed, func(a, b entities.IMDbItem) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return reviewed
 }
@@ -0,0 +1,853 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+// fakeTraktClient records the changes and serves the lists and ratings, any other call panics on the nil embedded client
+type fakeTraktClient struct {
+	client.TraktClientInterface
+	historyBatches []entities.TraktItems
+	lists          map[string]entities.TraktList
+	createdSlugs   map[string]string
+	removedItems   map[string]entities.TraktItems
+	addedItems     map[string]entities.TraktItems
+	ratings        entities.TraktItems
+	removedRatings entities.TraktItems
+	removedLists   []string
+}
+
+func (f *fakeTraktClient) ListGet(_ context.Context, listID string) (*entities.TraktList, error) {
+	list, found := f.lists[listID]
+	if !found {
+		return nil, &client.TraktListNotFoundError{Slug: listID}
+	}
+	return &list, nil
+}
+
+func (f *fakeTraktClient) ListAdd(_ context.Context, listID, listName, _, _, _ string) (*entities.TraktList, error) {
+	return &entities.TraktList{
+		Name:   &listName,
+		IDMeta: entities.TraktIDMeta{Slug: f.createdSlugs[listID]},
+	}, nil
+}
+
+func (f *fakeTraktClient) ListItemsAdd(_ context.Context, listID string, items entities.TraktItems) (*entities.TraktResponse, error) {
+	if f.addedItems == nil {
+		f.addedItems = make(map[string]entities.TraktItems)
+	}
+	f.addedItems[listID] = append(f.addedItems[listID], items...)
+	return nil, nil
+}
+
+func (f *fakeTraktClient) ListItemsRemove(_ context.Context, listID string, items entities.TraktItems) error {
+	if f.removedItems == nil {
+		f.removedItems = make(map[string]entities.TraktItems)
+	}
+	f.removedItems[listID] = append(f.removedItems[listID], items...)
+	return nil
+}
+
+func (f *fakeTraktClient) ListRemove(_ context.Context, listID string) error {
+	f.removedLists = append(f.removedLists, listID)
+	return nil
+}
+
+func (f *fakeTraktClient) RatingsGet(_ context.Context) (entities.TraktItems, error) {
+	return f.ratings, nil
+}
+
+func (f *fakeTraktClient) RatingsAdd(_ context.Context, _ entities.TraktItems) error {
+	return nil
+}
+
+func (f *fakeTraktClient) RatingsRemove(_ context.Context, items entities.TraktItems) error {
+	f.removedRatings = append(f.removedRatings, items...)
+	return nil
+}
+
+func (f *fakeTraktClient) HistoryAdd(_ context.Context, items entities.TraktItems) error {
+	f.historyBatches = append(f.historyBatches, items)
+	return nil
+}
+
+func Test_syncError(t *testing.T) {
+	type args struct {
+		synced int
+		errs   []error
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "nothing synced",
+			args: args{
+				errs: []error{errors.New("failure syncing lists")},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var partialSyncError *PartialSyncError
+				assertions.False(errors.As(err, &partialSyncError))
+				assertions.ErrorContains(err, "failure syncing lists")
+			},
+		},
+		{
+			name: "some categories synced",
+			args: args{
+				synced: 1,
+				errs:   []error{errors.New("failure syncing ratings")},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var partialSyncError *PartialSyncError
+				assertions.True(errors.As(err, &partialSyncError))
+				assertions.ErrorContains(err, "failure syncing ratings")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), syncError(tt.args.synced, tt.args.errs))
+		})
+	}
+}
+
+func Test_ratingConflictIMDbWins(t *testing.T) {
+	type args struct {
+		policy      string
+		imdbRating  entities.IMDbItem
+		traktRating entities.TraktItem
+	}
+	rating := func(value int) *int {
+		return &value
+	}
+	ratedOn := func(year int, month time.Month, day int) *time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	traktRating := entities.TraktItem{Rating: 6, RatedAt: "2024-05-01T20:15:00.000Z"}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, bool, error)
+	}{
+		{
+			name: "imdb wins",
+			args: args{
+				policy:      appconfig.RatingsConflictIMDbWins,
+				imdbRating:  entities.IMDbItem{Rating: rating(4)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.True(imdbWins)
+			},
+		},
+		{
+			name: "trakt wins",
+			args: args{
+				policy:      appconfig.RatingsConflictTraktWins,
+				imdbRating:  entities.IMDbItem{Rating: rating(8)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "highest rating wins",
+			args: args{
+				policy:      appconfig.RatingsConflictHighest,
+				imdbRating:  entities.IMDbItem{Rating: rating(8)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.True(imdbWins)
+			},
+		},
+		{
+			name: "lower imdb rating loses",
+			args: args{
+				policy:      appconfig.RatingsConflictHighest,
+				imdbRating:  entities.IMDbItem{Rating: rating(4)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "more recent imdb rating wins",
+			args: args{
+				policy:      appconfig.RatingsConflictMostRecent,
+				imdbRating:  entities.IMDbItem{Rating: rating(4), RatingDate: ratedOn(2024, time.May, 2)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.True(imdbWins)
+			},
+		},
+		{
+			name: "trakt wins ratings submitted on the same day",
+			args: args{
+				policy:      appconfig.RatingsConflictMostRecent,
+				imdbRating:  entities.IMDbItem{Rating: rating(4), RatingDate: ratedOn(2024, time.May, 1)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "undated imdb rating loses",
+			args: args{
+				policy:      appconfig.RatingsConflictMostRecent,
+				imdbRating:  entities.IMDbItem{Rating: rating(4)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "handle invalid trakt rating date",
+			args: args{
+				policy:      appconfig.RatingsConflictMostRecent,
+				imdbRating:  entities.IMDbItem{Rating: rating(4), RatingDate: ratedOn(2024, time.May, 2)},
+				traktRating: entities.TraktItem{Rating: 6, RatedAt: "yesterday"},
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.ErrorContains(err, "failure parsing trakt rating date")
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "skip conflicting ratings",
+			args: args{
+				policy:      appconfig.RatingsConflictSkip,
+				imdbRating:  entities.IMDbItem{Rating: rating(8)},
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+		{
+			name: "imdb rating without a value loses",
+			args: args{
+				policy:      appconfig.RatingsConflictIMDbWins,
+				traktRating: traktRating,
+			},
+			assertions: func(assertions *assert.Assertions, imdbWins bool, err error) {
+				assertions.NoError(err)
+				assertions.False(imdbWins)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imdbWins, err := ratingConflictIMDbWins(tt.args.policy, tt.args.imdbRating, tt.args.traktRating)
+			tt.assertions(assert.New(t), imdbWins, err)
+		})
+	}
+}
+
+func TestSyncer_checkRemovals(t *testing.T) {
+	type args struct {
+		maxCount   int
+		maxPercent int
+		count      int
+		total      int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "within limits",
+			args: args{
+				maxCount:   5,
+				maxPercent: 50,
+				count:      5,
+				total:      10,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "exceeding max count",
+			args: args{
+				maxCount: 5,
+				count:    6,
+				total:    100,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var thresholdErr *RemovalThresholdError
+				assertions.True(errors.As(err, &thresholdErr))
+				assertions.Equal("5 item(s)", thresholdErr.Limit)
+			},
+		},
+		{
+			name: "exceeding max percent",
+			args: args{
+				maxPercent: 50,
+				count:      6,
+				total:      10,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var thresholdErr *RemovalThresholdError
+				assertions.True(errors.As(err, &thresholdErr))
+				assertions.Equal("50%", thresholdErr.Limit)
+			},
+		},
+		{
+			name: "max percent ignored for unknown total",
+			args: args{
+				maxPercent: 50,
+				count:      6,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "max count applied for unknown total",
+			args: args{
+				maxCount: 5,
+				count:    6,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "refusing to remove 6 item(s) from trakt history")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Syncer{
+				conf: appconfig.Sync{
+					Removals: appconfig.Removals{
+						MaxCount:   &tt.args.maxCount,
+						MaxPercent: &tt.args.maxPercent,
+					},
+				},
+			}
+			tt.assertions(assert.New(t), s.checkRemovals("history", make(entities.TraktItems, tt.args.count), tt.args.total))
+		})
+	}
+}
+
+func TestSyncer_unhandledRemovals(t *testing.T) {
+	dunkirk := entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}},
+	}
+	season := entities.TraktItem{
+		Type: entities.TraktItemTypeSeason,
+	}
+	handled := map[string]map[string]struct{}{}
+	s := &Syncer{}
+	assertions := assert.New(t)
+	unhandled, err := s.unhandledRemovals(handled, "watched", entities.TraktItems{dunkirk, season})
+	assertions.NoError(err)
+	assertions.Equal(entities.TraktItems{dunkirk}, unhandled)
+	unhandled, err = s.unhandledRemovals(handled, "watched", entities.TraktItems{dunkirk})
+	assertions.NoError(err)
+	assertions.Empty(unhandled)
+}
+
+func TestSyncer_removeRatedFromWatchlist(t *testing.T) {
+	dunkirk := entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}},
+	}
+	season := entities.TraktItem{
+		Type: entities.TraktItemTypeSeason,
+	}
+	mode := appconfig.SyncModeDryRun
+	minRating := 8
+	rating := 9
+	s := &Syncer{
+		logger: logger.NewLogger(io.Discard),
+		conf: appconfig.Sync{
+			Mode:                      &mode,
+			WatchlistRemovalMinRating: &minRating,
+		},
+		user: &user{
+			imdbLists: map[string]entities.IMDbList{
+				"watchlist": {IsWatchlist: true},
+			},
+			imdbRatings: map[string]entities.IMDbItem{
+				"tt5013056": {ID: "tt5013056", Rating: &rating},
+			},
+			traktLists: map[string]entities.TraktList{
+				"watchlist": {ListItems: entities.TraktItems{season, dunkirk}},
+			},
+		},
+	}
+	assertions := assert.New(t)
+	err := s.removeRatedFromWatchlist(context.Background(), entities.TraktItems{season, dunkirk})
+	assertions.NoError(err)
+	assertions.Len(s.result.Watchlist.PendingRemove, 1)
+	assertions.Equal(dunkirk.Movie, s.result.Watchlist.PendingRemove[0].Movie)
+}
+
+func TestSyncer_pruneLists(t *testing.T) {
+	dunkirk := entities.IMDbItem{ID: "tt5013056", TitleType: "Movie"}
+	interstellar := entities.IMDbItem{ID: "tt0816692", TitleType: "Movie"}
+	inception := entities.IMDbItem{ID: "tt1375666", TitleType: "Movie"}
+	tests := []struct {
+		name       string
+		mode       string
+		maxCount   int
+		sibling    []entities.IMDbItem
+		partial    bool
+		assertions func(*assert.Assertions, []PrunedList, error, *fakeTraktClient)
+	}{
+		{
+			name: "remove items missing from the imdb list",
+			mode: appconfig.SyncModeFull,
+			assertions: func(assertions *assert.Assertions, pruned []PrunedList, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(pruned, 1)
+				assertions.False(pruned[0].DryRun)
+				assertions.Len(traktClient.removedItems["watched"], 2)
+			},
+		},
+		{
+			name: "report items in dry-run mode",
+			mode: appconfig.SyncModeDryRun,
+			assertions: func(assertions *assert.Assertions, pruned []PrunedList, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(pruned, 1)
+				assertions.True(pruned[0].DryRun)
+				assertions.Len(pruned[0].Items, 2)
+				assertions.Empty(traktClient.removedItems)
+			},
+		},
+		{
+			name:     "fail when the removals exceed the limits",
+			mode:     appconfig.SyncModeFull,
+			maxCount: 1,
+			assertions: func(assertions *assert.Assertions, pruned []PrunedList, err error, traktClient *fakeTraktClient) {
+				var thresholdErr *RemovalThresholdError
+				assertions.True(errors.As(err, &thresholdErr))
+				assertions.Empty(pruned)
+				assertions.Empty(traktClient.removedItems)
+			},
+		},
+		{
+			name:    "keep items of sibling imdb lists",
+			mode:    appconfig.SyncModeFull,
+			sibling: []entities.IMDbItem{interstellar},
+			assertions: func(assertions *assert.Assertions, pruned []PrunedList, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(pruned, 1)
+				assertions.Len(traktClient.removedItems["watched"], 1)
+				assertions.Equal(inception.ID, traktClient.removedItems["watched"][0].Movie.IDMeta.IMDb)
+			},
+		},
+		{
+			name:    "skip partial imdb lists",
+			mode:    appconfig.SyncModeFull,
+			partial: true,
+			assertions: func(assertions *assert.Assertions, pruned []PrunedList, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Empty(pruned)
+				assertions.Empty(traktClient.removedItems)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traktClient := &fakeTraktClient{}
+			s := &Syncer{
+				logger:      logger.NewLogger(io.Discard),
+				traktClient: traktClient,
+				conf: appconfig.Sync{
+					Mode:     &tt.mode,
+					Removals: appconfig.Removals{MaxCount: &tt.maxCount},
+				},
+				user: &user{
+					imdbLists: map[string]entities.IMDbList{
+						"ls000000001": {ListID: "ls000000001", ListName: "Watched", ListItems: []entities.IMDbItem{dunkirk}, Partial: tt.partial},
+					},
+					traktLists: map[string]entities.TraktList{
+						"ls000000001": {IDMeta: entities.TraktIDMeta{Slug: "watched"}, ListItems: entities.TraktItems{dunkirk.ToTraktItem(), interstellar.ToTraktItem(), inception.ToTraktItem()}},
+					},
+				},
+			}
+			if tt.sibling != nil {
+				s.user.imdbLists["ls000000002"] = entities.IMDbList{ListID: "ls000000002", ListName: "Watched", ListItems: tt.sibling}
+			}
+			pruned, err := s.pruneLists(context.Background(), []string{"ls000000001"})
+			tt.assertions(assert.New(t), pruned, err, traktClient)
+		})
+	}
+}
+
+func TestSyncer_Restore(t *testing.T) {
+	movie := func(id string, rating int) entities.TraktItem {
+		return entities.TraktItem{
+			Type:   entities.TraktItemTypeMovie,
+			Rating: rating,
+			Movie:  entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: id}},
+		}
+	}
+	dunkirk, interstellar, inception := movie("tt5013056", 0), movie("tt0816692", 0), movie("tt1375666", 0)
+	snapshot := &TraktSnapshot{
+		Lists: []entities.TraktList{
+			{IDMeta: entities.TraktIDMeta{Slug: "watched"}, ListItems: entities.TraktItems{dunkirk, inception}},
+		},
+		Ratings: entities.TraktItems{movie("tt5013056", 8)},
+	}
+	tests := []struct {
+		name         string
+		mode         string
+		maxPercent   int
+		allowRatings bool
+		assertions   func(*assert.Assertions, []RestoredTarget, error, *fakeTraktClient)
+	}{
+		{
+			name: "report changes in dry-run mode",
+			mode: appconfig.SyncModeDryRun,
+			assertions: func(assertions *assert.Assertions, restored []RestoredTarget, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(restored, 2)
+				for _, target := range restored {
+					assertions.True(target.DryRun)
+				}
+				assertions.Equal(entities.TraktItems{inception}, restored[0].Added)
+				assertions.Equal(entities.TraktItems{interstellar}, restored[0].Removed)
+				assertions.Empty(traktClient.addedItems)
+				assertions.Empty(traktClient.removedItems)
+				assertions.Empty(traktClient.removedRatings)
+			},
+		},
+		{
+			name: "skip removals in add-only mode",
+			mode: appconfig.SyncModeAddOnly,
+			assertions: func(assertions *assert.Assertions, restored []RestoredTarget, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(restored, 1)
+				assertions.Equal(entities.TraktItems{inception}, traktClient.addedItems["watched"])
+				assertions.Empty(traktClient.removedItems)
+				assertions.Empty(traktClient.removedRatings)
+			},
+		},
+		{
+			name:       "fail when the removals exceed the limits",
+			mode:       appconfig.SyncModeFull,
+			maxPercent: 10,
+			assertions: func(assertions *assert.Assertions, restored []RestoredTarget, err error, traktClient *fakeTraktClient) {
+				var thresholdErr *RemovalThresholdError
+				assertions.True(errors.As(err, &thresholdErr))
+				assertions.Equal("list watched", thresholdErr.Target)
+				assertions.Empty(traktClient.removedItems)
+			},
+		},
+		{
+			name: "keep ratings unless their removal is allowed",
+			mode: appconfig.SyncModeFull,
+			assertions: func(assertions *assert.Assertions, restored []RestoredTarget, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Equal(entities.TraktItems{interstellar}, traktClient.removedItems["watched"])
+				assertions.Empty(traktClient.removedRatings)
+			},
+		},
+		{
+			name:         "remove ratings when their removal is allowed",
+			mode:         appconfig.SyncModeFull,
+			allowRatings: true,
+			assertions: func(assertions *assert.Assertions, restored []RestoredTarget, err error, traktClient *fakeTraktClient) {
+				assertions.NoError(err)
+				assertions.Len(restored, 2)
+				assertions.Equal(entities.TraktItems{movie("tt1375666", 7)}, traktClient.removedRatings)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traktClient := &fakeTraktClient{
+				lists: map[string]entities.TraktList{
+					"watched": {IDMeta: entities.TraktIDMeta{Slug: "watched"}, ListItems: entities.TraktItems{dunkirk, interstellar}},
+				},
+				ratings: entities.TraktItems{movie("tt5013056", 8), movie("tt1375666", 7)},
+			}
+			s := &Syncer{
+				logger:      logger.NewLogger(io.Discard),
+				traktClient: traktClient,
+				conf: appconfig.Sync{
+					Mode: &tt.mode,
+					Removals: appconfig.Removals{
+						MaxPercent:   &tt.maxPercent,
+						AllowRatings: &tt.allowRatings,
+					},
+				},
+			}
+			restored, err := s.Restore(context.Background(), snapshot)
+			tt.assertions(assert.New(t), restored, err, traktClient)
+		})
+	}
+}
+
+func TestSyncer_addHistoryBatches(t *testing.T) {
+	play := func(watchedAt time.Time) entities.TraktItem {
+		item := entities.TraktItem{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}},
+		}
+		item.SetWatchedAt(watchedAt)
+		return item
+	}
+	firstWatch := time.Date(2024, time.January, 1, 20, 0, 0, 0, time.UTC)
+	secondWatch := time.Date(2024, time.June, 1, 20, 0, 0, 0, time.UTC)
+	cp, _, err := loadCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), appconfig.SyncModeFull)
+	assert.NoError(t, err)
+	batchSize := 1
+	traktClient := &fakeTraktClient{}
+	s := &Syncer{
+		logger:      logger.NewLogger(io.Discard),
+		traktClient: traktClient,
+		checkpoint:  cp,
+		conf:        appconfig.Sync{HistoryBatchSize: &batchSize},
+	}
+	assertions := assert.New(t)
+	err = s.addHistoryBatches(context.Background(), "check-in history", checkpointCheckInsAdd, entities.TraktItems{play(secondWatch), play(firstWatch)}, checkInKeys)
+	assertions.NoError(err)
+	assertions.Len(traktClient.historyBatches, 2)
+	assertions.Len(s.result.History.Added, 2)
+	assertions.True(s.isProcessed(checkpointCheckInsAdd, checkInKey(play(firstWatch))))
+	assertions.True(s.isProcessed(checkpointCheckInsAdd, checkInKey(play(secondWatch))))
+	watchedAt, _ := traktClient.historyBatches[0][0].WatchedAtTime()
+	assertions.True(watchedAt.Equal(firstWatch))
+}
+
+func TestSyncer_withIMDbIDs(t *testing.T) {
+	dunkirk := entities.IMDbItem{ID: "tt5013056", Title: "Dunkirk", Year: 2017, TitleType: "Movie"}
+	unmatched := entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{Title: "Dunkirk", Year: 2017},
+	}
+	missing := entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{Title: "Tenet", Year: 2020},
+	}
+	matchMissingIDs := true
+	s := &Syncer{
+		logger: logger.NewLogger(io.Discard),
+		conf:   appconfig.Sync{MatchMissingIDs: &matchMissingIDs},
+	}
+	cached := entities.TraktItems{unmatched, missing}
+	kept := s.withIMDbIDs("watchlist", cached, []entities.IMDbItem{dunkirk})
+	assertions := assert.New(t)
+	assertions.Len(kept, 1)
+	assertions.Equal("tt5013056", kept[0].Movie.IDMeta.IMDb)
+	assertions.Empty(cached[0].Movie.IDMeta.IMDb)
+	assertions.Equal(1, s.result.SkippedWithoutIMDbID)
+}
+
+func TestSyncer_hydrateWatchlistMirrors(t *testing.T) {
+	mode := appconfig.SyncModeFull
+	s := &Syncer{
+		logger: logger.NewLogger(io.Discard),
+		traktClient: &fakeTraktClient{
+			createdSlugs: map[string]string{"watchlist-mirror": "watchlist-mirror-1"},
+		},
+		conf:      appconfig.Sync{Mode: &mode},
+		listsConf: appconfig.Lists{WatchlistMirrors: []string{"watchlist-mirror"}},
+		user:      &user{},
+	}
+	assertions := assert.New(t)
+	err := s.hydrateWatchlistMirrors(context.Background(), entities.IMDbList{ListID: "watchlist", IsWatchlist: true})
+	assertions.NoError(err)
+	assertions.Equal("watchlist-mirror-1", s.user.watchlistMirrors["watchlist-mirror"].IDMeta.Slug)
+}
+
+func TestSyncer_syncWatchlistMirrors_removalLimitsInConfigOrder(t *testing.T) {
+	stale := entities.TraktItems{
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"}}},
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0816692"}}},
+	}
+	mirrors := []string{"mirror-c", "mirror-a", "mirror-b"}
+	mode := appconfig.SyncModeFull
+	maxCount := 1
+	assertions := assert.New(t)
+	// the map of diffs would check the mirrors in random order, hence the check is repeated
+	for i := 0; i < 10; i++ {
+		s := &Syncer{
+			logger: logger.NewLogger(io.Discard),
+			conf: appconfig.Sync{
+				Mode:     &mode,
+				Removals: appconfig.Removals{MaxCount: &maxCount},
+			},
+			listsConf: appconfig.Lists{WatchlistMirrors: mirrors},
+			user: &user{
+				watchlistMirrors: make(map[string]entities.TraktList),
+			},
+		}
+		for _, slug := range mirrors {
+			s.user.watchlistMirrors[slug] = entities.TraktList{IDMeta: entities.TraktIDMeta{Slug: slug}, ListItems: stale}
+		}
+		err := s.syncWatchlistMirrors(context.Background(), entities.IMDbList{ListID: "watchlist", IsWatchlist: true}, nil)
+		var thresholdErr *RemovalThresholdError
+		assertions.True(errors.As(err, &thresholdErr))
+		assertions.Equal("list mirror-c", thresholdErr.Target)
+	}
+}
+
+func TestSyncer_syncLists_partialList(t *testing.T) {
+	dunkirk := entities.IMDbItem{ID: "tt5013056", TitleType: "Movie"}
+	interstellar := entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0816692"}},
+	}
+	tests := []struct {
+		name       string
+		partial    bool
+		assertions func(*assert.Assertions, *Syncer)
+	}{
+		{
+			name:    "remove items missing from a complete list",
+			partial: false,
+			assertions: func(assertions *assert.Assertions, s *Syncer) {
+				assertions.Len(s.result.Lists.PendingRemove, 1)
+			},
+		},
+		{
+			name:    "keep items missing from a list truncated to its first page",
+			partial: true,
+			assertions: func(assertions *assert.Assertions, s *Syncer) {
+				assertions.Empty(s.result.Lists.PendingRemove)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := appconfig.SyncModeDryRun
+			s := &Syncer{
+				logger: logger.NewLogger(io.Discard),
+				conf:   appconfig.Sync{Mode: &mode},
+				user: &user{
+					imdbLists: map[string]entities.IMDbList{
+						"ls000000001": {ListID: "ls000000001", ListName: "Watched", ListItems: []entities.IMDbItem{dunkirk}, Partial: tt.partial},
+					},
+					traktLists: map[string]entities.TraktList{
+						"ls000000001": {IDMeta: entities.TraktIDMeta{Slug: "watched"}, ListItems: entities.TraktItems{dunkirk.ToTraktItem(), interstellar}},
+					},
+				},
+			}
+			err := s.syncLists(context.Background())
+			assertions := assert.New(t)
+			assertions.NoError(err)
+			tt.assertions(assertions, s)
+		})
+	}
+}
+
+func TestSyncer_removeEmptyLists(t *testing.T) {
+	dunkirk := entities.IMDbItem{ID: "tt5013056", TitleType: "Movie"}
+	tests := []struct {
+		name       string
+		list       entities.IMDbList
+		sibling    *entities.IMDbList
+		traktItems entities.TraktItems
+		assertions func(*assert.Assertions, *fakeTraktClient)
+	}{
+		{
+			name: "delete the trakt list left without items",
+			list: entities.IMDbList{ListID: "ls000000001", ListName: "Watched"},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Equal([]string{"watched"}, traktClient.removedLists)
+			},
+		},
+		{
+			name:       "keep the trakt list still holding items",
+			list:       entities.IMDbList{ListID: "ls000000001", ListName: "Watched"},
+			traktItems: entities.TraktItems{dunkirk.ToTraktItem()},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Empty(traktClient.removedLists)
+			},
+		},
+		{
+			name:    "keep the trakt list of a sibling imdb list with items",
+			list:    entities.IMDbList{ListID: "ls000000001", ListName: "Watched"},
+			sibling: &entities.IMDbList{ListID: "ls000000002", ListName: "Watched", ListItems: []entities.IMDbItem{dunkirk}},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Empty(traktClient.removedLists)
+			},
+		},
+		{
+			name:    "keep the trakt list of a partial sibling imdb list",
+			list:    entities.IMDbList{ListID: "ls000000001", ListName: "Watched"},
+			sibling: &entities.IMDbList{ListID: "ls000000002", ListName: "Watched", Partial: true},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Empty(traktClient.removedLists)
+			},
+		},
+		{
+			name: "keep the trakt list routed to by list rules",
+			list: entities.IMDbList{ListID: appconfig.RoutedListID("ls000000001", "watched"), ListName: "watched"},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Empty(traktClient.removedLists)
+			},
+		},
+		{
+			name: "keep the genre trakt list",
+			list: entities.IMDbList{ListID: appconfig.GenreListID("Watched"), ListName: "Watched"},
+			assertions: func(assertions *assert.Assertions, traktClient *fakeTraktClient) {
+				assertions.Empty(traktClient.removedLists)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := appconfig.SyncModeFull
+			traktClient := &fakeTraktClient{
+				lists: map[string]entities.TraktList{
+					"watched": {IDMeta: entities.TraktIDMeta{Slug: "watched"}, ListItems: tt.traktItems},
+				},
+			}
+			s := &Syncer{
+				logger:      logger.NewLogger(io.Discard),
+				traktClient: traktClient,
+				conf:        appconfig.Sync{Mode: &mode},
+				user: &user{
+					imdbLists: map[string]entities.IMDbList{
+						tt.list.ListID: tt.list,
+					},
+					traktLists: map[string]entities.TraktList{
+						tt.list.ListID: {IDMeta: entities.TraktIDMeta{Slug: "watched"}},
+					},
+				},
+			}
+			if tt.sibling != nil {
+				s.user.imdbLists[tt.sibling.ListID] = *tt.sibling
+				s.user.traktLists[tt.sibling.ListID] = entities.TraktList{IDMeta: entities.TraktIDMeta{Slug: "watched"}}
+			}
+			assertions := assert.New(t)
+			assertions.NoError(s.removeEmptyLists(context.Background()))
+			tt.assertions(assertions, traktClient)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+// syncTrackedLists syncs the lists, recording the items added to them in the tracking list, including the ones added before a failure
+func (s *Syncer) syncTrackedLists(ctx context.Context) error {
+	err := s.syncLists(ctx)
+	s.trackAdded(ctx)
+	return err
+}
+
+// trackAdded records the items added to the trakt watchlist and lists by the run in the tracking list, which is created as a private list when missing
+// failing to record them is only logged, since the items were synced regardless
+func (s *Syncer) trackAdded(ctx context.Context) {
+	slug := s.listsConf.TrackingListSlug()
+	added := append(slices.Clone(s.result.Watchlist.Added), s.result.Lists.Added...)
+	if slug == "" || len(added) == 0 {
+		return
+	}
+	s.invalidateCache(slug)
+	_, err := s.traktClient.ListItemsAdd(ctx, slug, added)
+	var notFoundErr *client.TraktNotFoundError
+	if errors.As(err, &notFoundErr) {
+		if _, err = s.traktClient.ListAdd(ctx, slug, slug, appconfig.ListPrivacyPrivate, "", ""); err == nil {
+			_, err = s.traktClient.ListItemsAdd(ctx, slug, added)
+		}
+	}
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failure recording %d added trakt item(s) in tracking list %s", len(added), slug), logger.Error(err))
+		return
+	}
+	s.logger.Info(fmt.Sprintf("recorded %d added trakt item(s) in tracking list %s", len(added), slug))
+}
+
+// trackedItems returns the imdb ids of the items recorded in the tracking list, nil when tracking is disabled
+func (s *Syncer) trackedItems(ctx context.Context) (map[string]struct{}, error) {
+	slug := s.listsConf.TrackingListSlug()
+	if slug == "" {
+		return nil, nil
+	}
+	tracked := make(map[string]struct{})
+	list, err := s.traktClient.ListGet(ctx, slug)
+	var notFoundErr *client.TraktListNotFoundError
+	switch {
+	case errors.As(err, &notFoundErr):
+		s.logger.Info(fmt.Sprintf("trakt tracking list %s doesn't exist yet, no items were recorded in it", slug))
+		return tracked, nil
+	case err != nil:
+		return nil, fmt.Errorf("failure fetching trakt tracking list %s: %w", slug, err)
+	}
+	for _, item := range list.ListItems {
+		if id, _ := item.GetItemID(); id != nil && *id != "" {
+			tracked[*id] = struct{}{}
+		}
+	}
+	return tracked, nil
+}
+
+// withoutUntracked leaves out the items missing from the tracking list, i.e. the ones added to trakt manually, unless tracking is disabled
+func (s *Syncer) withoutUntracked(target string, tracked map[string]struct{}, items entities.TraktItems) entities.TraktItems {
+	if tracked == nil || len(items) == 0 {
+		return items
+	}
+	var kept, untracked entities.TraktItems
+	for _, item := range items {
+		if id, _ := item.GetItemID(); id != nil {
+			if _, found := tracked[*id]; found {
+				kept = append(kept, item)
+				continue
+			}
+		}
+		untracked = append(untracked, item)
+	}
+	if len(untracked) > 0 {
+		s.logger.Info(fmt.Sprintf("keeping %d trakt %s item(s) missing from the tracking list, they weren't added by the syncer", len(untracked), target), s.diffItems(target, untracked))
+	}
+	return kept
+}
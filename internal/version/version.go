@@ -0,0 +1,57 @@
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// set at build time via ldflags, e.g. -X github.com/cecobask/imdb-trakt-sync/internal/version.version=v1.0.0
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+// Info describes the build of the running binary
+type Info struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// Get returns the build metadata embedded via ldflags, falling back to the metadata recorded by the go toolchain
+func Get() Info {
+	info := Info{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// String describes the build in a single line, e.g. v1.0.0 (commit 1a2b3c4, built 2024-01-01T00:00:00Z)
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", i.Version, i.Commit, i.Date)
+}
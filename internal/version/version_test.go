@@ -0,0 +1,44 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		ldflags    Info
+		assertions func(*assert.Assertions, Info)
+	}{
+		{
+			name: "use metadata embedded via ldflags",
+			ldflags: Info{
+				Version: "v1.0.0",
+				Commit:  "1a2b3c4",
+				Date:    "2024-01-01T00:00:00Z",
+			},
+			assertions: func(assertions *assert.Assertions, info Info) {
+				assertions.Equal("v1.0.0 (commit 1a2b3c4, built 2024-01-01T00:00:00Z)", info.String())
+			},
+		},
+		{
+			name: "fall back to placeholders without metadata",
+			assertions: func(assertions *assert.Assertions, info Info) {
+				assertions.NotEmpty(info.Version)
+				assertions.NotEmpty(info.Commit)
+				assertions.NotEmpty(info.Date)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, commit, date = tt.ldflags.Version, tt.ldflags.Commit, tt.ldflags.Date
+			t.Cleanup(func() {
+				version, commit, date = "", "", ""
+			})
+			tt.assertions(assert.New(t), Get())
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/cecobask/imdb-trakt-sync/cmd"
 	"github.com/cecobask/imdb-trakt-sync/cmd/root"
 )
 
 func main() {
-	err := root.NewCommand().Execute()
-	if err != nil {
-		os.Exit(1)
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := root.NewCommand().ExecuteContext(ctx)
+	stop()
+	os.Exit(cmd.ExitCode(err))
 }
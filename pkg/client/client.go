@@ -2,49 +2,65 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 )
 
 type IMDbClientInterface interface {
-	ListGet(listID string) (*entities.IMDbList, error)
-	ListsGet(listIDs []string) ([]entities.IMDbList, error)
-	WatchlistGet() (*entities.IMDbList, error)
-	ListsGetAll() ([]entities.IMDbList, error)
-	RatingsGet() ([]entities.IMDbItem, error)
-	UserIDScrape() error
-	WatchlistIDScrape() error
-	Hydrate() error
+	ListGet(ctx context.Context, listID string) (*entities.IMDbList, error)
+	ListsGet(ctx context.Context, listIDs []string) ([]entities.IMDbList, []error)
+	WatchlistGet(ctx context.Context) (*entities.IMDbList, error)
+	ListDescriptionGet(ctx context.Context, listID string) (string, error)
+	ListsGetAll(ctx context.Context) ([]entities.IMDbList, []error)
+	RatingsGet(ctx context.Context) ([]entities.IMDbItem, error)
+	RatingsAdd(ctx context.Context, items []entities.IMDbItem) error
+	UserIDScrape(ctx context.Context) error
+	WatchlistIDScrape(ctx context.Context) error
+	Hydrate(ctx context.Context) error
 }
 
 type TraktClientInterface interface {
-	BrowseSignIn() (*string, error)
-	SignIn(authenticityToken string) error
-	BrowseActivate() (*string, error)
-	Activate(userCode, authenticityToken string) (*string, error)
-	ActivateAuthorize(authenticityToken string) error
-	GetAccessToken(deviceCode string) (*entities.TraktAuthTokensResponse, error)
-	GetAuthCodes() (*entities.TraktAuthCodesResponse, error)
-	WatchlistGet() (*entities.TraktList, error)
-	WatchlistItemsAdd(items entities.TraktItems) error
-	WatchlistItemsRemove(items entities.TraktItems) error
-	ListGet(listID string) (*entities.TraktList, error)
-	ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error)
-	ListItemsAdd(listID string, items entities.TraktItems) error
-	ListItemsRemove(listID string, items entities.TraktItems) error
-	ListAdd(listID, listName string) error
-	ListRemove(listID string) error
-	RatingsGet() (entities.TraktItems, error)
-	RatingsAdd(items entities.TraktItems) error
-	RatingsRemove(items entities.TraktItems) error
-	HistoryGet(itemType, itemID string) (entities.TraktItems, error)
-	HistoryAdd(items entities.TraktItems) error
-	HistoryRemove(items entities.TraktItems) error
-	Hydrate() error
+	BrowseSignIn(ctx context.Context) (*string, error)
+	SignIn(ctx context.Context, authenticityToken string) error
+	BrowseActivate(ctx context.Context) (*string, error)
+	Activate(ctx context.Context, userCode, authenticityToken string) (*string, error)
+	ActivateAuthorize(ctx context.Context, authenticityToken string) error
+	GetAccessToken(ctx context.Context, deviceCode string) (*entities.TraktAuthTokensResponse, error)
+	GetAuthCodes(ctx context.Context) (*entities.TraktAuthCodesResponse, error)
+	WatchlistGet(ctx context.Context) (*entities.TraktList, error)
+	WatchlistItemsAdd(ctx context.Context, items entities.TraktItems) (*entities.TraktResponse, error)
+	WatchlistItemsRemove(ctx context.Context, items entities.TraktItems) error
+	ListGet(ctx context.Context, listID string) (*entities.TraktList, error)
+	ListsGet(ctx context.Context, idMeta entities.TraktIDMetas) ([]entities.TraktList, []error)
+	ListItemsAdd(ctx context.Context, listID string, items entities.TraktItems) (*entities.TraktResponse, error)
+	ListItemsRemove(ctx context.Context, listID string, items entities.TraktItems) error
+	ListAdd(ctx context.Context, listID, listName, privacy, sortBy, sortHow string) (*entities.TraktList, error)
+	ListSummaryGet(ctx context.Context, listID string) (*entities.TraktList, error)
+	ListUpdate(ctx context.Context, listID string, body entities.TraktListUpdateBody) error
+	ListRemove(ctx context.Context, listID string) error
+	ListLike(ctx context.Context, owner, listID string) error
+	RatingsGet(ctx context.Context) (entities.TraktItems, error)
+	RatingsAdd(ctx context.Context, items entities.TraktItems) error
+	RatingsRemove(ctx context.Context, items entities.TraktItems) error
+	HistoryGet(ctx context.Context, itemType, itemID string) (entities.TraktItems, error)
+	WatchedGet(ctx context.Context) (entities.TraktItems, error)
+	HiddenGet(ctx context.Context, section string) (entities.TraktItems, error)
+	HistoryAdd(ctx context.Context, items entities.TraktItems) error
+	HistoryRemove(ctx context.Context, items entities.TraktItems) error
+	CommentsGet(ctx context.Context) (entities.TraktItems, error)
+	LastActivitiesGet(ctx context.Context) (*entities.TraktLastActivities, error)
+	CommentAdd(ctx context.Context, item entities.TraktItem, comment string, spoiler bool) error
+	Scrobble(ctx context.Context, item entities.TraktItem) error
+	Search(ctx context.Context, itemType, title string, year int) (entities.TraktItems, error)
+	Hydrate(ctx context.Context) error
 }
 
 const (
@@ -85,6 +101,15 @@ func (r reusableReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
+func sleepContext(ctx context.Context, clk clock.Clock, duration time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clk.After(duration):
+		return nil
+	}
+}
+
 func scrapeSelectionAttribute(body io.ReadCloser, clientName, selector, attribute string) (*string, error) {
 	defer body.Close()
 	doc, err := goquery.NewDocumentFromReader(body)
@@ -109,6 +134,152 @@ func (e *ApiError) Error() string {
 	return fmt.Sprintf("http request %s %s returned status code %d: %s", e.httpMethod, e.url, e.StatusCode, e.details)
 }
 
+type TraktVIPRequiredError struct {
+	Feature    string
+	Limit      int
+	UpgradeURL string
+	apiErr     *ApiError
+}
+
+func (e *TraktVIPRequiredError) Error() string {
+	limit := "unknown"
+	if e.Limit > 0 {
+		limit = strconv.Itoa(e.Limit)
+	}
+	return fmt.Sprintf("trakt vip is required for %s (account limit %s), upgrade here: %s: %s", e.Feature, limit, e.UpgradeURL, e.apiErr)
+}
+
+func (e *TraktVIPRequiredError) Unwrap() error {
+	return e.apiErr
+}
+
+// IMDbAuthExpiredError is returned when imdb rejects the configured cookies
+// the cookies can't be refreshed programmatically, hence the user has to replace them
+type IMDbAuthExpiredError struct {
+	apiErr *ApiError
+}
+
+func (e *IMDbAuthExpiredError) Error() string {
+	return fmt.Sprintf("imdb session has expired, log into imdb from a web browser and replace the values of config fields 'IMDB_COOKIEATMAIN' and 'IMDB_COOKIEUBIDMAIN' with the values of cookies %s and %s: %s", imdbCookieNameAtMain, imdbCookieNameUbidMain, e.apiErr)
+}
+
+func (e *IMDbAuthExpiredError) Unwrap() error {
+	return e.apiErr
+}
+
+// IMDbChallengeError is returned when imdb responds with an anti-bot challenge page instead of the requested data
+type IMDbChallengeError struct {
+	apiErr *ApiError
+}
+
+func (e *IMDbChallengeError) Error() string {
+	return fmt.Sprintf("imdb responded with an anti-bot challenge instead of the requested data, wait a while before syncing again or replace the values of config fields 'IMDB_COOKIEATMAIN' and 'IMDB_COOKIEUBIDMAIN' with fresh cookies: %s", e.apiErr)
+}
+
+func (e *IMDbChallengeError) Unwrap() error {
+	return e.apiErr
+}
+
+// IMDbListPrivateError is returned when a list can't be fetched anonymously, because it is private or imdb doesn't tell whether it exists
+type IMDbListPrivateError struct {
+	ListID string
+	apiErr *ApiError
+}
+
+func (e *IMDbListPrivateError) Error() string {
+	return fmt.Sprintf("imdb list %s is private and can't be synced without imdb authentication, make the list public or set config field 'IMDB_AUTH' to cookies: %s", e.ListID, e.apiErr)
+}
+
+func (e *IMDbListPrivateError) Unwrap() error {
+	return e.apiErr
+}
+
+// IMDbListError is delegated when a single list can't be fetched, so that the other lists can still be synced
+type IMDbListError struct {
+	ListID string
+	err    error
+}
+
+func (e *IMDbListError) Error() string {
+	return fmt.Sprintf("failure fetching imdb list %s: %s", e.ListID, e.err)
+}
+
+func (e *IMDbListError) Unwrap() error {
+	return e.err
+}
+
+type TraktUnauthorizedError struct {
+	apiErr *ApiError
+}
+
+func (e *TraktUnauthorizedError) Error() string {
+	return fmt.Sprintf("trakt rejected the credentials, check the TRAKT_EMAIL, TRAKT_PASSWORD, TRAKT_CLIENTID and TRAKT_CLIENTSECRET config fields: %s", e.apiErr)
+}
+
+func (e *TraktUnauthorizedError) Unwrap() error {
+	return e.apiErr
+}
+
+type TraktForbiddenError struct {
+	apiErr *ApiError
+}
+
+func (e *TraktForbiddenError) Error() string {
+	return fmt.Sprintf("trakt denied access to the resource, check that the trakt app is approved: %s", e.apiErr)
+}
+
+func (e *TraktForbiddenError) Unwrap() error {
+	return e.apiErr
+}
+
+type TraktNotFoundError struct {
+	apiErr *ApiError
+}
+
+func (e *TraktNotFoundError) Error() string {
+	return fmt.Sprintf("trakt resource could not be found: %s", e.apiErr)
+}
+
+func (e *TraktNotFoundError) Unwrap() error {
+	return e.apiErr
+}
+
+type TraktRateLimitError struct {
+	RetryAfter time.Duration
+	apiErr     *ApiError
+}
+
+func (e *TraktRateLimitError) Error() string {
+	return fmt.Sprintf("trakt rate limit reached, retry after %s: %s", e.RetryAfter, e.apiErr)
+}
+
+func (e *TraktRateLimitError) Unwrap() error {
+	return e.apiErr
+}
+
+// TraktWatchlistUnavailableError is returned when trakt denies access to the watchlist, e.g. due to restrictions or limits of the account
+type TraktWatchlistUnavailableError struct {
+	err error
+}
+
+func (e *TraktWatchlistUnavailableError) Error() string {
+	return fmt.Sprintf("trakt watchlist is unavailable, the trakt account may be restricted or limited: %s", e.err)
+}
+
+func (e *TraktWatchlistUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// TraktListNotOwnedError is returned for trakt lists of other users, which can't be edited
+type TraktListNotOwnedError struct {
+	Slug  string
+	Owner string
+}
+
+func (e *TraktListNotOwnedError) Error() string {
+	return fmt.Sprintf("list with id %s is owned by %s, only your own lists can be edited", e.Slug, e.Owner)
+}
+
 type TraktListNotFoundError struct {
 	Slug string
 }
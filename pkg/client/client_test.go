@@ -1,11 +1,15 @@
 package client
 
 import (
+	"context"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 )
 
 func Test_scrapeSelectionAttribute(t *testing.T) {
@@ -68,6 +72,48 @@ func Test_scrapeSelectionAttribute(t *testing.T) {
 	}
 }
 
+func Test_sleepContext(t *testing.T) {
+	type args struct {
+		ctx      func() context.Context
+		duration time.Duration
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "sleep for the whole duration",
+			args: args{
+				ctx:      context.Background,
+				duration: time.Millisecond,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "abort sleep when context is cancelled",
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					return ctx
+				},
+				duration: time.Hour,
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorIs(err, context.Canceled)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), sleepContext(tt.args.ctx(), clock.New(), tt.args.duration))
+		})
+	}
+}
+
 type stuckReadCloser struct{}
 
 func (*stuckReadCloser) Read([]byte) (int, error) {
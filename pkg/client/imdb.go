@@ -1,9 +1,14 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"mime"
 	"net/http"
@@ -18,23 +23,57 @@ import (
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
 const (
+	imdbColumnNameCreated           = "Created"
+	imdbColumnNameDateRated         = "Date Rated"
+	imdbColumnNameDescription       = "Description"
+	imdbColumnNameGenres            = "Genres"
+	imdbColumnNameID                = "Const"
+	imdbColumnNameRating            = "Your Rating"
+	imdbColumnNameReview            = "Your Review"
+	imdbColumnNameTitle             = "Title"
+	imdbColumnNameTitleType         = "Title Type"
+	imdbColumnNameYear              = "Year"
 	imdbCookieNameAtMain            = "at-main"
 	imdbCookieNameUbidMain          = "ubid-main"
 	imdbHeaderKeyContentDisposition = "Content-Disposition"
+	imdbHeaderKeyContentType        = "Content-Type"
+	imdbHeaderKeyWAFAction          = "X-Amzn-Waf-Action"
 	imdbPathBase                    = "https://www.imdb.com"
+	imdbPathGraphQL                 = "https://api.graphql.imdb.com"
+	imdbPathList                    = "/list/%s/"
 	imdbPathListExport              = "/list/%s/export"
-	imdbPathLists                   = "/user/%s/lists"
+	imdbPathLists                   = "/user/%s/lists?page=%d"
 	imdbPathProfile                 = "/profile"
 	imdbPathRatingsExport           = "/user/%s/ratings/export"
+	imdbPathSignIn                  = "/registration/signin"
+	imdbPathSignInAmazon            = "/ap/signin"
 	imdbPathWatchlist               = "/watchlist"
+	imdbUserAgentDefault            = "PostmanRuntime/7.37.3" // workaround for https://github.com/cecobask/imdb-trakt-sync/issues/33
+
+	imdbChallengeRetryDelay = 30 * time.Second
+	imdbListsMaxPages       = 100
+	imdbSniffLength         = 512 // the bytes http.DetectContentType considers at most
+
+	imdbItemTypeMovie            = "Movie"
+	imdbSelectorListItem         = "li.ipc-metadata-list-summary-item"
+	imdbSelectorListItemLink     = "a.ipc-title-link-wrapper"
+	imdbSelectorListItemMetadata = ".dli-title-metadata-item"
+	imdbSelectorListItemTitle    = "h3.ipc-title__text"
+	imdbSelectorListItemType     = ".dli-title-type-data"
+	imdbSelectorListTitle        = "[data-testid='list-page-mc-list-title']"
+
+	// imdbMutationRateTitle is the graphql mutation the imdb website rates titles with, imdb offers no other way of adding ratings
+	imdbMutationRateTitle = "mutation UpdateTitleRating($rating: Int!, $titleId: ID!) { rateTitle(input: {rating: $rating, titleId: $titleId}) { rating { value } } }"
 )
 
 type IMDbClient struct {
 	client *http.Client
+	clock  clock.Clock
 	config imdbConfig
 	logger *slog.Logger
 }
@@ -42,74 +81,127 @@ type IMDbClient struct {
 type imdbConfig struct {
 	appconfig.IMDb
 	basePath    string
+	graphqlPath string
 	userID      string
 	watchlistID string
 }
 
-func NewIMDbClient(conf appconfig.IMDb, logger *slog.Logger) (IMDbClientInterface, error) {
+func NewIMDbClient(conf appconfig.IMDb, httpConf appconfig.HTTP, logger *slog.Logger) (IMDbClientInterface, error) {
 	config := imdbConfig{
-		IMDb:     conf,
-		basePath: imdbPathBase,
+		IMDb:        conf,
+		basePath:    imdbPathBase,
+		graphqlPath: imdbPathGraphQL,
 	}
 	jar, err := setupCookieJar(config)
 	if err != nil {
 		return nil, err
 	}
+	transport, err := newBaseTransport(httpConf)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating imdb http transport: %w", err)
+	}
 	client := &IMDbClient{
 		client: &http.Client{
-			Jar: jar,
+			Jar:       jar,
+			Transport: newLoggingTransport(newHeadersTransport(transport, httpConf, imdbUserAgentDefault), logger, clientNameIMDb),
 		},
+		clock:  clock.New(),
 		config: config,
 		logger: logger,
 	}
 	return client, nil
 }
 
+// setupCookieJar sets the cookies for the website and the graphql api, which is served from another host
 func setupCookieJar(config imdbConfig) (http.CookieJar, error) {
-	imdbUrl, err := url.Parse(config.basePath)
-	if err != nil {
-		return nil, fmt.Errorf("failure parsing %s as url: %w", config.basePath, err)
-	}
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, fmt.Errorf("failure creating cookie jar: %w", err)
 	}
-	jar.SetCookies(imdbUrl, []*http.Cookie{
-		{
-			Name:  imdbCookieNameAtMain,
-			Value: *config.CookieAtMain,
-		},
-		{
-			Name:  imdbCookieNameUbidMain,
-			Value: *config.CookieUbidMain,
-		},
-	})
+	if config.IsAuthless() {
+		return jar, nil
+	}
+	for _, path := range []string{config.basePath, config.graphqlPath} {
+		imdbUrl, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing %s as url: %w", path, err)
+		}
+		jar.SetCookies(imdbUrl, []*http.Cookie{
+			{
+				Name:  imdbCookieNameAtMain,
+				Value: *config.CookieAtMain,
+			},
+			{
+				Name:  imdbCookieNameUbidMain,
+				Value: *config.CookieUbidMain,
+			},
+		})
+	}
 	return jar, nil
 }
 
-func (c *IMDbClient) Hydrate() error {
-	if err := c.UserIDScrape(); err != nil {
+func (c *IMDbClient) Hydrate(ctx context.Context) error {
+	if c.config.IsAuthless() {
+		c.logger.Info("imdb authentication is disabled, only public imdb lists can be synced")
+		return nil
+	}
+	if err := c.UserIDScrape(ctx); err != nil {
 		return fmt.Errorf("failure scraping imdb user id: %w", err)
 	}
-	if err := c.WatchlistIDScrape(); err != nil {
+	if err := c.WatchlistIDScrape(ctx); err != nil {
 		return fmt.Errorf("failure scraping imdb watchlist id: %w", err)
 	}
 	return nil
 }
 
-func (c *IMDbClient) doRequest(requestFields requestFields) (*http.Response, error) {
-	request, err := http.NewRequest(requestFields.Method, requestFields.BasePath+requestFields.Endpoint, requestFields.Body)
+// doRequest sends the request, retrying it once after a delay when imdb responds with an anti-bot challenge
+func (c *IMDbClient) doRequest(ctx context.Context, requestFields requestFields) (*http.Response, error) {
+	response, err := c.sendRequest(ctx, requestFields)
+	var challengeErr *IMDbChallengeError
+	if !errors.As(err, &challengeErr) {
+		return response, err
+	}
+	c.logger.Warn("imdb responded with an anti-bot challenge, retrying once", slog.Duration("delay", imdbChallengeRetryDelay))
+	if err = sleepContext(ctx, c.clock, imdbChallengeRetryDelay); err != nil {
+		return nil, err
+	}
+	return c.sendRequest(ctx, requestFields)
+}
+
+func (c *IMDbClient) sendRequest(ctx context.Context, requestFields requestFields) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, requestFields.Method, requestFields.BasePath+requestFields.Endpoint, requestFields.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failure creating http request %s %s: %w", requestFields.Method, requestFields.BasePath+requestFields.Endpoint, err)
 	}
-	request.Header.Set("User-Agent", "PostmanRuntime/7.37.3") // workaround for https://github.com/cecobask/imdb-trakt-sync/issues/33
+	for key, value := range requestFields.Headers {
+		request.Header.Set(key, value)
+	}
 	response, err := c.client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("failure sending http request %s %s: %w", request.Method, request.URL, err)
 	}
+	if isIMDbChallenge(response) {
+		response.Body.Close()
+		return nil, &IMDbChallengeError{
+			apiErr: &ApiError{
+				httpMethod: request.Method,
+				url:        request.URL.String(),
+				StatusCode: response.StatusCode,
+				details:    "response is a challenge page",
+			},
+		}
+	}
 	switch response.StatusCode {
 	case http.StatusOK, http.StatusNotFound:
+		// imdb redirects unauthenticated requests to the sign in page
+		if path := response.Request.URL.Path; strings.HasPrefix(path, imdbPathSignIn) || strings.HasPrefix(path, imdbPathSignInAmazon) {
+			response.Body.Close()
+			return nil, newIMDbAuthExpiredError(response, "request was redirected to the sign in page")
+		}
 		return response, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		response.Body.Close()
+		return nil, newIMDbAuthExpiredError(response, fmt.Sprintf("unexpected status code %d", response.StatusCode))
 	default:
 		response.Body.Close()
 		return nil, &ApiError{
@@ -121,29 +213,99 @@ func (c *IMDbClient) doRequest(requestFields requestFields) (*http.Response, err
 	}
 }
 
-func (c *IMDbClient) ListGet(listID string) (*entities.IMDbList, error) {
-	response, err := c.doRequest(requestFields{
+// isIMDbChallenge reports whether the response is an anti-bot challenge, which is either flagged by the waf or served as html in place of a csv export
+// imdb serves some list exports as the html page of the list though, depending on the endpoint and cookies, hence the body of an html export is
+// buffered to tell list pages apart from challenges, list pages are parsed in place of the csv by readIMDbListResponse
+func isIMDbChallenge(response *http.Response) bool {
+	if response.Header.Get(imdbHeaderKeyWAFAction) != "" || response.StatusCode == http.StatusAccepted {
+		return true
+	}
+	if response.StatusCode != http.StatusOK || !strings.HasSuffix(response.Request.URL.Path, "/export") {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(response.Header.Get(imdbHeaderKeyContentType))
+	if err != nil || mediaType != "text/html" {
+		return false
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	return err != nil || !isIMDbListPage(bytes.NewReader(body))
+}
+
+func newIMDbAuthExpiredError(response *http.Response, details string) *IMDbAuthExpiredError {
+	return &IMDbAuthExpiredError{
+		apiErr: &ApiError{
+			httpMethod: response.Request.Method,
+			url:        response.Request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    details,
+		},
+	}
+}
+
+func (c *IMDbClient) ListGet(ctx context.Context, listID string) (*entities.IMDbList, error) {
+	response, err := c.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: c.config.basePath,
 		Endpoint: fmt.Sprintf(imdbPathListExport, listID),
 		Body:     http.NoBody,
 	})
 	if err != nil {
+		var authExpiredErr *IMDbAuthExpiredError
+		if c.config.IsAuthless() && errors.As(err, &authExpiredErr) {
+			return nil, &IMDbListPrivateError{
+				ListID: listID,
+				apiErr: authExpiredErr.apiErr,
+			}
+		}
 		return nil, err
 	}
 	if response.StatusCode == http.StatusNotFound {
-		return nil, &ApiError{
+		response.Body.Close()
+		apiErr := &ApiError{
 			httpMethod: response.Request.Method,
 			url:        response.Request.URL.String(),
 			StatusCode: response.StatusCode,
 			details:    fmt.Sprintf("list with id %s could not be found", listID),
 		}
+		// private lists can't be told apart from missing ones without authentication, hence they must not be skipped as if they were missing
+		if c.config.IsAuthless() {
+			return nil, &IMDbListPrivateError{
+				ListID: listID,
+				apiErr: apiErr,
+			}
+		}
+		return nil, apiErr
 	}
 	return readIMDbListResponse(response, listID)
 }
 
-func (c *IMDbClient) WatchlistGet() (*entities.IMDbList, error) {
-	list, err := c.ListGet(c.config.watchlistID)
+// ListDescriptionGet scrapes the description of a list from its page, since the list export doesn't include it
+// lists without a description result in an empty string
+func (c *IMDbClient) ListDescriptionGet(ctx context.Context, listID string) (string, error) {
+	response, err := c.doRequest(ctx, requestFields{
+		Method:   http.MethodGet,
+		BasePath: c.config.basePath,
+		Endpoint: fmt.Sprintf(imdbPathList, listID),
+		Body:     http.NoBody,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("failure creating goquery document from imdb response: %w", err)
+	}
+	return strings.TrimSpace(doc.Find("[data-testid='list-description']").First().Text()), nil
+}
+
+func (c *IMDbClient) WatchlistGet(ctx context.Context) (*entities.IMDbList, error) {
+	if c.config.IsAuthless() {
+		return nil, errAuthRequired("fetching the imdb watchlist")
+	}
+	list, err := c.ListGet(ctx, c.config.watchlistID)
 	if err != nil {
 		return nil, err
 	}
@@ -151,11 +313,44 @@ func (c *IMDbClient) WatchlistGet() (*entities.IMDbList, error) {
 	return list, nil
 }
 
-func (c *IMDbClient) ListsGetAll() ([]entities.IMDbList, error) {
-	response, err := c.doRequest(requestFields{
+// ListsGetAll scrapes the ids of all lists owned by the user, going through every page of the lists overview
+func (c *IMDbClient) ListsGetAll(ctx context.Context) ([]entities.IMDbList, []error) {
+	if c.config.IsAuthless() {
+		return nil, []error{errAuthRequired("discovering imdb lists")}
+	}
+	var (
+		ids  = make([]string, 0)
+		seen = make(map[string]struct{})
+	)
+	for page := 1; page <= imdbListsMaxPages; page++ {
+		pageIDs, err := c.listIDsScrape(ctx, page)
+		if err != nil {
+			return nil, []error{err}
+		}
+		newIDs := 0
+		for _, id := range pageIDs {
+			if _, found := seen[id]; found {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+			newIDs++
+		}
+		if newIDs == 0 {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return nil, []error{fmt.Errorf("failure finding imdb lists in html response")}
+	}
+	return c.ListsGet(ctx, ids)
+}
+
+func (c *IMDbClient) listIDsScrape(ctx context.Context, page int) ([]string, error) {
+	response, err := c.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: c.config.basePath,
-		Endpoint: fmt.Sprintf(imdbPathLists, c.config.userID),
+		Endpoint: fmt.Sprintf(imdbPathLists, c.config.userID, page),
 		Body:     http.NoBody,
 	})
 	if err != nil {
@@ -171,7 +366,7 @@ func (c *IMDbClient) ListsGetAll() ([]entities.IMDbList, error) {
 		itemsSelector   = "li[data-testid='user-ll-item']"
 		summarySelector = ".ipc-metadata-list-summary-item__t"
 	)
-	selection := doc.Find(itemsSelector).Each(func(i int, selection *goquery.Selection) {
+	doc.Find(itemsSelector).Each(func(i int, selection *goquery.Selection) {
 		value, ok := selection.Find(summarySelector).Attr("href")
 		if !ok {
 			c.logger.Error(fmt.Sprintf("failure scraping selector %s", summarySelector))
@@ -179,23 +374,24 @@ func (c *IMDbClient) ListsGetAll() ([]entities.IMDbList, error) {
 		}
 		listID, err := extractListID(value)
 		if err != nil {
-			c.logger.Error("failure extracting imdb list id", err)
+			c.logger.Error("failure extracting imdb list id", logger.Error(err))
 			return
 		}
 		ids = append(ids, listID)
 	})
-	if selection.Length() == 0 {
-		return nil, fmt.Errorf("failure finding imdb lists in html response")
-	}
-	return c.ListsGet(ids)
+	return ids, nil
 }
 
-func (c *IMDbClient) ListsGet(listIDs []string) ([]entities.IMDbList, error) {
+// ListsGet fetches the lists concurrently, delegating the errors of lists that can't be fetched to the caller
+// errors affecting all lists, such as expired cookies, abort the whole batch instead
+func (c *IMDbClient) ListsGet(ctx context.Context, listIDs []string) ([]entities.IMDbList, []error) {
 	var (
-		outChan  = make(chan entities.IMDbList, len(listIDs))
-		errChan  = make(chan error, 1)
-		doneChan = make(chan struct{})
-		lists    = make([]entities.IMDbList, 0, len(listIDs))
+		outChan         = make(chan entities.IMDbList, len(listIDs))
+		errChan         = make(chan error, 1)
+		doneChan        = make(chan struct{})
+		lists           = make([]entities.IMDbList, 0, len(listIDs))
+		delegatedErrors = make([]error, 0, len(listIDs))
+		mutex           sync.Mutex
 	)
 	go func() {
 		waitGroup := new(sync.WaitGroup)
@@ -203,14 +399,18 @@ func (c *IMDbClient) ListsGet(listIDs []string) ([]entities.IMDbList, error) {
 			waitGroup.Add(1)
 			go func(id string) {
 				defer waitGroup.Done()
-				imdbList, err := c.ListGet(id)
+				imdbList, err := c.ListGet(ctx, id)
 				if err != nil {
-					var apiError *ApiError
-					if errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound {
-						c.logger.Debug("silencing not found error while fetching imdb lists", logger.Error(apiError))
+					if !affectsSingleIMDbList(err) {
+						errChan <- fmt.Errorf("unexpected error while fetching imdb lists: %w", err)
 						return
 					}
-					errChan <- fmt.Errorf("unexpected error while fetching imdb lists: %w", err)
+					mutex.Lock()
+					delegatedErrors = append(delegatedErrors, &IMDbListError{
+						ListID: id,
+						err:    err,
+					})
+					mutex.Unlock()
 					return
 				}
 				outChan <- *imdbList
@@ -224,15 +424,26 @@ func (c *IMDbClient) ListsGet(listIDs []string) ([]entities.IMDbList, error) {
 		case list := <-outChan:
 			lists = append(lists, list)
 		case err := <-errChan:
-			return nil, err
+			return nil, []error{err}
 		case <-doneChan:
-			return lists, nil
+			// the lists sent right before closing the done channel may not have been received yet
+			for len(outChan) > 0 {
+				lists = append(lists, <-outChan)
+			}
+			return lists, delegatedErrors
 		}
 	}
 }
 
-func (c *IMDbClient) UserIDScrape() error {
-	response, err := c.doRequest(requestFields{
+// affectsSingleIMDbList tells whether a list can be skipped due to the error, as opposed to errors failing every request
+func affectsSingleIMDbList(err error) bool {
+	var authExpiredErr *IMDbAuthExpiredError
+	var challengeErr *IMDbChallengeError
+	return !errors.As(err, &authExpiredErr) && !errors.As(err, &challengeErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *IMDbClient) UserIDScrape(ctx context.Context) error {
+	response, err := c.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: c.config.basePath,
 		Endpoint: imdbPathProfile,
@@ -249,8 +460,8 @@ func (c *IMDbClient) UserIDScrape() error {
 	return nil
 }
 
-func (c *IMDbClient) WatchlistIDScrape() error {
-	response, err := c.doRequest(requestFields{
+func (c *IMDbClient) WatchlistIDScrape(ctx context.Context) error {
+	response, err := c.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: c.config.basePath,
 		Endpoint: imdbPathWatchlist,
@@ -271,8 +482,11 @@ func (c *IMDbClient) WatchlistIDScrape() error {
 	return nil
 }
 
-func (c *IMDbClient) RatingsGet() ([]entities.IMDbItem, error) {
-	response, err := c.doRequest(requestFields{
+func (c *IMDbClient) RatingsGet(ctx context.Context) ([]entities.IMDbItem, error) {
+	if c.config.IsAuthless() {
+		return nil, errAuthRequired("fetching imdb ratings")
+	}
+	response, err := c.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: c.config.basePath,
 		Endpoint: fmt.Sprintf(imdbPathRatingsExport, c.config.userID),
@@ -284,24 +498,79 @@ func (c *IMDbClient) RatingsGet() ([]entities.IMDbItem, error) {
 	return readIMDbRatingsResponse(response)
 }
 
+type imdbGraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type imdbGraphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// RatingsAdd rates the items on imdb with their ratings, one at a time, since the graphql mutation rates a single title
+func (c *IMDbClient) RatingsAdd(ctx context.Context, items []entities.IMDbItem) error {
+	if c.config.IsAuthless() {
+		return errAuthRequired("adding imdb ratings")
+	}
+	for _, item := range items {
+		if item.Rating == nil {
+			return fmt.Errorf("failure adding imdb rating for %s: the item has no rating", item.ID)
+		}
+		body, err := json.Marshal(imdbGraphQLRequest{
+			Query: imdbMutationRateTitle,
+			Variables: map[string]any{
+				"rating":  *item.Rating,
+				"titleId": item.ID,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failure encoding imdb rating for %s: %w", item.ID, err)
+		}
+		response, err := c.doRequest(ctx, requestFields{
+			Method:   http.MethodPost,
+			BasePath: c.config.graphqlPath,
+			Endpoint: "/",
+			Body:     ReusableReader(bytes.NewReader(body)),
+			Headers: map[string]string{
+				imdbHeaderKeyContentType: "application/json",
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failure adding imdb rating for %s: %w", item.ID, err)
+		}
+		result, err := decodeReader[imdbGraphQLResponse](response.Body)
+		if err != nil {
+			return fmt.Errorf("failure decoding imdb rating response for %s: %w", item.ID, err)
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("failure adding imdb rating for %s: %s", item.ID, result.Errors[0].Message)
+		}
+		c.logger.Debug(fmt.Sprintf("rated imdb item %s with %d", item.ID, *item.Rating))
+	}
+	return nil
+}
+
 func readIMDbListResponse(response *http.Response, listID string) (*entities.IMDbList, error) {
 	defer response.Body.Close()
-	csvReader := csv.NewReader(response.Body)
-	csvReader.LazyQuotes = true
-	csvReader.FieldsPerRecord = -1
-	csvData, err := csvReader.ReadAll()
+	body, isHTML := sniffIMDbHTML(response)
+	if isHTML {
+		listName, listItems, err := readIMDbListHTML(body)
+		if err != nil {
+			return nil, fmt.Errorf("failure reading from imdb response: %w", err)
+		}
+		return &entities.IMDbList{
+			ListName:  listName,
+			ListID:    listID,
+			ListItems: listItems,
+			Partial:   true,
+		}, nil
+	}
+	listItems, err := readIMDbListCSV(body)
 	if err != nil {
 		return nil, fmt.Errorf("failure reading from imdb response: %w", err)
 	}
-	var listItems []entities.IMDbItem
-	for i, record := range csvData {
-		if i > 0 { // omit header line
-			listItems = append(listItems, entities.IMDbItem{
-				ID:        record[1],
-				TitleType: record[7],
-			})
-		}
-	}
 	contentDispositionHeader := response.Header.Get(imdbHeaderKeyContentDisposition)
 	if contentDispositionHeader == "" {
 		return nil, fmt.Errorf("failure reading header %s from imdb response", imdbHeaderKeyContentDisposition)
@@ -318,37 +587,245 @@ func readIMDbListResponse(response *http.Response, listID string) (*entities.IMD
 	}, nil
 }
 
+func readIMDbListCSV(r io.Reader) ([]entities.IMDbItem, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+	csvData, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failure reading imdb list csv: %w", err)
+	}
+	columns, err := validateIMDbExport(csvData, "list", imdbColumnNameID)
+	if err != nil {
+		return nil, err
+	}
+	var listItems []entities.IMDbItem
+	for _, record := range csvData[1:] {
+		item := entities.IMDbItem{
+			ID:          columns.value(record, imdbColumnNameID),
+			Title:       columns.value(record, imdbColumnNameTitle),
+			TitleType:   columns.value(record, imdbColumnNameTitleType),
+			Description: columns.value(record, imdbColumnNameDescription),
+			Year:        parseIMDbYear(columns.value(record, imdbColumnNameYear)),
+			Genres:      parseIMDbGenres(columns.value(record, imdbColumnNameGenres)),
+		}
+		if value := columns.value(record, imdbColumnNameCreated); value != "" {
+			created, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return nil, fmt.Errorf("failure parsing imdb list item created date: %w", err)
+			}
+			item.Created = &created
+		}
+		listItems = append(listItems, item)
+	}
+	return listItems, nil
+}
+
 func readIMDbRatingsResponse(response *http.Response) ([]entities.IMDbItem, error) {
 	defer response.Body.Close()
-	csvReader := csv.NewReader(response.Body)
+	body, isHTML := sniffIMDbHTML(response)
+	if isHTML {
+		return nil, fmt.Errorf("imdb ratings export was served as html, which lacks the rating dates, hence the ratings can't be synced from it")
+	}
+	ratings, err := readIMDbRatingsCSV(body)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading from imdb response: %w", err)
+	}
+	return ratings, nil
+}
+
+func readIMDbRatingsCSV(r io.Reader) ([]entities.IMDbItem, error) {
+	csvReader := csv.NewReader(r)
 	csvReader.LazyQuotes = true
 	csvReader.FieldsPerRecord = -1
 	csvData, err := csvReader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failure reading from imdb response: %w", err)
+		return nil, fmt.Errorf("failure reading imdb ratings csv: %w", err)
+	}
+	columns, err := validateIMDbExport(csvData, "ratings", imdbColumnNameID, imdbColumnNameRating, imdbColumnNameDateRated)
+	if err != nil {
+		return nil, err
 	}
 	var ratings []entities.IMDbItem
-	for i, record := range csvData {
-		if i > 0 {
-			rating, err := strconv.Atoi(record[1])
-			if err != nil {
-				return nil, fmt.Errorf("failure parsing imdb rating value to integer: %w", err)
-			}
-			ratingDate, err := time.Parse(time.DateOnly, record[2])
-			if err != nil {
-				return nil, fmt.Errorf("failure parsing imdb rating date: %w", err)
-			}
-			ratings = append(ratings, entities.IMDbItem{
-				ID:         record[0],
-				TitleType:  record[5],
-				Rating:     &rating,
-				RatingDate: &ratingDate,
-			})
+	for _, record := range csvData[1:] {
+		rating, err := strconv.Atoi(columns.value(record, imdbColumnNameRating))
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing imdb rating value to integer: %w", err)
 		}
+		ratingDate, err := time.Parse(time.DateOnly, columns.value(record, imdbColumnNameDateRated))
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing imdb rating date: %w", err)
+		}
+		ratings = append(ratings, entities.IMDbItem{
+			ID:         columns.value(record, imdbColumnNameID),
+			Title:      columns.value(record, imdbColumnNameTitle),
+			TitleType:  columns.value(record, imdbColumnNameTitleType),
+			Year:       parseIMDbYear(columns.value(record, imdbColumnNameYear)),
+			Genres:     parseIMDbGenres(columns.value(record, imdbColumnNameGenres)),
+			Review:     columns.value(record, imdbColumnNameReview),
+			Rating:     &rating,
+			RatingDate: &ratingDate,
+		})
 	}
 	return ratings, nil
 }
 
+// sniffIMDbHTML reports whether the body of an imdb export is html rather than csv, going by the content type header and the first bytes of the body
+// the returned reader yields the whole body, including the bytes sniffed
+func sniffIMDbHTML(response *http.Response) (io.Reader, bool) {
+	reader := bufio.NewReaderSize(response.Body, imdbSniffLength)
+	if mediaType, _, err := mime.ParseMediaType(response.Header.Get(imdbHeaderKeyContentType)); err == nil && mediaType == "text/html" {
+		return reader, true
+	}
+	head, _ := reader.Peek(imdbSniffLength)
+	return reader, strings.HasPrefix(http.DetectContentType(head), "text/html")
+}
+
+// isIMDbListPage reports whether the html is the page of an imdb list, as opposed to a challenge or an error page
+func isIMDbListPage(r io.Reader) bool {
+	doc, err := goquery.NewDocumentFromReader(r)
+	return err == nil && doc.Find(imdbSelectorListTitle).Length() > 0
+}
+
+// readIMDbListHTML extracts the name and the items of a list from its html page, which imdb serves in place of the csv export at times
+// the page lacks some of the export columns, e.g. the genres and the created dates, which are left empty
+func readIMDbListHTML(r io.Reader) (string, []entities.IMDbItem, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return "", nil, fmt.Errorf("failure creating goquery document from imdb response: %w", err)
+	}
+	title := doc.Find(imdbSelectorListTitle).First()
+	if title.Length() == 0 {
+		return "", nil, fmt.Errorf("imdb list page is missing the list title, it was most likely not a list page")
+	}
+	var (
+		listItems []entities.IMDbItem
+		itemErr   error
+	)
+	doc.Find(imdbSelectorListItem).EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		href, _ := selection.Find(imdbSelectorListItemLink).Attr("href")
+		id, err := extractTitleID(href)
+		if err != nil {
+			itemErr = fmt.Errorf("failure extracting imdb id of list page item %d: %w", i+1, err)
+			return false
+		}
+		titleType := strings.TrimSpace(selection.Find(imdbSelectorListItemType).First().Text())
+		if titleType == "" {
+			// the page only labels the titles other than movies with their type
+			titleType = imdbItemTypeMovie
+		}
+		year := strings.TrimSpace(selection.Find(imdbSelectorListItemMetadata).First().Text())
+		listItems = append(listItems, entities.IMDbItem{
+			ID:        id,
+			Title:     trimIMDbListPosition(selection.Find(imdbSelectorListItemTitle).First().Text()),
+			TitleType: titleType,
+			Year:      parseIMDbYear(year[:min(len(year), 4)]),
+		})
+		return true
+	})
+	if itemErr != nil {
+		return "", nil, itemErr
+	}
+	return strings.TrimSpace(title.Text()), listItems, nil
+}
+
+// trimIMDbListPosition trims the position the list page prefixes the titles with, e.g. "1. Dunkirk"
+func trimIMDbListPosition(title string) string {
+	title = strings.TrimSpace(title)
+	position, rest, found := strings.Cut(title, ". ")
+	if _, err := strconv.Atoi(position); !found || err != nil {
+		return title
+	}
+	return rest
+}
+
+// imdbColumns maps the normalised names of the columns of an imdb export to their position
+// imdb added and reordered columns over time, hence the columns are looked up by name rather than position
+type imdbColumns map[string]int
+
+func newIMDbColumns(header []string) imdbColumns {
+	columns := make(imdbColumns, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		if key := normaliseIMDbColumn(name); key != "" {
+			if _, found := columns[key]; !found {
+				columns[key] = i
+			}
+		}
+	}
+	return columns
+}
+
+func normaliseIMDbColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (c imdbColumns) index(name string) (int, bool) {
+	i, found := c[normaliseIMDbColumn(name)]
+	return i, found
+}
+
+// value returns the value of a column of the record, or an empty string when the export lacks the column
+func (c imdbColumns) value(record []string, name string) string {
+	i, found := c.index(name)
+	if !found || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// validateIMDbExport rejects exports which imdb failed to generate, since syncing them as if they had no items would remove everything from trakt
+// an export containing only the header row is valid, it belongs to an empty list
+func validateIMDbExport(csvData [][]string, export string, requiredColumns ...string) (imdbColumns, error) {
+	if len(csvData) == 0 {
+		return nil, fmt.Errorf("imdb %s export is empty, it was most likely not generated correctly", export)
+	}
+	columns := newIMDbColumns(csvData[0])
+	if _, found := columns.index(imdbColumnNameID); !found {
+		return nil, fmt.Errorf("imdb %s export is missing the header row, it was most likely not generated correctly", export)
+	}
+	minColumns := 0
+	for _, name := range requiredColumns {
+		i, found := columns.index(name)
+		if !found {
+			return nil, fmt.Errorf("imdb %s export is missing the required column %q", export, name)
+		}
+		minColumns = max(minColumns, i+1)
+	}
+	for i, record := range csvData[1:] {
+		if len(record) < minColumns {
+			return nil, fmt.Errorf("imdb %s export row %d has %d columns, expected at least %d", export, i+1, len(record), minColumns)
+		}
+	}
+	return columns, nil
+}
+
+// parseIMDbGenres splits the comma separated genres of an imdb export, e.g. "Action, Drama"
+func parseIMDbGenres(value string) []string {
+	var genres []string
+	for _, genre := range strings.Split(value, ",") {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+	return genres
+}
+
+func errAuthRequired(operation string) error {
+	return fmt.Errorf("%s requires imdb authentication, which is disabled via config field 'IMDB_AUTH'", operation)
+}
+
+// extractTitleID extracts the imdb id from the href of a title, e.g. "/title/tt5013056/?ref_=ls_t_1"
+func extractTitleID(href string) (string, error) {
+	pieces := strings.Split(href, "/")
+	if len(pieces) < 3 || pieces[1] != "title" || !strings.HasPrefix(pieces[2], "tt") {
+		return "", fmt.Errorf("imdb title href has unexpected format: %s", href)
+	}
+	return pieces[2], nil
+}
+
 func extractListID(href string) (string, error) {
 	pieces := strings.Split(href, "/")
 	if len(pieces) < 3 {
@@ -356,3 +833,12 @@ func extractListID(href string) (string, error) {
 	}
 	return pieces[2], nil
 }
+
+// parseIMDbYear returns the release year of an item, or 0 when the export doesn't provide one
+func parseIMDbYear(value string) int {
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return year
+}
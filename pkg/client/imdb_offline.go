@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+const (
+	imdbExportExtension     = ".csv"
+	imdbExportFileRatings   = "ratings" + imdbExportExtension
+	imdbExportFileWatchlist = "watchlist" + imdbExportExtension
+	imdbOfflineWatchlistID  = "watchlist"
+)
+
+// IMDbOfflineClient reads pre-downloaded imdb csv exports from a directory instead of scraping imdb.
+// Every csv file other than the ratings and watchlist exports is treated as a list, with the file name
+// (without extension) used as both the list id and the list name.
+type IMDbOfflineClient struct {
+	dir    string
+	logger *slog.Logger
+}
+
+// NewIMDbOfflineClient accepts the http config only to match the signature of NewIMDbClient, since it doesn't send any http requests
+func NewIMDbOfflineClient(conf appconfig.IMDb, _ appconfig.HTTP, logger *slog.Logger) (IMDbClientInterface, error) {
+	if !conf.IsOffline() {
+		return nil, fmt.Errorf("imdb exports directory is not configured")
+	}
+	return &IMDbOfflineClient{
+		dir:    *conf.ExportsDir,
+		logger: logger,
+	}, nil
+}
+
+func (c *IMDbOfflineClient) Hydrate(ctx context.Context) error {
+	info, err := os.Stat(c.dir)
+	if err != nil {
+		return fmt.Errorf("failure reading imdb exports directory %s: %w", c.dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("imdb exports path %s is not a directory", c.dir)
+	}
+	return nil
+}
+
+func (c *IMDbOfflineClient) ListGet(ctx context.Context, listID string) (*entities.IMDbList, error) {
+	f, err := os.Open(filepath.Join(c.dir, listID+imdbExportExtension))
+	if err != nil {
+		return nil, fmt.Errorf("failure opening imdb list export %s: %w", listID, err)
+	}
+	defer f.Close()
+	listItems, err := readIMDbListCSV(f)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.IMDbList{
+		ListName:  listID,
+		ListID:    listID,
+		ListItems: listItems,
+	}, nil
+}
+
+// ListsGet reads the exported lists, delegating the errors of lists that can't be read to the caller
+func (c *IMDbOfflineClient) ListsGet(ctx context.Context, listIDs []string) ([]entities.IMDbList, []error) {
+	var (
+		lists           = make([]entities.IMDbList, 0, len(listIDs))
+		delegatedErrors = make([]error, 0, len(listIDs))
+	)
+	for _, listID := range listIDs {
+		imdbList, err := c.ListGet(ctx, listID)
+		if err != nil {
+			delegatedErrors = append(delegatedErrors, &IMDbListError{
+				ListID: listID,
+				err:    err,
+			})
+			continue
+		}
+		lists = append(lists, *imdbList)
+	}
+	return lists, delegatedErrors
+}
+
+func (c *IMDbOfflineClient) ListsGetAll(ctx context.Context) ([]entities.IMDbList, []error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failure reading imdb exports directory %s: %w", c.dir, err)}
+	}
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != imdbExportExtension || name == imdbExportFileRatings || name == imdbExportFileWatchlist {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, imdbExportExtension))
+	}
+	return c.ListsGet(ctx, ids)
+}
+
+// ListDescriptionGet returns an empty description, the list exports don't include it
+func (c *IMDbOfflineClient) ListDescriptionGet(ctx context.Context, listID string) (string, error) {
+	return "", nil
+}
+
+func (c *IMDbOfflineClient) WatchlistGet(ctx context.Context) (*entities.IMDbList, error) {
+	list, err := c.ListGet(ctx, imdbOfflineWatchlistID)
+	if err != nil {
+		return nil, err
+	}
+	list.IsWatchlist = true
+	return list, nil
+}
+
+func (c *IMDbOfflineClient) RatingsGet(ctx context.Context) ([]entities.IMDbItem, error) {
+	f, err := os.Open(filepath.Join(c.dir, imdbExportFileRatings))
+	if err != nil {
+		return nil, fmt.Errorf("failure opening imdb ratings export: %w", err)
+	}
+	defer f.Close()
+	return readIMDbRatingsCSV(f)
+}
+
+// RatingsAdd fails, since the exports are only ever read
+func (c *IMDbOfflineClient) RatingsAdd(ctx context.Context, items []entities.IMDbItem) error {
+	return fmt.Errorf("adding imdb ratings is not supported when reading imdb exports from directory %s", c.dir)
+}
+
+func (c *IMDbOfflineClient) UserIDScrape(ctx context.Context) error {
+	return nil
+}
+
+func (c *IMDbOfflineClient) WatchlistIDScrape(ctx context.Context) error {
+	return nil
+}
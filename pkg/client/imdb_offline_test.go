@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func populateExportsDir(requirements *require.Assertions, dir string, files map[string]string) {
+	for name, source := range files {
+		data, err := os.ReadFile(source)
+		requirements.NoError(err)
+		requirements.NoError(os.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+}
+
+func TestNewIMDbOfflineClient(t *testing.T) {
+	type args struct {
+		config appconfig.IMDb
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, IMDbClientInterface, error)
+	}{
+		{
+			name: "successfully create client",
+			args: args{
+				config: appconfig.IMDb{
+					ExportsDir: stringPointer("exports"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, client IMDbClientInterface, err error) {
+				assertions.NoError(err)
+				offlineClient, ok := client.(*IMDbOfflineClient)
+				assertions.True(ok)
+				assertions.Equal("exports", offlineClient.dir)
+			},
+		},
+		{
+			name: "handle missing exports directory",
+			args: args{
+				config: appconfig.IMDb{},
+			},
+			assertions: func(assertions *assert.Assertions, client IMDbClientInterface, err error) {
+				assertions.Nil(client)
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewIMDbOfflineClient(tt.args.config, appconfig.HTTP{}, logger.NewLogger(io.Discard))
+			tt.assertions(assert.New(t), client, err)
+		})
+	}
+}
+
+func TestIMDbOfflineClient_Hydrate(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions, string) string
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully hydrate client",
+			requirements: func(requirements *require.Assertions, dir string) string {
+				return dir
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "handle missing directory",
+			requirements: func(requirements *require.Assertions, dir string) string {
+				return filepath.Join(dir, "missing")
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+			},
+		},
+		{
+			name: "handle path that is not a directory",
+			requirements: func(requirements *require.Assertions, dir string) string {
+				path := filepath.Join(dir, "file")
+				requirements.NoError(os.WriteFile(path, nil, 0644))
+				return path
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &IMDbOfflineClient{
+				dir:    tt.requirements(require.New(t), t.TempDir()),
+				logger: logger.NewLogger(io.Discard),
+			}
+			tt.assertions(assert.New(t), c.Hydrate(context.Background()))
+		})
+	}
+}
+
+func TestIMDbOfflineClient_ListsGetAll(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions, string)
+		assertions   func(*assert.Assertions, []entities.IMDbList, []error)
+	}{
+		{
+			name: "successfully read all lists",
+			requirements: func(requirements *require.Assertions, dir string) {
+				populateExportsDir(requirements, dir, map[string]string{
+					"Watched.csv":           "testdata/imdb_list.csv",
+					imdbExportFileWatchlist: "testdata/imdb_list.csv",
+					imdbExportFileRatings:   "testdata/imdb_ratings.csv",
+				})
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
+				assertions.Empty(errs)
+				assertions.Len(lists, 1)
+				assertions.Equal("Watched", lists[0].ListID)
+				assertions.Equal("Watched", lists[0].ListName)
+				assertions.Len(lists[0].ListItems, 3)
+			},
+		},
+		{
+			name: "handle missing directory",
+			requirements: func(requirements *require.Assertions, dir string) {
+				requirements.NoError(os.Remove(dir))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
+				assertions.Nil(lists)
+				assertions.Len(errs, 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.requirements(require.New(t), dir)
+			c := &IMDbOfflineClient{
+				dir:    dir,
+				logger: logger.NewLogger(io.Discard),
+			}
+			lists, errs := c.ListsGetAll(context.Background())
+			tt.assertions(assert.New(t), lists, errs)
+		})
+	}
+}
+
+func TestIMDbOfflineClient_ListsGet(t *testing.T) {
+	type args struct {
+		listIDs []string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func(*require.Assertions, string)
+		assertions   func(*assert.Assertions, []entities.IMDbList, []error)
+	}{
+		{
+			name: "successfully read lists and delegate missing ones",
+			args: args{
+				listIDs: []string{"Watched", "Missing"},
+			},
+			requirements: func(requirements *require.Assertions, dir string) {
+				populateExportsDir(requirements, dir, map[string]string{
+					"Watched.csv": "testdata/imdb_list.csv",
+				})
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
+				assertions.Len(lists, 1)
+				assertions.Equal("Watched", lists[0].ListID)
+				assertions.Len(errs, 1)
+				var listErr *IMDbListError
+				assertions.ErrorAs(errs[0], &listErr)
+				assertions.Equal("Missing", listErr.ListID)
+				assertions.ErrorIs(errs[0], fs.ErrNotExist)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.requirements(require.New(t), dir)
+			c := &IMDbOfflineClient{
+				dir:    dir,
+				logger: logger.NewLogger(io.Discard),
+			}
+			lists, errs := c.ListsGet(context.Background(), tt.args.listIDs)
+			tt.assertions(assert.New(t), lists, errs)
+		})
+	}
+}
+
+func TestIMDbOfflineClient_WatchlistGet(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions, string)
+		assertions   func(*assert.Assertions, *entities.IMDbList, error)
+	}{
+		{
+			name: "successfully read watchlist",
+			requirements: func(requirements *require.Assertions, dir string) {
+				populateExportsDir(requirements, dir, map[string]string{
+					imdbExportFileWatchlist: "testdata/imdb_list.csv",
+				})
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.True(list.IsWatchlist)
+				assertions.Equal(imdbOfflineWatchlistID, list.ListID)
+				assertions.Len(list.ListItems, 3)
+			},
+		},
+		{
+			name:         "handle missing watchlist export",
+			requirements: func(requirements *require.Assertions, dir string) {},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.requirements(require.New(t), dir)
+			c := &IMDbOfflineClient{
+				dir:    dir,
+				logger: logger.NewLogger(io.Discard),
+			}
+			list, err := c.WatchlistGet(context.Background())
+			tt.assertions(assert.New(t), list, err)
+		})
+	}
+}
+
+func TestIMDbOfflineClient_RatingsGet(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions, string)
+		assertions   func(*assert.Assertions, []entities.IMDbItem, error)
+	}{
+		{
+			name: "successfully read ratings",
+			requirements: func(requirements *require.Assertions, dir string) {
+				populateExportsDir(requirements, dir, map[string]string{
+					imdbExportFileRatings: "testdata/imdb_ratings.csv",
+				})
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.NoError(err)
+				assertions.Len(ratings, 3)
+				assertions.Equal("tt5013056", ratings[0].ID)
+			},
+		},
+		{
+			name:         "handle missing ratings export",
+			requirements: func(requirements *require.Assertions, dir string) {},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.requirements(require.New(t), dir)
+			c := &IMDbOfflineClient{
+				dir:    dir,
+				logger: logger.NewLogger(io.Discard),
+			}
+			ratings, err := c.RatingsGet(context.Background())
+			tt.assertions(assert.New(t), ratings, err)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"context"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,12 +12,14 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
@@ -76,6 +80,49 @@ func TestIMDbClient_doRequest(t *testing.T) {
 				assertions.ErrorContains(err, "failure creating http request")
 			},
 		},
+		{
+			name: "handle unauthorized status",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var authErr *IMDbAuthExpiredError
+				assertions.True(errors.As(err, &authErr))
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+				assertions.ErrorContains(err, "IMDB_COOKIEATMAIN")
+			},
+		},
+		{
+			name: "handle redirect to sign in page",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == imdbPathSignIn {
+						w.WriteHeader(http.StatusOK)
+						return
+					}
+					http.Redirect(w, r, imdbPathSignIn, http.StatusFound)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var authErr *IMDbAuthExpiredError
+				assertions.True(errors.As(err, &authErr))
+				assertions.ErrorContains(err, "redirected to the sign in page")
+			},
+		},
 		{
 			name: "handle unexpected status",
 			args: args{
@@ -97,6 +144,75 @@ func TestIMDbClient_doRequest(t *testing.T) {
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
+		{
+			name: "retry once after a challenge",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				var requests int
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					if requests++; requests == 1 {
+						w.Header().Set(imdbHeaderKeyWAFAction, "challenge")
+						w.WriteHeader(http.StatusAccepted)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.NoError(err)
+				assertions.Equal(http.StatusOK, res.StatusCode)
+			},
+		},
+		{
+			name: "handle html in place of a csv export",
+			args: args{
+				requestFields: requestFields{
+					Method:   http.MethodGet,
+					Endpoint: "/list/ls000000000/export",
+					Body:     http.NoBody,
+				},
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(imdbHeaderKeyContentType, "text/html; charset=utf-8")
+					w.WriteHeader(http.StatusOK)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var challengeErr *IMDbChallengeError
+				assertions.True(errors.As(err, &challengeErr))
+				assertions.ErrorContains(err, "anti-bot challenge")
+			},
+		},
+		{
+			name: "handle list page in place of a csv export",
+			args: args{
+				requestFields: requestFields{
+					Method:   http.MethodGet,
+					Endpoint: "/list/ls000000000/export",
+					Body:     http.NoBody,
+				},
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(imdbHeaderKeyContentType, "text/html; charset=utf-8")
+					requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_list.html"))
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.NoError(err)
+				defer res.Body.Close()
+				body, err := io.ReadAll(res.Body)
+				assertions.NoError(err)
+				assertions.Equal(dummyIMDbListHTML, string(body))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,19 +221,25 @@ func TestIMDbClient_doRequest(t *testing.T) {
 			tt.args.requestFields.BasePath = testServer.URL
 			c := &IMDbClient{
 				client: http.DefaultClient,
+				clock:  clock.NewFake(time.Time{}),
+				logger: logger.NewLogger(io.Discard),
 			}
-			res, err := c.doRequest(tt.args.requestFields)
+			res, err := c.doRequest(context.Background(), tt.args.requestFields)
 			tt.assertions(assert.New(t), res, err)
 		})
 	}
 }
 
 func TestIMDbClient_ListGet(t *testing.T) {
+	type fields struct {
+		config appconfig.IMDb
+	}
 	type args struct {
 		listID string
 	}
 	tests := []struct {
 		name         string
+		fields       fields
 		args         args
 		requirements func(*require.Assertions) *httptest.Server
 		assertions   func(*assert.Assertions, *entities.IMDbList, error)
@@ -144,6 +266,9 @@ func TestIMDbClient_ListGet(t *testing.T) {
 				assertions.Equal("Watched (2023)", list.ListName)
 				assertions.Equal(3, len(list.ListItems))
 				assertions.Equal(false, list.IsWatchlist)
+				assertions.Equal("2023-08-03", list.ListItems[0].Created.Format(time.DateOnly))
+				assertions.Equal("Dunkirk", list.ListItems[0].Title)
+				assertions.Equal(2017, list.ListItems[0].Year)
 			},
 		},
 		{
@@ -164,6 +289,97 @@ func TestIMDbClient_ListGet(t *testing.T) {
 				assertions.Error(err)
 			},
 		},
+		{
+			name: "handle error when list is private and imdb auth is none",
+			fields: fields{
+				config: appconfig.IMDb{
+					Auth: func() *string {
+						s := appconfig.IMDbAuthNone
+						return &s
+					}(),
+				},
+			},
+			args: args{
+				listID: "ls123456789",
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodGet, r.Method)
+					requirements.Equal("/list/ls123456789/export", r.URL.Path)
+					w.WriteHeader(http.StatusForbidden)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				var privateErr *IMDbListPrivateError
+				assertions.ErrorAs(err, &privateErr)
+				assertions.Equal("ls123456789", privateErr.ListID)
+				assertions.ErrorContains(err, "imdb list ls123456789 is private")
+			},
+		},
+		{
+			name: "handle error when list is not found and imdb auth is none",
+			fields: fields{
+				config: appconfig.IMDb{
+					Auth: func() *string {
+						s := appconfig.IMDbAuthNone
+						return &s
+					}(),
+				},
+			},
+			args: args{
+				listID: "ls123456789",
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				var privateErr *IMDbListPrivateError
+				assertions.ErrorAs(err, &privateErr)
+				assertions.ErrorContains(err, "could not be found")
+			},
+		},
+		{
+			name: "successfully get private list with imdb auth cookies",
+			fields: fields{
+				config: appconfig.IMDb{
+					CookieAtMain: func() *string {
+						s := "at-main-value"
+						return &s
+					}(),
+					CookieUbidMain: func() *string {
+						s := "ubid-main-value"
+						return &s
+					}(),
+				},
+			},
+			args: args{
+				listID: "ls123456789",
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					cookie, err := r.Cookie(imdbCookieNameAtMain)
+					if err != nil || cookie.Value != "at-main-value" {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					w.Header().Set(imdbHeaderKeyContentDisposition, `attachment; filename="Private.csv"`)
+					w.WriteHeader(http.StatusOK)
+					requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_list.csv"))
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.Equal("Private", list.ListName)
+				assertions.Len(list.ListItems, 3)
+			},
+		},
 		{
 			name: "handle unexpected status",
 			args: args{
@@ -187,13 +403,24 @@ func TestIMDbClient_ListGet(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			testServer := tt.requirements(require.New(t))
 			defer testServer.Close()
+			config := imdbConfig{
+				IMDb:     tt.fields.config,
+				basePath: testServer.URL,
+			}
+			client := http.DefaultClient
+			if config.CookieAtMain != nil {
+				jar, err := setupCookieJar(config)
+				require.NoError(t, err)
+				client = &http.Client{
+					Jar: jar,
+				}
+			}
 			c := &IMDbClient{
-				client: http.DefaultClient,
-				config: imdbConfig{
-					basePath: testServer.URL,
-				},
+				client: client,
+				config: config,
+				logger: logger.NewLogger(io.Discard),
 			}
-			list, err := c.ListGet(tt.args.listID)
+			list, err := c.ListGet(context.Background(), tt.args.listID)
 			tt.assertions(assert.New(t), list, err)
 		})
 	}
@@ -252,18 +479,92 @@ func TestIMDbClient_WatchlistGet(t *testing.T) {
 					basePath:    testServer.URL,
 					watchlistID: "ls123456789",
 				},
+				logger: logger.NewLogger(io.Discard),
 			}
-			list, err := c.WatchlistGet()
+			list, err := c.WatchlistGet(context.Background())
 			tt.assertions(assert.New(t), list, err)
 		})
 	}
 }
 
+func TestIMDbClient_ListDescriptionGet(t *testing.T) {
+	listID := "ls123456789"
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions) *httptest.Server
+		assertions   func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "successfully scrape list description",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodGet, r.Method)
+					requirements.Equal("/list/"+listID+"/", r.URL.Path)
+					w.WriteHeader(http.StatusOK)
+					bytes, err := w.Write([]byte(`<div data-testid="list-description"> Movies I keep coming back to </div>`))
+					requirements.Greater(bytes, 0)
+					requirements.NoError(err)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, description string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("Movies I keep coming back to", description)
+			},
+		},
+		{
+			name: "list without description",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodGet, r.Method)
+					requirements.Equal("/list/"+listID+"/", r.URL.Path)
+					w.WriteHeader(http.StatusOK)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, description string, err error) {
+				assertions.NoError(err)
+				assertions.Empty(description)
+			},
+		},
+		{
+			name: "handle unexpected status",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodGet, r.Method)
+					requirements.Equal("/list/"+listID+"/", r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, description string, err error) {
+				assertions.Error(err)
+				assertions.Empty(description)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := tt.requirements(require.New(t))
+			defer testServer.Close()
+			c := &IMDbClient{
+				client: http.DefaultClient,
+				config: imdbConfig{
+					basePath: testServer.URL,
+				},
+				logger: logger.NewLogger(io.Discard),
+			}
+			description, err := c.ListDescriptionGet(context.Background(), listID)
+			tt.assertions(assert.New(t), description, err)
+		})
+	}
+}
+
 func TestIMDbClient_ListsGetAll(t *testing.T) {
 	tests := []struct {
 		name         string
 		requirements func(*require.Assertions) *httptest.Server
-		assertions   func(*assert.Assertions, []entities.IMDbList, error)
+		assertions   func(*assert.Assertions, []entities.IMDbList, []error)
 	}{
 		{
 			name: "successfully get all lists",
@@ -286,9 +587,9 @@ func TestIMDbClient_ListsGetAll(t *testing.T) {
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.NotNil(lists)
-				assertions.NoError(err)
+				assertions.Empty(errs)
 				assertions.Equal(2, len(lists))
 				sort.Slice(lists, func(a, b int) bool {
 					return lists[a].ListID < lists[b].ListID
@@ -297,6 +598,44 @@ func TestIMDbClient_ListsGetAll(t *testing.T) {
 				assertions.Equal("ls987654321", lists[1].ListID)
 			},
 		},
+		{
+			name: "successfully get all lists across pages",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodGet, r.Method)
+					w.Header().Set(imdbHeaderKeyContentDisposition, `attachment; filename="DummyList.csv"`)
+					if r.URL.Path != "/user/ur12345678/lists" {
+						w.WriteHeader(http.StatusOK)
+						requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_list.csv"))
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					switch r.URL.Query().Get("page") {
+					case "1":
+						requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_lists.html"))
+					case "2":
+						requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_lists_page2.html"))
+					default:
+						_, err := w.Write([]byte("<ul></ul>"))
+						requirements.NoError(err)
+					}
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
+				assertions.Empty(errs)
+				assertions.Equal(3, len(lists))
+				sort.Slice(lists, func(a, b int) bool {
+					return lists[a].ListID < lists[b].ListID
+				})
+				assertions.Equal("ls123456789", lists[0].ListID)
+				assertions.Equal("ls555555555", lists[1].ListID)
+				assertions.Equal("ls987654321", lists[2].ListID)
+				for _, list := range lists {
+					assertions.Equal(3, len(list.ListItems))
+				}
+			},
+		},
 		{
 			name: "fail to get all lists",
 			requirements: func(requirements *require.Assertions) *httptest.Server {
@@ -307,9 +646,9 @@ func TestIMDbClient_ListsGetAll(t *testing.T) {
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.Nil(lists)
-				assertions.Error(err)
+				assertions.Len(errs, 1)
 			},
 		},
 		{
@@ -325,9 +664,9 @@ func TestIMDbClient_ListsGetAll(t *testing.T) {
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.Nil(lists)
-				assertions.Error(err)
+				assertions.Len(errs, 1)
 			},
 		},
 	}
@@ -343,8 +682,8 @@ func TestIMDbClient_ListsGetAll(t *testing.T) {
 				},
 				logger: logger.NewLogger(io.Discard),
 			}
-			lists, err := c.ListsGetAll()
-			tt.assertions(assert.New(t), lists, err)
+			lists, errs := c.ListsGetAll(context.Background())
+			tt.assertions(assert.New(t), lists, errs)
 		})
 	}
 }
@@ -357,7 +696,7 @@ func TestIMDbClient_ListsGet(t *testing.T) {
 		name         string
 		args         args
 		requirements func(*require.Assertions) *httptest.Server
-		assertions   func(*assert.Assertions, []entities.IMDbList, error)
+		assertions   func(*assert.Assertions, []entities.IMDbList, []error)
 	}{
 		{
 			name: "successfully get lists",
@@ -381,9 +720,9 @@ func TestIMDbClient_ListsGet(t *testing.T) {
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.NotNil(lists)
-				assertions.NoError(err)
+				assertions.Empty(errs)
 				assertions.Equal(2, len(lists))
 				sort.Slice(lists, func(a, b int) bool {
 					return lists[a].ListID < lists[b].ListID
@@ -393,7 +732,7 @@ func TestIMDbClient_ListsGet(t *testing.T) {
 			},
 		},
 		{
-			name: "silently ignore lists that could not be found",
+			name: "delegate error when list could not be found",
 			args: args{
 				listIDs: []string{
 					"ls123456789",
@@ -407,30 +746,66 @@ func TestIMDbClient_ListsGet(t *testing.T) {
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.NotNil(lists)
-				assertions.NoError(err)
 				assertions.Equal(0, len(lists))
+				assertions.Len(errs, 1)
+				var listErr *IMDbListError
+				assertions.ErrorAs(errs[0], &listErr)
+				assertions.Equal("ls123456789", listErr.ListID)
+				var apiError *ApiError
+				assertions.ErrorAs(errs[0], &apiError)
+				assertions.Equal(http.StatusNotFound, apiError.StatusCode)
 			},
 		},
 		{
-			name: "handle unexpected error when getting lists",
+			name: "get the other lists when one list fails",
 			args: args{
 				listIDs: []string{
 					"ls123456789",
+					"ls987654321",
 				},
 			},
 			requirements: func(requirements *require.Assertions) *httptest.Server {
 				handler := func(w http.ResponseWriter, r *http.Request) {
 					requirements.Equal(http.MethodGet, r.Method)
-					requirements.Equal("/list/ls123456789/export", r.URL.Path)
-					w.WriteHeader(http.StatusInternalServerError)
+					if r.URL.Path == "/list/ls123456789/export" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.Header().Set(imdbHeaderKeyContentDisposition, `attachment; filename="DummyList.csv"`)
+					w.WriteHeader(http.StatusOK)
+					requirements.NoError(populateHttpResponseWithFileContents(w, "testdata/imdb_list.csv"))
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
+				assertions.Len(lists, 1)
+				assertions.Equal("ls987654321", lists[0].ListID)
+				assertions.Len(errs, 1)
+				var listErr *IMDbListError
+				assertions.ErrorAs(errs[0], &listErr)
+				assertions.Equal("ls123456789", listErr.ListID)
+			},
+		},
+		{
+			name: "abort when imdb cookies have expired",
+			args: args{
+				listIDs: []string{
+					"ls123456789",
+				},
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
 				}
 				return httptest.NewServer(http.HandlerFunc(handler))
 			},
-			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, errs []error) {
 				assertions.Nil(lists)
-				assertions.Error(err)
+				assertions.Len(errs, 1)
+				var authExpiredErr *IMDbAuthExpiredError
+				assertions.ErrorAs(errs[0], &authExpiredErr)
 			},
 		},
 	}
@@ -446,8 +821,8 @@ func TestIMDbClient_ListsGet(t *testing.T) {
 				},
 				logger: logger.NewLogger(io.Discard),
 			}
-			lists, err := c.ListsGet(tt.args.listIDs)
-			tt.assertions(assert.New(t), lists, err)
+			lists, errs := c.ListsGet(context.Background(), tt.args.listIDs)
+			tt.assertions(assert.New(t), lists, errs)
 		})
 	}
 }
@@ -517,8 +892,9 @@ func TestIMDbClient_UserIDScrape(t *testing.T) {
 				config: imdbConfig{
 					basePath: testServer.URL,
 				},
+				logger: logger.NewLogger(io.Discard),
 			}
-			err := c.UserIDScrape()
+			err := c.UserIDScrape(context.Background())
 			tt.assertions(assert.New(t), c, err)
 		})
 	}
@@ -607,8 +983,9 @@ func TestIMDbClient_WatchlistIDScrape(t *testing.T) {
 				config: imdbConfig{
 					basePath: testServer.URL,
 				},
+				logger: logger.NewLogger(io.Discard),
 			}
-			err := c.WatchlistIDScrape()
+			err := c.WatchlistIDScrape(context.Background())
 			tt.assertions(assert.New(t), c, err)
 		})
 	}
@@ -638,6 +1015,9 @@ func TestIMDbClient_RatingsGet(t *testing.T) {
 				assertions.Equal("tt5013056", ratings[0].ID)
 				assertions.Equal("tt15398776", ratings[1].ID)
 				assertions.Equal("tt0172495", ratings[2].ID)
+				assertions.Equal("Dunkirk", ratings[0].Title)
+				assertions.Equal(2017, ratings[0].Year)
+				assertions.Equal([]string{"Action", "Drama", "History", "Thriller", "War"}, ratings[0].Genres)
 			},
 		},
 		{
@@ -666,8 +1046,9 @@ func TestIMDbClient_RatingsGet(t *testing.T) {
 					basePath: testServer.URL,
 					userID:   "ur12345678",
 				},
+				logger: logger.NewLogger(io.Discard),
 			}
-			ratings, err := c.RatingsGet()
+			ratings, err := c.RatingsGet(context.Background())
 			tt.assertions(assert.New(t), ratings, err)
 		})
 	}
@@ -676,6 +1057,9 @@ func TestIMDbClient_RatingsGet(t *testing.T) {
 //go:embed testdata/imdb_list.csv
 var dummyIMDbList string
 
+//go:embed testdata/imdb_list.html
+var dummyIMDbListHTML string
+
 func Test_readIMDbListResponse(t *testing.T) {
 	type args struct {
 		response *http.Response
@@ -704,7 +1088,83 @@ func Test_readIMDbListResponse(t *testing.T) {
 				assertions.Equal("ls123456789", list.ListID)
 				assertions.Equal("Watched (2023)", list.ListName)
 				assertions.Equal(3, len(list.ListItems))
+				assertions.Equal([]string{"Action", "Drama", "History", "Thriller", "War"}, list.ListItems[0].Genres)
 				assertions.Equal(false, list.IsWatchlist)
+				assertions.False(list.Partial)
+			},
+		},
+		{
+			name: "successfully read list response in the current layout",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentDisposition: []string{`attachment; filename="Watched (2023).csv"`},
+					},
+					Body: io.NopCloser(strings.NewReader(dummyIMDbListHeaderCurrent + "\n1,tt5013056,2023-08-03,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,\"Action, Drama\",718267,2017-07-13,Christopher Nolan,8,2017-12-25")),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.Len(list.ListItems, 1)
+				item := list.ListItems[0]
+				assertions.Equal("tt5013056", item.ID)
+				assertions.Equal("Dunkirk", item.Title)
+				assertions.Equal("Movie", item.TitleType)
+				assertions.Equal(2017, item.Year)
+				assertions.Equal([]string{"Action", "Drama"}, item.Genres)
+				assertions.Equal(time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), *item.Created)
+			},
+		},
+		{
+			name: "successfully read list response served as html",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentType: []string{"text/html; charset=utf-8"},
+					},
+					Body: io.NopCloser(strings.NewReader(dummyIMDbListHTML)),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.Equal("ls123456789", list.ListID)
+				assertions.Equal("Watched (2023)", list.ListName)
+				assertions.Len(list.ListItems, 3)
+				assertions.Equal(entities.IMDbItem{ID: "tt5013056", Title: "Dunkirk", TitleType: "Movie", Year: 2017}, list.ListItems[0])
+				assertions.Equal(entities.IMDbItem{ID: "tt0903747", Title: "Breaking Bad", TitleType: "TV Series", Year: 2008}, list.ListItems[1])
+				assertions.True(list.Partial)
+			},
+		},
+		{
+			name: "successfully read list response sniffed as html",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentType: []string{"application/octet-stream"},
+					},
+					Body: io.NopCloser(strings.NewReader(dummyIMDbListHTML)),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.Len(list.ListItems, 3)
+				assertions.Equal("tt0172495", list.ListItems[2].ID)
+			},
+		},
+		{
+			name: "handle error when html response is not a list page",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("<!DOCTYPE html><html><body><p>Something went wrong</p></body></html>")),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				assertions.ErrorContains(err, "imdb list page is missing the list title")
 			},
 		},
 		{
@@ -714,7 +1174,7 @@ func Test_readIMDbListResponse(t *testing.T) {
 					Header: http.Header{
 						imdbHeaderKeyContentDisposition: []string{`invalid media type`},
 					},
-					Body: http.NoBody,
+					Body: io.NopCloser(strings.NewReader(dummyIMDbList)),
 				},
 				listID: "ls123456789",
 			},
@@ -727,7 +1187,7 @@ func Test_readIMDbListResponse(t *testing.T) {
 			name: "handle error when content disposition header is missing",
 			args: args{
 				response: &http.Response{
-					Body: http.NoBody,
+					Body: io.NopCloser(strings.NewReader(dummyIMDbList)),
 				},
 				listID: "ls123456789",
 			},
@@ -736,6 +1196,55 @@ func Test_readIMDbListResponse(t *testing.T) {
 				assertions.Error(err)
 			},
 		},
+		{
+			name: "successfully read header-only list response",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentDisposition: []string{`attachment; filename="Empty.csv"`},
+					},
+					Body: io.NopCloser(strings.NewReader(strings.SplitN(dummyIMDbList, "\n", 2)[0] + "\n")),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(list)
+				assertions.Empty(list.ListItems)
+			},
+		},
+		{
+			name: "handle error when list export is empty",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentDisposition: []string{`attachment; filename="Watched (2023).csv"`},
+					},
+					Body: http.NoBody,
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				assertions.ErrorContains(err, "imdb list export is empty")
+			},
+		},
+		{
+			name: "handle error when list export is missing the header row",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentDisposition: []string{`attachment; filename="Watched (2023).csv"`},
+					},
+					Body: io.NopCloser(strings.NewReader(strings.SplitN(dummyIMDbList, "\n", 2)[1])),
+				},
+				listID: "ls123456789",
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.IMDbList, err error) {
+				assertions.Nil(list)
+				assertions.ErrorContains(err, "imdb list export is missing the header row")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -748,6 +1257,13 @@ func Test_readIMDbListResponse(t *testing.T) {
 //go:embed testdata/imdb_ratings.csv
 var dummyIMDbRatings string
 
+const (
+	dummyIMDbRatingsHeader = "Const,Your Rating,Date Rated,Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors"
+	// the current layout adds the original title column, shifting the columns after the title
+	dummyIMDbRatingsHeaderCurrent = "Const,Your Rating,Date Rated,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors"
+	dummyIMDbListHeaderCurrent    = "Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated"
+)
+
 func Test_readIMDbRatingsResponse(t *testing.T) {
 	type args struct {
 		response *http.Response
@@ -773,11 +1289,86 @@ func Test_readIMDbRatingsResponse(t *testing.T) {
 				assertions.Equal("tt0172495", ratings[2].ID)
 			},
 		},
+		{
+			name: "successfully read ratings response in the current layout",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader(dummyIMDbRatingsHeaderCurrent + "\ntt0903747,10,2024-05-01,Breaking Bad,Breaking Bad,https://www.imdb.com/title/tt0903747/,TV Series,9.5,49,2008,\"Crime, Drama\",2200000,2008-01-20,")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.NoError(err)
+				assertions.Len(ratings, 1)
+				assertions.Equal("tt0903747", ratings[0].ID)
+				assertions.Equal("Breaking Bad", ratings[0].Title)
+				assertions.Equal("TV Series", ratings[0].TitleType)
+				assertions.Equal(2008, ratings[0].Year)
+				assertions.Equal(10, *ratings[0].Rating)
+			},
+		},
+		{
+			name: "handle error when ratings response is served as html",
+			args: args{
+				response: &http.Response{
+					Header: http.Header{
+						imdbHeaderKeyContentType: []string{"text/html; charset=utf-8"},
+					},
+					Body: io.NopCloser(strings.NewReader(dummyIMDbListHTML)),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.ErrorContains(err, "imdb ratings export was served as html")
+			},
+		},
+		{
+			name: "successfully read ratings response with reordered and lowercase columns",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("title type,year,your rating,const,date rated,title,extra\nmovie,2017,8,tt5013056,2017-12-25,Dunkirk,value")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.NoError(err)
+				assertions.Len(ratings, 1)
+				assertions.Equal("tt5013056", ratings[0].ID)
+				assertions.Equal("Dunkirk", ratings[0].Title)
+				assertions.Equal("movie", ratings[0].TitleType)
+				assertions.Equal(2017, ratings[0].Year)
+				assertions.Equal(8, *ratings[0].Rating)
+			},
+		},
+		{
+			name: "successfully read ratings response with reviews",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("Const,Your Rating,Date Rated,Title,Your Review\ntt5013056,8,2017-12-25,Dunkirk,\" A tense and immersive war film \"\ntt0172495,9,2018-01-02,Gladiator,")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.NoError(err)
+				assertions.Len(ratings, 2)
+				assertions.Equal("A tense and immersive war film", ratings[0].Review)
+				assertions.Empty(ratings[1].Review)
+			},
+		},
+		{
+			name: "handle error when ratings export is missing a required column",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("Const,Date Rated,Title\ntt5013056,2017-12-25,Dunkirk")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.ErrorContains(err, `imdb ratings export is missing the required column "Your Rating"`)
+			},
+		},
 		{
 			name: "handle error when parsing rating value",
 			args: args{
 				response: &http.Response{
-					Body: io.NopCloser(strings.NewReader("field1,field2\n1,invalid-rating-value")),
+					Body: io.NopCloser(strings.NewReader(dummyIMDbRatingsHeader + "\ntt5013056,invalid-rating-value,2017-12-25,Dunkirk,,movie")),
 				},
 			},
 			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
@@ -789,7 +1380,7 @@ func Test_readIMDbRatingsResponse(t *testing.T) {
 			name: "handle error when parsing rating date",
 			args: args{
 				response: &http.Response{
-					Body: io.NopCloser(strings.NewReader("field1,field2,field3\n1,1,invalid-date")),
+					Body: io.NopCloser(strings.NewReader(dummyIMDbRatingsHeader + "\ntt5013056,8,invalid-date,Dunkirk,,movie")),
 				},
 			},
 			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
@@ -797,6 +1388,54 @@ func Test_readIMDbRatingsResponse(t *testing.T) {
 				assertions.Error(err)
 			},
 		},
+		{
+			name: "successfully read header-only ratings response",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("\ufeff" + dummyIMDbRatingsHeader + "\n")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.NoError(err)
+				assertions.Empty(ratings)
+			},
+		},
+		{
+			name: "handle error when ratings export is empty",
+			args: args{
+				response: &http.Response{
+					Body: http.NoBody,
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.ErrorContains(err, "imdb ratings export is empty")
+			},
+		},
+		{
+			name: "handle error when ratings export is missing the header row",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader("tt5013056,8,2017-12-25,Dunkirk,,movie")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.ErrorContains(err, "imdb ratings export is missing the header row")
+			},
+		},
+		{
+			name: "handle error when ratings export row is truncated",
+			args: args{
+				response: &http.Response{
+					Body: io.NopCloser(strings.NewReader(dummyIMDbRatingsHeader + "\ntt5013056,8")),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, ratings []entities.IMDbItem, err error) {
+				assertions.Nil(ratings)
+				assertions.ErrorContains(err, "imdb ratings export row 1 has 2 columns")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -897,8 +1536,87 @@ func TestIMDbClient_Hydrate(t *testing.T) {
 				config: imdbConfig{
 					basePath: testServer.URL,
 				},
+				logger: logger.NewLogger(io.Discard),
+			}
+			tt.assertions(assert.New(t), c.config, c.Hydrate(context.Background()))
+		})
+	}
+}
+
+func TestIMDbClient_RatingsAdd(t *testing.T) {
+	rating := 6
+	items := []entities.IMDbItem{
+		{
+			ID:     "tt5013056",
+			Rating: &rating,
+		},
+	}
+	tests := []struct {
+		name         string
+		requirements func(*require.Assertions) *httptest.Server
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully add ratings",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodPost, r.Method)
+					requirements.Equal("application/json", r.Header.Get(imdbHeaderKeyContentType))
+					var body imdbGraphQLRequest
+					requirements.NoError(json.NewDecoder(r.Body).Decode(&body))
+					requirements.Equal(imdbMutationRateTitle, body.Query)
+					requirements.Equal("tt5013056", body.Variables["titleId"])
+					requirements.Equal(float64(6), body.Variables["rating"])
+					w.WriteHeader(http.StatusOK)
+					_, err := w.Write([]byte(`{"data":{"rateTitle":{"rating":{"value":6}}}}`))
+					requirements.NoError(err)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure adding ratings rejected by graphql",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					_, err := w.Write([]byte(`{"errors":[{"message":"title not found"}]}`))
+					requirements.NoError(err)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "title not found")
+			},
+		},
+		{
+			name: "handle unexpected status",
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := tt.requirements(require.New(t))
+			defer testServer.Close()
+			c := &IMDbClient{
+				client: http.DefaultClient,
+				config: imdbConfig{
+					graphqlPath: testServer.URL,
+				},
+				logger: logger.NewLogger(io.Discard),
 			}
-			tt.assertions(assert.New(t), c.config, c.Hydrate())
+			tt.assertions(assert.New(t), c.RatingsAdd(context.Background(), items))
 		})
 	}
 }
@@ -945,7 +1663,7 @@ func TestNewIMDbClient(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewIMDbClient(tt.args.config, logger.NewLogger(io.Discard))
+			client, err := NewIMDbClient(tt.args.config, appconfig.HTTP{}, logger.NewLogger(io.Discard))
 			tt.assertions(assert.New(t), client, err)
 		})
 	}
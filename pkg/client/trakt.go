@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,13 +11,17 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
 const (
@@ -33,32 +38,73 @@ const (
 	traktHeaderKeyContentLength = "Content-Length"
 	traktHeaderKeyContentType   = "Content-Type"
 	traktHeaderKeyRetryAfter    = "Retry-After"
+	traktHeaderKeyAccountLimit  = "X-Account-Limit"
+	traktHeaderKeyUpgradeURL    = "X-Upgrade-URL"
+	traktHeaderKeyPageCount     = "X-Pagination-Page-Count"
+	traktHeaderKeyRateLimit     = "X-Ratelimit"
 
 	traktPathActivate            = "/activate"
 	traktPathActivateAuthorize   = "/activate/authorize"
 	traktPathAuthCodes           = "/oauth/device/code"
 	traktPathAuthSignIn          = "/auth/signin"
 	traktPathAuthTokens          = "/oauth/device/token"
+	traktPathAuthTokensRefresh   = "/oauth/token"
+	traktPathComments            = "/comments"
+	traktPathScrobbleStop        = "/scrobble/stop"
+	traktPathSearch              = "/search/%s?%s"
+	traktPathUserComments        = "/users/%s/comments/all/all?include_replies=false&limit=%s"
 	traktPathBaseAPI             = "https://api.trakt.tv"
 	traktPathBaseBrowser         = "https://trakt.tv"
 	traktPathHistory             = "/sync/history"
 	traktPathHistoryGet          = "/sync/history/%s/%s?limit=%s"
 	traktPathHistoryRemove       = "/sync/history/remove"
+	traktPathHidden              = "/users/hidden/%s?page=%d&limit=%d"
+	traktPathLastActivities      = "/sync/last_activities"
 	traktPathRatings             = "/sync/ratings"
+	traktPathRatingsPage         = "/sync/ratings?page=%d&limit=%d"
 	traktPathRatingsRemove       = "/sync/ratings/remove"
 	traktPathUserList            = "/users/%s/lists/%s"
 	traktPathUserListItems       = "/users/%s/lists/%s/items"
 	traktPathUserListItemsRemove = "/users/%s/lists/%s/items/remove"
+	traktPathUserListLike        = "/users/%s/lists/%s/like"
+	traktPathUserSettings        = "/users/settings"
+	traktPathWatched             = "/sync/watched/%s"
 	traktPathWatchlist           = "/sync/watchlist"
 	traktPathWatchlistRemove     = "/sync/watchlist/remove"
 
 	traktStatusCodeEnhanceYourCalm = 420 // https://github.com/trakt/api-help/discussions/350
+
+	traktBatchSizeDefault = 1000
+	traktRatingsPageLimit = 1000
+	traktHiddenPageLimit  = 1000
+
+	// requests are paced once less than this share of the rate limit budget of the current period remains
+	traktPacingThreshold = 0.2
+
+	traktListSortByDefault  = "rank"
+	traktListSortHowDefault = "asc"
 )
 
 type TraktClient struct {
-	client *http.Client
-	config traktConfig
-	logger *slog.Logger
+	client     *http.Client
+	config     traktConfig
+	tokenStore traktTokenStore
+	logger     *slog.Logger
+	clock      clock.Clock
+	writeMutex sync.Mutex
+	lastWrite  time.Time
+	paceMutex  sync.Mutex
+	pace       time.Duration
+	nextSlot   time.Time
+}
+
+// traktRateLimit is the value of the rate limit header, describing the budget of requests left until the end of the current period
+type traktRateLimit struct {
+	Name      string    `json:"name"`
+	Period    int       `json:"period"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Until     time.Time `json:"until"`
 }
 
 type traktConfig struct {
@@ -67,55 +113,189 @@ type traktConfig struct {
 	username    string
 }
 
-func NewTraktClient(conf appconfig.Trakt, logger *slog.Logger) (TraktClientInterface, error) {
+func NewTraktClient(conf appconfig.Trakt, httpConf appconfig.HTTP, logger *slog.Logger) (TraktClientInterface, error) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, fmt.Errorf("failure creating cookie jar: %w", err)
 	}
+	transport, err := newBaseTransport(httpConf)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating trakt http transport: %w", err)
+	}
+	tokenStore, err := newTraktTokenStore(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating trakt token store: %w", err)
+	}
 	return &TraktClient{
 		client: &http.Client{
-			Jar: jar,
+			Jar:       jar,
+			Transport: newLoggingTransport(newHeadersTransport(transport, httpConf, userAgentDefault()), logger, clientNameTrakt),
 		},
 		config: traktConfig{
 			Trakt: conf,
 		},
-		logger: logger,
+		tokenStore: tokenStore,
+		logger:     logger,
+		clock:      clock.New(),
 	}, nil
 }
 
-func (tc *TraktClient) Hydrate() error {
-	authCodes, err := tc.GetAuthCodes()
+func newTraktTokenStore(conf appconfig.Trakt) (traktTokenStore, error) {
+	if path := conf.TokensFilePath(); path != "" {
+		return &traktTokensFile{path: path}, nil
+	}
+	if conf.ShouldUseKeyring() {
+		kr, err := newOSKeyring()
+		if err != nil {
+			return nil, err
+		}
+		return &traktTokensKeyring{keyring: kr, account: *conf.Email}, nil
+	}
+	return nil, nil
+}
+
+// Hydrate authenticates against the trakt api, reusing the stored tokens when possible and simulating a sign in otherwise
+func (tc *TraktClient) Hydrate(ctx context.Context) error {
+	if tc.tokenStore != nil {
+		authenticated, err := tc.authenticateWithStoredTokens(ctx)
+		if err != nil {
+			return err
+		}
+		if authenticated {
+			return nil
+		}
+	}
+	authCodes, err := tc.GetAuthCodes(ctx)
 	if err != nil {
 		return fmt.Errorf("failure generating auth codes: %w", err)
 	}
-	authenticityToken, err := tc.BrowseSignIn()
+	authenticityToken, err := tc.BrowseSignIn(ctx)
 	if err != nil {
 		return fmt.Errorf("failure simulating browse to the trakt sign in page: %w", err)
 	}
-	if err = tc.SignIn(*authenticityToken); err != nil {
+	if err = tc.SignIn(ctx, *authenticityToken); err != nil {
 		return fmt.Errorf("failure simulating trakt sign in form submission: %w", err)
 	}
-	authenticityToken, err = tc.BrowseActivate()
+	authenticityToken, err = tc.BrowseActivate(ctx)
 	if err != nil {
 		return fmt.Errorf("failure simulating browse to the trakt device activation page: %w", err)
 	}
-	authenticityToken, err = tc.Activate(authCodes.UserCode, *authenticityToken)
+	authenticityToken, err = tc.Activate(ctx, authCodes.UserCode, *authenticityToken)
 	if err != nil {
 		return fmt.Errorf("failure simulating trakt device activation form submission: %w", err)
 	}
-	if err = tc.ActivateAuthorize(*authenticityToken); err != nil {
+	if err = tc.ActivateAuthorize(ctx, *authenticityToken); err != nil {
 		return fmt.Errorf("failure simulating trakt api app allowlisting: %w", err)
 	}
-	authTokens, err := tc.GetAccessToken(authCodes.DeviceCode)
+	authTokens, err := tc.GetAccessToken(ctx, authCodes.DeviceCode)
 	if err != nil {
 		return fmt.Errorf("failure exchanging trakt device code for access token: %w", err)
 	}
 	tc.config.accessToken = authTokens.AccessToken
+	return tc.storeTokens(newTraktTokens(authTokens, tc.clock.Now()))
+}
+
+// authenticateWithStoredTokens uses the stored tokens, refreshing them when they are about to expire
+// it reports false when there are no usable tokens, in which case the caller falls back to simulating a sign in
+func (tc *TraktClient) authenticateWithStoredTokens(ctx context.Context) (bool, error) {
+	tokens, err := tc.tokenStore.load()
+	if err != nil {
+		return false, fmt.Errorf("failure loading trakt tokens from %s: %w", tc.tokenStore, err)
+	}
+	if tokens == nil || tokens.AccessToken == "" {
+		tc.logger.Info("no stored trakt tokens found, signing in to trakt", slog.String("store", tc.tokenStore.String()))
+		return false, nil
+	}
+	if tokens.Email != *tc.config.Email {
+		tc.logger.Info("the stored trakt tokens belong to another trakt account, signing in to trakt", slog.String("store", tc.tokenStore.String()))
+		return false, nil
+	}
+	if tokens.expiresSoon(tc.clock.Now()) {
+		authTokens, err := tc.RefreshAccessToken(ctx, tokens.RefreshToken)
+		if err != nil {
+			tc.logger.Warn("failure refreshing the stored trakt tokens, signing in to trakt", logger.Error(err))
+			return false, nil
+		}
+		tokens = newTraktTokens(authTokens, tc.clock.Now())
+		if err = tc.storeTokens(tokens); err != nil {
+			return false, err
+		}
+	}
+	tc.config.accessToken = tokens.AccessToken
+	if err = tc.UserSettingsGet(ctx); err != nil {
+		var unauthorizedErr *TraktUnauthorizedError
+		if errors.As(err, &unauthorizedErr) {
+			tc.logger.Warn("trakt rejected the stored access token, signing in to trakt", logger.Error(err))
+			tc.config.accessToken = ""
+			return false, nil
+		}
+		return false, fmt.Errorf("failure fetching trakt user settings: %w", err)
+	}
+	return true, nil
+}
+
+func (tc *TraktClient) storeTokens(tokens *TraktTokens) error {
+	if tc.tokenStore == nil {
+		return nil
+	}
+	tokens.Email = *tc.config.Email
+	if err := tc.tokenStore.save(tokens); err != nil {
+		return fmt.Errorf("failure storing trakt tokens in %s: %w", tc.tokenStore, err)
+	}
 	return nil
 }
 
-func (tc *TraktClient) BrowseSignIn() (*string, error) {
-	response, err := tc.doRequest(requestFields{
+func (tc *TraktClient) RefreshAccessToken(ctx context.Context, refreshToken string) (*entities.TraktAuthTokensResponse, error) {
+	body, err := json.Marshal(entities.TraktAuthRefreshBody{
+		RefreshToken: refreshToken,
+		ClientID:     *tc.config.ClientID,
+		ClientSecret: *tc.config.ClientSecret,
+		RedirectURI:  "urn:ietf:wg:oauth:2.0:oob",
+		GrantType:    "refresh_token",
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodPost,
+		BasePath: traktPathBaseAPI,
+		Endpoint: traktPathAuthTokensRefresh,
+		Body:     bytes.NewReader(body),
+		Headers: map[string]string{
+			traktHeaderKeyContentType: "application/json",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeReader[*entities.TraktAuthTokensResponse](response.Body)
+}
+
+// UserSettingsGet fetches the username of the authenticated user, which the simulated sign in scrapes otherwise
+func (tc *TraktClient) UserSettingsGet(ctx context.Context) error {
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodGet,
+		BasePath: traktPathBaseAPI,
+		Endpoint: traktPathUserSettings,
+		Body:     http.NoBody,
+		Headers:  tc.defaultApiHeaders(),
+	})
+	if err != nil {
+		return err
+	}
+	settings, err := decodeReader[*entities.TraktUserSettings](response.Body)
+	if err != nil {
+		return err
+	}
+	tc.config.username = settings.User.IDs.Slug
+	if tc.config.username == "" {
+		tc.config.username = settings.User.Username
+	}
+	return nil
+}
+
+func (tc *TraktClient) BrowseSignIn(ctx context.Context) (*string, error) {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseBrowser,
 		Endpoint: traktPathAuthSignIn,
@@ -127,14 +307,14 @@ func (tc *TraktClient) BrowseSignIn() (*string, error) {
 	return scrapeSelectionAttribute(response.Body, clientNameTrakt, "#new_user > input[name=authenticity_token]", "value")
 }
 
-func (tc *TraktClient) SignIn(authenticityToken string) error {
+func (tc *TraktClient) SignIn(ctx context.Context, authenticityToken string) error {
 	data := url.Values{}
 	data.Set(traktFormKeyAuthenticityToken, authenticityToken)
 	data.Set(traktFormKeyUserLogIn, *tc.config.Email)
 	data.Set(traktFormKeyUserPassword, *tc.config.Password)
 	data.Set(traktFormKeyUserRemember, "1")
 	encodedData := data.Encode()
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseBrowser,
 		Endpoint: traktPathAuthSignIn,
@@ -151,8 +331,8 @@ func (tc *TraktClient) SignIn(authenticityToken string) error {
 	return nil
 }
 
-func (tc *TraktClient) BrowseActivate() (*string, error) {
-	response, err := tc.doRequest(requestFields{
+func (tc *TraktClient) BrowseActivate(ctx context.Context) (*string, error) {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseBrowser,
 		Endpoint: traktPathActivate,
@@ -164,13 +344,13 @@ func (tc *TraktClient) BrowseActivate() (*string, error) {
 	return scrapeSelectionAttribute(response.Body, clientNameTrakt, "#auth-form-wrapper > form.form-signin > input[name=authenticity_token]", "value")
 }
 
-func (tc *TraktClient) Activate(userCode, authenticityToken string) (*string, error) {
+func (tc *TraktClient) Activate(ctx context.Context, userCode, authenticityToken string) (*string, error) {
 	data := url.Values{}
 	data.Set(traktFormKeyAuthenticityToken, authenticityToken)
 	data.Set(traktFormKeyCode, userCode)
 	data.Set(traktFormKeyCommit, "Continue")
 	encodedData := data.Encode()
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseBrowser,
 		Endpoint: traktPathActivate,
@@ -186,12 +366,12 @@ func (tc *TraktClient) Activate(userCode, authenticityToken string) (*string, er
 	return scrapeSelectionAttribute(response.Body, clientNameTrakt, "#auth-form-wrapper > div.form-signin.less-top > div > form:nth-child(1) > input[name=authenticity_token]:nth-child(1)", "value")
 }
 
-func (tc *TraktClient) ActivateAuthorize(authenticityToken string) error {
+func (tc *TraktClient) ActivateAuthorize(ctx context.Context, authenticityToken string) error {
 	data := url.Values{}
 	data.Set(traktFormKeyAuthenticityToken, authenticityToken)
 	data.Set(traktFormKeyCommit, "Yes")
 	encodedData := data.Encode()
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseBrowser,
 		Endpoint: traktPathActivateAuthorize,
@@ -216,7 +396,7 @@ func (tc *TraktClient) ActivateAuthorize(authenticityToken string) error {
 	return nil
 }
 
-func (tc *TraktClient) GetAccessToken(deviceCode string) (*entities.TraktAuthTokensResponse, error) {
+func (tc *TraktClient) GetAccessToken(ctx context.Context, deviceCode string) (*entities.TraktAuthTokensResponse, error) {
 	body, err := json.Marshal(entities.TraktAuthTokensBody{
 		Code:         deviceCode,
 		ClientID:     *tc.config.ClientID,
@@ -225,7 +405,7 @@ func (tc *TraktClient) GetAccessToken(deviceCode string) (*entities.TraktAuthTok
 	if err != nil {
 		return nil, err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
 		Endpoint: traktPathAuthTokens,
@@ -240,12 +420,12 @@ func (tc *TraktClient) GetAccessToken(deviceCode string) (*entities.TraktAuthTok
 	return decodeReader[*entities.TraktAuthTokensResponse](response.Body)
 }
 
-func (tc *TraktClient) GetAuthCodes() (*entities.TraktAuthCodesResponse, error) {
+func (tc *TraktClient) GetAuthCodes(ctx context.Context) (*entities.TraktAuthCodesResponse, error) {
 	body, err := json.Marshal(entities.TraktAuthCodesBody{ClientID: *tc.config.ClientID})
 	if err != nil {
 		return nil, err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
 		Endpoint: traktPathAuthCodes,
@@ -271,30 +451,46 @@ func (tc *TraktClient) defaultApiHeaders() map[string]string {
 	}
 }
 
-func (tc *TraktClient) doRequest(requestFields requestFields) (*http.Response, error) {
-	request, err := http.NewRequest(requestFields.Method, requestFields.BasePath+requestFields.Endpoint, ReusableReader(requestFields.Body))
+func (tc *TraktClient) doRequest(ctx context.Context, requestFields requestFields) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, requestFields.Method, requestFields.BasePath+requestFields.Endpoint, ReusableReader(requestFields.Body))
 	if err != nil {
 		return nil, fmt.Errorf("error creating http request %s %s: %w", requestFields.Method, requestFields.BasePath+requestFields.Endpoint, err)
 	}
 	for key, value := range requestFields.Headers {
 		request.Header.Set(key, value)
 	}
+	if requestFields.Method != http.MethodGet && requestFields.BasePath == traktPathBaseAPI {
+		if err = tc.awaitWriteDelay(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var rateLimitErr *TraktRateLimitError
 	for retries := 0; retries < 5; retries++ {
+		if requestFields.BasePath == traktPathBaseAPI {
+			if err = tc.awaitPace(ctx); err != nil {
+				return nil, err
+			}
+		}
 		response, err := tc.client.Do(request)
 		if err != nil {
 			return nil, fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
 		}
+		tc.observeRateLimit(response)
 		switch response.StatusCode {
-		case http.StatusOK, http.StatusCreated, http.StatusNoContent, http.StatusNotFound:
+		case http.StatusOK, http.StatusCreated, http.StatusNoContent:
 			return response, nil
+		case http.StatusUnauthorized:
+			response.Body.Close()
+			return nil, &TraktUnauthorizedError{apiErr: newApiError(response)}
+		case http.StatusForbidden:
+			response.Body.Close()
+			return nil, &TraktForbiddenError{apiErr: newApiError(response)}
+		case http.StatusNotFound:
+			response.Body.Close()
+			return nil, &TraktNotFoundError{apiErr: newApiError(response)}
 		case traktStatusCodeEnhanceYourCalm:
 			response.Body.Close()
-			return nil, &ApiError{
-				httpMethod: response.Request.Method,
-				url:        response.Request.URL.String(),
-				StatusCode: response.StatusCode,
-				details:    fmt.Sprintf("trakt account limit exceeded, more info here: %s", "https://github.com/trakt/api-help/discussions/350"),
-			}
+			return nil, newTraktVIPRequiredError(response)
 		case http.StatusTooManyRequests:
 			response.Body.Close()
 			retryAfter, err := strconv.Atoi(response.Header.Get(traktHeaderKeyRetryAfter))
@@ -302,32 +498,114 @@ func (tc *TraktClient) doRequest(requestFields requestFields) (*http.Response, e
 				return nil, fmt.Errorf("failure parsing the value of trakt header %s to integer: %w", traktHeaderKeyRetryAfter, err)
 			}
 			duration := time.Duration(retryAfter) * time.Second
+			rateLimitErr = &TraktRateLimitError{
+				RetryAfter: duration,
+				apiErr:     newApiError(response),
+			}
 			message := fmt.Sprintf("trakt rate limit reached, waiting for %s then retrying http request %s %s", duration, response.Request.Method, response.Request.URL)
 			tc.logger.Warn(message)
-			time.Sleep(duration)
+			if err = sleepContext(ctx, tc.clock, duration); err != nil {
+				return nil, err
+			}
 			continue
 		case http.StatusRequestTimeout, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
 			response.Body.Close()
 			duration := time.Second
 			message := fmt.Sprintf("unexpected status code %d, waiting for %s then retrying http request %s %s", response.StatusCode, duration, response.Request.Method, response.Request.URL)
 			tc.logger.Warn(message)
-			time.Sleep(duration)
+			if err = sleepContext(ctx, tc.clock, duration); err != nil {
+				return nil, err
+			}
 			continue
 		default:
 			response.Body.Close()
-			return nil, &ApiError{
-				httpMethod: response.Request.Method,
-				url:        response.Request.URL.String(),
-				StatusCode: response.StatusCode,
-				details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
-			}
+			return nil, newApiError(response)
 		}
 	}
+	if rateLimitErr != nil {
+		return nil, fmt.Errorf("reached max retry attempts for %s %s: %w", request.Method, request.URL, rateLimitErr)
+	}
 	return nil, fmt.Errorf("reached max retry attempts for %s %s", request.Method, request.URL)
 }
 
-func (tc *TraktClient) WatchlistGet() (*entities.TraktList, error) {
-	response, err := tc.doRequest(requestFields{
+// awaitWriteDelay spaces out the requests that modify trakt data by the configured write delay
+func (tc *TraktClient) awaitWriteDelay(ctx context.Context) error {
+	delay := tc.config.WriteDelayDuration()
+	if delay == 0 {
+		return nil
+	}
+	tc.writeMutex.Lock()
+	defer tc.writeMutex.Unlock()
+	if !tc.lastWrite.IsZero() {
+		if wait := tc.lastWrite.Add(delay).Sub(tc.clock.Now()); wait > 0 {
+			if err := sleepContext(ctx, tc.clock, wait); err != nil {
+				return err
+			}
+		}
+	}
+	tc.lastWrite = tc.clock.Now()
+	return nil
+}
+
+// observeRateLimit adapts the pace of the requests to the rate limit budget trakt reports, so that the budget lasts until the end of its period
+// once the budget runs low, the requests are spread evenly over the rest of the period, rather than running into the rate limit
+func (tc *TraktClient) observeRateLimit(response *http.Response) {
+	value := response.Header.Get(traktHeaderKeyRateLimit)
+	if value == "" {
+		return
+	}
+	var rateLimit traktRateLimit
+	if err := json.Unmarshal([]byte(value), &rateLimit); err != nil || rateLimit.Limit <= 0 {
+		tc.logger.Debug(fmt.Sprintf("ignoring unexpected value of trakt header %s: %s", traktHeaderKeyRateLimit, value))
+		return
+	}
+	var pace time.Duration
+	if float64(rateLimit.Remaining) < float64(rateLimit.Limit)*traktPacingThreshold {
+		pace = max(rateLimit.Until.Sub(tc.clock.Now())/time.Duration(rateLimit.Remaining+1), 0)
+	}
+	tc.paceMutex.Lock()
+	defer tc.paceMutex.Unlock()
+	switch {
+	case pace > 0 && tc.pace == 0:
+		tc.logger.Info(fmt.Sprintf("trakt rate limit %s running low with %d of %d request(s) left until %s, spacing out requests by %s", rateLimit.Name, rateLimit.Remaining, rateLimit.Limit, rateLimit.Until.Format(time.RFC3339), pace))
+		tc.nextSlot = tc.clock.Now().Add(pace)
+	case pace == 0 && tc.pace > 0:
+		tc.logger.Info(fmt.Sprintf("trakt rate limit %s recovered, no longer spacing out requests", rateLimit.Name))
+	}
+	tc.pace = pace
+}
+
+// awaitPace waits for the next request slot while observeRateLimit sets a pace, each request reserves its own slot so that concurrent requests are spaced out as well
+func (tc *TraktClient) awaitPace(ctx context.Context) error {
+	tc.paceMutex.Lock()
+	if tc.pace == 0 {
+		tc.paceMutex.Unlock()
+		return nil
+	}
+	now := tc.clock.Now()
+	slot := tc.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	tc.nextSlot = slot.Add(tc.pace)
+	tc.paceMutex.Unlock()
+	if wait := slot.Sub(now); wait > 0 {
+		return sleepContext(ctx, tc.clock, wait)
+	}
+	return nil
+}
+
+func newApiError(response *http.Response) *ApiError {
+	return &ApiError{
+		httpMethod: response.Request.Method,
+		url:        response.Request.URL.String(),
+		StatusCode: response.StatusCode,
+		details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+	}
+}
+
+func (tc *TraktClient) WatchlistGet(ctx context.Context) (*entities.TraktList, error) {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
 		Endpoint: traktPathWatchlist,
@@ -335,7 +613,7 @@ func (tc *TraktClient) WatchlistGet() (*entities.TraktList, error) {
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return nil, err
+		return nil, watchlistError(err, true)
 	}
 	list := entities.TraktList{
 		IDMeta: entities.TraktIDMeta{
@@ -349,217 +627,391 @@ func (tc *TraktClient) WatchlistGet() (*entities.TraktList, error) {
 	return &list, nil
 }
 
-func (tc *TraktClient) WatchlistItemsAdd(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// WatchlistItemsAdd adds the items to the trakt watchlist, returning the summary of the trakt responses, which tells the items that already existed
+func (tc *TraktClient) WatchlistItemsAdd(ctx context.Context, items entities.TraktItems) (*entities.TraktResponse, error) {
+	response, err := tc.syncItems(ctx, traktPathWatchlist, items, "watchlist", "synced trakt watchlist", true)
 	if err != nil {
-		return err
+		return nil, watchlistError(err, false)
 	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
+	return response, nil
+}
+
+func (tc *TraktClient) WatchlistItemsRemove(ctx context.Context, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, traktPathWatchlistRemove, items, "watchlist", "synced trakt watchlist", false)
+	return watchlistError(err, false)
+}
+
+// watchlistError tells the watchlist being unavailable apart from other errors, the vip required error of a write is left to the caller,
+// since it reports the account limit of the watchlist, which leaves room for some of the items
+func watchlistError(err error, isRead bool) error {
+	var (
+		forbiddenErr   *TraktForbiddenError
+		notFoundErr    *TraktNotFoundError
+		vipRequiredErr *TraktVIPRequiredError
+	)
+	if errors.As(err, &forbiddenErr) || errors.As(err, &notFoundErr) || (isRead && errors.As(err, &vipRequiredErr)) {
+		return &TraktWatchlistUnavailableError{err: err}
+	}
+	return err
+}
+
+func (tc *TraktClient) ListGet(ctx context.Context, listID string) (*entities.TraktList, error) {
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathWatchlist,
-		Body:     bytes.NewReader(body),
+		Endpoint: fmt.Sprintf(traktPathUserListItems, tc.config.username, listID),
+		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		var notFoundErr *TraktNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, &TraktListNotFoundError{
+				Slug: listID,
+			}
+		}
+		return nil, err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
+	list := entities.TraktList{
+		IDMeta: entities.TraktIDMeta{
+			Slug: listID,
+		},
 	}
-	tc.logger.Info("synced trakt watchlist", slog.Any("watchlist", traktResponse))
-	return nil
+	if err = decodeReaderInto(response.Body, &list.ListItems); err != nil {
+		return nil, err
+	}
+	return &list, nil
 }
 
-func (tc *TraktClient) WatchlistItemsRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// ListItemsAdd adds the items to a trakt list, returning the summary of the trakt responses, which tells the items that already existed
+func (tc *TraktClient) ListItemsAdd(ctx context.Context, listID string, items entities.TraktItems) (*entities.TraktResponse, error) {
+	return tc.syncItems(ctx, fmt.Sprintf(traktPathUserListItems, tc.config.username, listID), items, listID, "synced trakt list", true)
+}
+
+func (tc *TraktClient) ListItemsRemove(ctx context.Context, listID string, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, fmt.Sprintf(traktPathUserListItemsRemove, tc.config.username, listID), items, listID, "synced trakt list", false)
+	return err
+}
+
+func (tc *TraktClient) ListsGet(ctx context.Context, idsMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
+	var (
+		mutex           sync.Mutex
+		waitGroup       sync.WaitGroup
+		lists           = make([]entities.TraktList, 0, len(idsMeta))
+		delegatedErrors = make([]error, 0, len(idsMeta))
+		unexpectedErr   error
+	)
+	for _, idMeta := range idsMeta {
+		waitGroup.Add(1)
+		go func(idMeta entities.TraktIDMeta) {
+			defer waitGroup.Done()
+			if idMeta.Owner != "" && !strings.EqualFold(idMeta.Owner, tc.config.username) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				delegatedErrors = append(delegatedErrors, &TraktListNotOwnedError{
+					Slug:  idMeta.Slug,
+					Owner: idMeta.Owner,
+				})
+				return
+			}
+			list, err := tc.ListGet(ctx, idMeta.Slug)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				var notFoundError *TraktListNotFoundError
+				if errors.As(err, &notFoundError) {
+					delegatedErrors = append(delegatedErrors, err)
+					return
+				}
+				if unexpectedErr == nil {
+					unexpectedErr = fmt.Errorf("unexpected error while fetching trakt lists: %w", err)
+				}
+				return
+			}
+			list.IDMeta = idMeta
+			lists = append(lists, *list)
+		}(idMeta)
+	}
+	// every list is waited for, so that none of the fetched lists and delegated errors are dropped
+	waitGroup.Wait()
+	if unexpectedErr != nil {
+		return nil, []error{unexpectedErr}
+	}
+	return lists, delegatedErrors
+}
+
+// ListAdd creates a list, sorted by rank in ascending order unless the sort settings are given, and returns it as created by trakt, whose slug is derived from the list name
+func (tc *TraktClient) ListAdd(ctx context.Context, listID, listName, privacy, sortBy, sortHow string) (*entities.TraktList, error) {
+	if sortBy == "" {
+		sortBy = traktListSortByDefault
+	}
+	if sortHow == "" {
+		sortHow = traktListSortHowDefault
+	}
+	body, err := json.Marshal(entities.TraktListAddBody{
+		Name:           listName,
+		Description:    fmt.Sprintf("list auto imported from imdb by https://github.com/cecobask/imdb-trakt-sync on %v", tc.clock.Now().Format(time.RFC1123)),
+		Privacy:        privacy,
+		DisplayNumbers: false,
+		AllowComments:  true,
+		SortBy:         sortBy,
+		SortHow:        sortHow,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathWatchlistRemove,
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, ""),
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	list, err := decodeReader[*entities.TraktList](response.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	tc.logger.Info("synced trakt watchlist", slog.Any("watchlist", traktResponse))
-	return nil
+	tc.logger.Info(fmt.Sprintf("created trakt list %s", listID))
+	return list, nil
 }
 
-func (tc *TraktClient) ListGet(listID string) (*entities.TraktList, error) {
-	response, err := tc.doRequest(requestFields{
+func (tc *TraktClient) ListSummaryGet(ctx context.Context, listID string) (*entities.TraktList, error) {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItems, tc.config.username, listID),
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return nil, err
-	}
-	if statusCode := response.StatusCode; statusCode == http.StatusNotFound {
-		return nil, &TraktListNotFoundError{
-			Slug: listID,
+		var notFoundErr *TraktNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, &TraktListNotFoundError{
+				Slug: listID,
+			}
 		}
-	}
-	list := entities.TraktList{
-		IDMeta: entities.TraktIDMeta{
-			Slug: listID,
-		},
-	}
-	if err = decodeReaderInto(response.Body, &list.ListItems); err != nil {
 		return nil, err
 	}
-	return &list, nil
+	return decodeReader[*entities.TraktList](response.Body)
 }
 
-func (tc *TraktClient) ListItemsAdd(listID string, items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+func (tc *TraktClient) ListUpdate(ctx context.Context, listID string, body entities.TraktListUpdateBody) error {
+	data, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodPut,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItems, tc.config.username, listID),
-		Body:     bytes.NewReader(body),
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
+		Body:     bytes.NewReader(data),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
-	}
-	tc.logger.Info("synced trakt list", slog.Any(listID, traktResponse))
+	defer response.Body.Close()
+	tc.logger.Info(fmt.Sprintf("updated trakt list %s", listID))
 	return nil
 }
 
-func (tc *TraktClient) ListItemsRemove(listID string, items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+func (tc *TraktClient) ListRemove(ctx context.Context, listID string) error {
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodDelete,
+		BasePath: traktPathBaseAPI,
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
+		Body:     http.NoBody,
+		Headers:  tc.defaultApiHeaders(),
+	})
 	if err != nil {
 		return err
 	}
-	response, err := tc.doRequest(requestFields{
+	defer response.Body.Close()
+	tc.logger.Info(fmt.Sprintf("removed trakt list %s", listID))
+	return nil
+}
+
+// ListLike likes a list of another user, which shows it among the liked lists of the trakt user
+func (tc *TraktClient) ListLike(ctx context.Context, owner, listID string) error {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItemsRemove, tc.config.username, listID),
-		Body:     bytes.NewReader(body),
+		Endpoint: fmt.Sprintf(traktPathUserListLike, owner, listID),
+		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
+		var notFoundErr *TraktNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return &TraktListNotFoundError{
+				Slug: listID,
+			}
+		}
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
-	}
-	tc.logger.Info("synced trakt list", slog.Any(listID, traktResponse))
+	defer response.Body.Close()
+	tc.logger.Info(fmt.Sprintf("liked trakt list %s of user %s", listID, owner))
 	return nil
 }
 
-func (tc *TraktClient) ListsGet(idsMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
-	var (
-		outChan         = make(chan entities.TraktList, len(idsMeta))
-		errChan         = make(chan error, 1)
-		doneChan        = make(chan struct{})
-		lists           = make([]entities.TraktList, 0, len(idsMeta))
-		delegatedErrors = make([]error, 0, len(idsMeta))
-	)
-	go func() {
-		waitGroup := new(sync.WaitGroup)
-		for _, idMeta := range idsMeta {
-			waitGroup.Add(1)
-			go func(idMeta entities.TraktIDMeta) {
-				defer waitGroup.Done()
-				list, err := tc.ListGet(idMeta.Slug)
-				if err != nil {
-					var notFoundError *TraktListNotFoundError
-					if errors.As(err, &notFoundError) {
-						delegatedErrors = append(delegatedErrors, err)
-						return
-					}
-					errChan <- fmt.Errorf("unexpected error while fetching trakt lists: %w", err)
-					return
-				}
-				list.IDMeta = idMeta
-				outChan <- *list
-			}(idMeta)
+// RatingsGet fetches the ratings page by page, until the page count trakt reports in the pagination headers is reached
+// the complete ratings are required, since the ratings missing from a partial response would be added again or removed
+func (tc *TraktClient) RatingsGet(ctx context.Context) (entities.TraktItems, error) {
+	var ratings entities.TraktItems
+	for page := 1; ; page++ {
+		response, err := tc.doRequest(ctx, requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf(traktPathRatingsPage, page, traktRatingsPageLimit),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pageCount, err := traktPageCount(response)
+		if err != nil {
+			response.Body.Close()
+			return nil, err
 		}
-		waitGroup.Wait()
-		close(doneChan)
-	}()
-	for {
-		select {
-		case list := <-outChan:
-			lists = append(lists, list)
-		case err := <-errChan:
-			return nil, []error{err}
-		case <-doneChan:
-			return lists, delegatedErrors
+		items, err := decodeReader[entities.TraktItems](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, items...)
+		if page >= pageCount {
+			tc.logger.Debug(fmt.Sprintf("fetched %d trakt rating(s) across %d page(s)", len(ratings), page))
+			return ratings, nil
 		}
 	}
 }
 
-func (tc *TraktClient) ListAdd(listID, listName string) error {
-	body, err := json.Marshal(entities.TraktListAddBody{
-		Name:           listName,
-		Description:    fmt.Sprintf("list auto imported from imdb by https://github.com/cecobask/imdb-trakt-sync on %v", time.Now().Format(time.RFC1123)),
-		Privacy:        "public",
-		DisplayNumbers: false,
-		AllowComments:  true,
-		SortBy:         "rank",
-		SortHow:        "asc",
-	})
+// traktPageCount returns the number of pages reported by trakt, a response without pagination headers holds everything in a single page
+func traktPageCount(response *http.Response) (int, error) {
+	value := response.Header.Get(traktHeaderKeyPageCount)
+	if value == "" {
+		return 1, nil
+	}
+	pageCount, err := strconv.Atoi(value)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("failure parsing trakt header %s with value %s: %w", traktHeaderKeyPageCount, value, err)
 	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
+	return pageCount, nil
+}
+
+func (tc *TraktClient) RatingsAdd(ctx context.Context, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, traktPathRatings, items, "ratings", "synced trakt ratings", true)
+	return err
+}
+
+func (tc *TraktClient) RatingsRemove(ctx context.Context, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, traktPathRatingsRemove, items, "ratings", "synced trakt ratings", false)
+	return err
+}
+
+func (tc *TraktClient) HistoryGet(ctx context.Context, itemType, itemID string) (entities.TraktItems, error) {
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, ""),
-		Body:     bytes.NewReader(body),
+		Endpoint: fmt.Sprintf(traktPathHistoryGet, itemType+"s", itemID, "1000"),
+		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer response.Body.Close()
-	tc.logger.Info(fmt.Sprintf("created trakt list %s", listID))
-	return nil
+	return decodeReader[entities.TraktItems](response.Body)
 }
 
-func (tc *TraktClient) ListRemove(listID string) error {
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodDelete,
+// WatchedGet returns the movies and shows watched at least once, the types of which trakt leaves out of the response
+func (tc *TraktClient) WatchedGet(ctx context.Context) (entities.TraktItems, error) {
+	var watched entities.TraktItems
+	for _, itemType := range []string{entities.TraktItemTypeMovie, entities.TraktItemTypeShow} {
+		response, err := tc.doRequest(ctx, requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf(traktPathWatched, itemType+"s"),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		items, err := decodeReader[entities.TraktItems](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i].Type = itemType
+		}
+		watched = append(watched, items...)
+	}
+	return watched, nil
+}
+
+// HiddenGet fetches the items hidden in a section, e.g. recommendations, page by page like RatingsGet
+func (tc *TraktClient) HiddenGet(ctx context.Context, section string) (entities.TraktItems, error) {
+	var hidden entities.TraktItems
+	for page := 1; ; page++ {
+		response, err := tc.doRequest(ctx, requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf(traktPathHidden, section, page, traktHiddenPageLimit),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pageCount, err := traktPageCount(response)
+		if err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+		items, err := decodeReader[entities.TraktItems](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		hidden = append(hidden, items...)
+		if page >= pageCount {
+			tc.logger.Debug(fmt.Sprintf("fetched %d trakt item(s) hidden from %s", len(hidden), section))
+			return hidden, nil
+		}
+	}
+}
+
+func (tc *TraktClient) HistoryAdd(ctx context.Context, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, traktPathHistory, items, "history", "synced trakt history", true)
+	return err
+}
+
+func (tc *TraktClient) HistoryRemove(ctx context.Context, items entities.TraktItems) error {
+	_, err := tc.syncItems(ctx, traktPathHistoryRemove, items, "history", "synced trakt history", false)
+	return err
+}
+
+func (tc *TraktClient) LastActivitiesGet(ctx context.Context) (*entities.TraktLastActivities, error) {
+	response, err := tc.doRequest(ctx, requestFields{
+		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
+		Endpoint: traktPathLastActivities,
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer response.Body.Close()
-	tc.logger.Info(fmt.Sprintf("removed trakt list %s", listID))
-	return nil
+	return decodeReader[*entities.TraktLastActivities](response.Body)
 }
 
-func (tc *TraktClient) RatingsGet() (entities.TraktItems, error) {
-	response, err := tc.doRequest(requestFields{
+func (tc *TraktClient) CommentsGet(ctx context.Context) (entities.TraktItems, error) {
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathRatings,
+		Endpoint: fmt.Sprintf(traktPathUserComments, tc.config.username, "1000"),
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
@@ -569,57 +1021,218 @@ func (tc *TraktClient) RatingsGet() (entities.TraktItems, error) {
 	return decodeReader[entities.TraktItems](response.Body)
 }
 
-func (tc *TraktClient) RatingsAdd(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+func (tc *TraktClient) CommentAdd(ctx context.Context, item entities.TraktItem, comment string, spoiler bool) error {
+	commentBody := entities.TraktCommentBody{
+		Comment: comment,
+		Spoiler: spoiler,
+	}
+	switch item.Type {
+	case entities.TraktItemTypeMovie:
+		commentBody.Movie = &item.Movie
+	case entities.TraktItemTypeShow:
+		commentBody.Show = &item.Show
+	case entities.TraktItemTypeEpisode:
+		commentBody.Episode = &item.Episode
+	default:
+		return fmt.Errorf("unsupported trakt item type %s for comments", item.Type)
+	}
+	body, err := json.Marshal(commentBody)
 	if err != nil {
 		return err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathRatings,
+		Endpoint: traktPathComments,
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
-	}
-	tc.logger.Info("synced trakt ratings", slog.Any("ratings", traktResponse))
+	defer response.Body.Close()
 	return nil
 }
 
-func (tc *TraktClient) RatingsRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// Scrobble marks the item as fully watched by stopping a scrobble at 100% progress, trakt records it as watched at the current time
+func (tc *TraktClient) Scrobble(ctx context.Context, item entities.TraktItem) error {
+	scrobbleBody := entities.TraktScrobbleBody{
+		Progress: 100,
+	}
+	switch item.Type {
+	case entities.TraktItemTypeMovie:
+		scrobbleBody.Movie = &item.Movie
+	case entities.TraktItemTypeEpisode:
+		scrobbleBody.Episode = &item.Episode
+	default:
+		return fmt.Errorf("unsupported trakt item type %s for scrobbles", item.Type)
+	}
+	body, err := json.Marshal(scrobbleBody)
 	if err != nil {
 		return err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathRatingsRemove,
+		Endpoint: traktPathScrobbleStop,
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	defer response.Body.Close()
+	return nil
+}
+
+// syncItems sends the items to a trakt sync endpoint in batches, as trakt limits the number of items per request
+// a failing batch doesn't prevent the remaining ones from being sent, unless the failure would affect them too
+// when matchNotFound is set, the items trakt couldn't find by imdb id are matched by title, see syncMatchedItems
+// the responses to the batches are summarised, items that already exist are not an error, as trakt skips them
+func (tc *TraktClient) syncItems(ctx context.Context, endpoint string, items entities.TraktItems, logKey, logMessage string, matchNotFound bool) (*entities.TraktResponse, error) {
+	batches := chunkTraktItems(items, tc.batchSize())
+	summary := &entities.TraktResponse{}
+	var errs []error
+	for i, batch := range batches {
+		traktResponse, err := tc.syncItemsBatch(ctx, endpoint, batch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failure syncing batch %d/%d: %w", i+1, len(batches), err))
+			var vipErr *TraktVIPRequiredError
+			if ctx.Err() != nil || errors.As(err, &vipErr) {
+				break
+			}
+			continue
+		}
+		tc.logger.Info(logMessage, slog.Any(logKey, traktResponse), slog.String("batch", fmt.Sprintf("%d/%d", i+1, len(batches))))
+		if traktResponse == nil {
+			continue
+		}
+		if matchNotFound && traktResponse.NotFound != nil {
+			if err = tc.syncMatchedItems(ctx, endpoint, batch, traktResponse, logKey, logMessage); err != nil {
+				errs = append(errs, fmt.Errorf("failure syncing items matched by title in batch %d/%d: %w", i+1, len(batches), err))
+			}
+		}
+		if existing := traktResponse.Existing.Total(); existing > 0 {
+			tc.logger.Debug(fmt.Sprintf("skipped %d item(s) already present in trakt %s", existing, logKey), slog.String("batch", fmt.Sprintf("%d/%d", i+1, len(batches))))
+		}
+		summary.Merge(traktResponse)
+	}
+	return summary, errors.Join(errs...)
+}
+
+// syncMatchedItems searches trakt by title and year for the movies and shows it couldn't find by imdb id, using the configured title match strategy
+// unambiguous matches are sent again by trakt id, ambiguous ones are logged and skipped. Episodes can't be matched by title reliably, hence they're skipped
+// the response to the batch is updated with the outcome, the matched items are no longer reported as not found
+func (tc *TraktClient) syncMatchedItems(ctx context.Context, endpoint string, batch entities.TraktItems, batchResponse *entities.TraktResponse, logKey, logMessage string) error {
+	matcher, ok := titleMatchers[tc.config.TitleMatchStrategy()]
+	if !ok {
+		return nil
+	}
+	notFound := batchResponse.NotFound
+	notFoundIDs := make(map[string]struct{})
+	for _, spec := range append(slices.Clone(notFound.Movies), notFound.Shows...) {
+		notFoundIDs[spec.IDMeta.IMDb] = struct{}{}
+	}
+	var (
+		matched    entities.TraktItems
+		matchedIDs = make(map[string]struct{})
+	)
+	for _, item := range batch {
+		if item.Type != entities.TraktItemTypeMovie && item.Type != entities.TraktItemTypeShow {
+			continue
+		}
+		id, err := item.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if _, found := notFoundIDs[*id]; !found {
+			continue
+		}
+		match, err := tc.matchItem(ctx, item, matcher)
+		if err != nil {
+			return err
+		}
+		if match != nil {
+			matched = append(matched, *match)
+			matchedIDs[*id] = struct{}{}
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	traktResponse, err := tc.syncItemsBatch(ctx, endpoint, matched)
 	if err != nil {
 		return err
 	}
-	tc.logger.Info("synced trakt ratings", slog.Any("ratings", traktResponse))
+	tc.logger.Info(logMessage, slog.Any(logKey, traktResponse), slog.Int("matchedByTitle", len(matched)))
+	isMatched := func(spec entities.TraktItemSpec) bool {
+		_, found := matchedIDs[spec.IDMeta.IMDb]
+		return found
+	}
+	notFound.Movies = slices.DeleteFunc(notFound.Movies, isMatched)
+	notFound.Shows = slices.DeleteFunc(notFound.Shows, isMatched)
+	if traktResponse != nil {
+		// the matched items are sent by trakt id, hence trakt can't report them by imdb id
+		traktResponse.NotFound = nil
+		batchResponse.Merge(traktResponse)
+	}
 	return nil
 }
 
-func (tc *TraktClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
-	response, err := tc.doRequest(requestFields{
+// matchItem returns the item identified by the trakt id of its only search result accepted by the matcher, or nil when there isn't exactly one
+func (tc *TraktClient) matchItem(ctx context.Context, item entities.TraktItem, matcher titleMatcher) (*entities.TraktItem, error) {
+	label := item.Label()
+	spec := &item.Movie
+	if item.Type == entities.TraktItemTypeShow {
+		spec = &item.Show
+	}
+	if spec.Title == "" {
+		return nil, nil
+	}
+	results, err := tc.Search(ctx, item.Type, spec.Title, spec.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failure searching trakt for %s: %w", label, err)
+	}
+	var candidates entities.TraktItems
+	for _, result := range results {
+		candidate := result.Movie
+		if result.Type == entities.TraktItemTypeShow {
+			candidate = result.Show
+		}
+		if result.Type == item.Type && matcher(*spec, candidate) {
+			candidates = append(candidates, result)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		tc.logger.Debug(fmt.Sprintf("no trakt %s matches %s by title", item.Type, label))
+		return nil, nil
+	case 1:
+		candidate := candidates[0].Movie
+		if item.Type == entities.TraktItemTypeShow {
+			candidate = candidates[0].Show
+		}
+		spec.IDMeta = candidate.IDMeta
+		tc.logger.Info(fmt.Sprintf("matched %s to trakt %s %s by title", label, item.Type, candidate.IDMeta.Slug))
+		return &item, nil
+	default:
+		tc.logger.Warn(fmt.Sprintf("skipping %s, it matches %d trakt %ss by title", label, len(candidates), item.Type), slog.Any("candidates", candidates.Labels()))
+		return nil, nil
+	}
+}
+
+// Search looks up movies or shows by title, restricted to the year before and after the given one, unless it is 0
+func (tc *TraktClient) Search(ctx context.Context, itemType, title string, year int) (entities.TraktItems, error) {
+	query := url.Values{
+		"query": {title},
+	}
+	if year != 0 {
+		query.Set("years", fmt.Sprintf("%d-%d", year-1, year+1))
+	}
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathHistoryGet, itemType+"s", itemID, "1000"),
+		Endpoint: fmt.Sprintf(traktPathSearch, itemType, query.Encode()),
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
@@ -629,50 +1242,103 @@ func (tc *TraktClient) HistoryGet(itemType, itemID string) (entities.TraktItems,
 	return decodeReader[entities.TraktItems](response.Body)
 }
 
-func (tc *TraktClient) HistoryAdd(items entities.TraktItems) error {
+// titleMatcher reports whether a trakt search result is the same title as an item that trakt couldn't find by imdb id
+type titleMatcher func(item, candidate entities.TraktItemSpec) bool
+
+var titleMatchers = map[string]titleMatcher{
+	appconfig.TitleMatchStrict: func(item, candidate entities.TraktItemSpec) bool {
+		return item.Year != 0 && item.Year == candidate.Year && strings.EqualFold(item.Title, candidate.Title)
+	},
+	appconfig.TitleMatchLoose: func(item, candidate entities.TraktItemSpec) bool {
+		yearDiff := item.Year - candidate.Year
+		if item.Year != 0 && candidate.Year != 0 && (yearDiff < -1 || yearDiff > 1) {
+			return false
+		}
+		return normalizeTitle(item.Title) == normalizeTitle(candidate.Title)
+	},
+}
+
+// normalizeTitle lowercases the title and drops everything but letters and digits, e.g. "Spider-Man: No Way Home" becomes "spidermannowayhome"
+func normalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func (tc *TraktClient) syncItemsBatch(ctx context.Context, endpoint string, items entities.TraktItems) (*entities.TraktResponse, error) {
 	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	response, err := tc.doRequest(requestFields{
+	response, err := tc.doRequest(ctx, requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathHistory,
+		Endpoint: endpoint,
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
+	return decodeReader[*entities.TraktResponse](response.Body)
+}
+
+func (tc *TraktClient) batchSize() int {
+	if size := tc.config.BatchSize; size != nil && *size > 0 {
+		return *size
 	}
-	tc.logger.Info("synced trakt history", slog.Any("history", traktResponse))
-	return nil
+	return traktBatchSizeDefault
 }
 
-func (tc *TraktClient) HistoryRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
-	if err != nil {
-		return err
+func chunkTraktItems(items entities.TraktItems, size int) []entities.TraktItems {
+	var chunks []entities.TraktItems
+	for size < len(items) {
+		items, chunks = items[size:], append(chunks, items[:size:size])
 	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
-		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathHistoryRemove,
-		Body:     bytes.NewReader(body),
-		Headers:  tc.defaultApiHeaders(),
-	})
-	if err != nil {
-		return err
+	if len(items) > 0 {
+		chunks = append(chunks, items)
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
+	return chunks
+}
+
+func newTraktVIPRequiredError(response *http.Response) *TraktVIPRequiredError {
+	vipErr := &TraktVIPRequiredError{
+		Feature:    traktFeature(response.Request.Method, response.Request.URL.Path),
+		UpgradeURL: response.Header.Get(traktHeaderKeyUpgradeURL),
+		apiErr: &ApiError{
+			httpMethod: response.Request.Method,
+			url:        response.Request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("trakt account limit exceeded, more info here: %s", "https://github.com/trakt/api-help/discussions/350"),
+		},
+	}
+	if limit, err := strconv.Atoi(response.Header.Get(traktHeaderKeyAccountLimit)); err == nil {
+		vipErr.Limit = limit
+	}
+	if vipErr.UpgradeURL == "" {
+		vipErr.UpgradeURL = "https://trakt.tv/vip"
+	}
+	return vipErr
+}
+
+// traktFeature describes the account limited feature behind a request, based on its endpoint
+func traktFeature(method, path string) string {
+	switch {
+	case strings.Contains(path, "/watchlist"):
+		return "adding more items to the watchlist"
+	case strings.HasSuffix(path, "/items"):
+		return "adding more items to a list"
+	case strings.HasSuffix(path, "/lists") && method == http.MethodPost:
+		return "creating more lists"
+	case strings.Contains(path, "/comments"):
+		return "adding more comments"
+	default:
+		return fmt.Sprintf("%s %s", method, path)
 	}
-	tc.logger.Info("synced trakt history", slog.Any("history", traktResponse))
-	return nil
 }
 
 func mapTraktItemsToTraktBody(items entities.TraktItems) entities.TraktListBody {
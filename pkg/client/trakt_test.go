@@ -1,13 +1,17 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +19,7 @@ import (
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/clock"
 	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
@@ -25,6 +30,7 @@ func buildTestTraktClient(config traktConfig) TraktClientInterface {
 		},
 		config: config,
 		logger: logger.NewLogger(io.Discard),
+		clock:  clock.NewFake(dummyNow),
 	}
 }
 
@@ -33,6 +39,7 @@ func stringPointer(s string) *string {
 }
 
 var (
+	dummyNow               = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
 	dummyUsername          = "cecobask"
 	dummyListID            = "watched"
 	dummyListName          = "Watched"
@@ -97,6 +104,18 @@ var (
 			Type: entities.TraktItemTypeSeason,
 		},
 	}
+	dummyTitledItems = entities.TraktItems{
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta: entities.TraktIDMeta{
+					IMDb: "tt5013056",
+				},
+				Title: "Dunkirk",
+				Year:  2017,
+			},
+		},
+	}
 	dummyRequestFields = requestFields{
 		Method:   http.MethodPost,
 		Endpoint: "/",
@@ -112,10 +131,11 @@ func TestTraktClient_doRequest(t *testing.T) {
 		requestFields requestFields
 	}
 	tests := []struct {
-		name         string
-		args         args
-		requirements func(*require.Assertions) *httptest.Server
-		assertions   func(*assert.Assertions, *http.Response, error)
+		name            string
+		args            args
+		requirements    func(*require.Assertions) *httptest.Server
+		assertions      func(*assert.Assertions, *http.Response, error)
+		clockAssertions func(*assert.Assertions, *clock.Fake)
 	}{
 		{
 			name: "handle response delegation",
@@ -157,6 +177,34 @@ func TestTraktClient_doRequest(t *testing.T) {
 				assertions.Equal(traktStatusCodeEnhanceYourCalm, apiError.StatusCode)
 			},
 		},
+		{
+			name: "handle vip required for list items",
+			args: args{
+				requestFields: requestFields{
+					Method:   http.MethodPost,
+					BasePath: dummyRequestFields.BasePath,
+					Endpoint: fmt.Sprintf(traktPathUserListItems, dummyUsername, dummyListID),
+					Body:     http.NoBody,
+				},
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(traktHeaderKeyAccountLimit, "100")
+					w.Header().Set(traktHeaderKeyUpgradeURL, "https://trakt.tv/vip")
+					w.WriteHeader(traktStatusCodeEnhanceYourCalm)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var vipErr *TraktVIPRequiredError
+				assertions.True(errors.As(err, &vipErr))
+				assertions.Equal(100, vipErr.Limit)
+				assertions.Equal("https://trakt.tv/vip", vipErr.UpgradeURL)
+				assertions.Equal("adding more items to a list", vipErr.Feature)
+				assertions.Contains(err.Error(), "adding more items to a list")
+			},
+		},
 		{
 			name: "handle status too many requests",
 			args: args{
@@ -175,6 +223,11 @@ func TestTraktClient_doRequest(t *testing.T) {
 				assertions.Nil(res)
 				assertions.Error(err)
 				assertions.Contains(err.Error(), "reached max retry attempts")
+				var rateLimitErr *TraktRateLimitError
+				assertions.True(errors.As(err, &rateLimitErr))
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusTooManyRequests, apiError.StatusCode)
 			},
 		},
 		{
@@ -195,6 +248,69 @@ func TestTraktClient_doRequest(t *testing.T) {
 				assertions.Error(err)
 				assertions.Contains(err.Error(), "reached max retry attempts")
 			},
+			clockAssertions: func(assertions *assert.Assertions, clk *clock.Fake) {
+				assertions.Equal(dummyNow.Add(5*time.Second), clk.Now())
+			},
+		},
+		{
+			name: "handle status unauthorized",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var unauthorizedErr *TraktUnauthorizedError
+				assertions.True(errors.As(err, &unauthorizedErr))
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+			},
+		},
+		{
+			name: "handle status forbidden",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var forbiddenErr *TraktForbiddenError
+				assertions.True(errors.As(err, &forbiddenErr))
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusForbidden, apiError.StatusCode)
+			},
+		},
+		{
+			name: "handle status not found",
+			args: args{
+				requestFields: dummyRequestFields,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return httptest.NewServer(http.HandlerFunc(handler))
+			},
+			assertions: func(assertions *assert.Assertions, res *http.Response, err error) {
+				assertions.Nil(res)
+				var notFoundErr *TraktNotFoundError
+				assertions.True(errors.As(err, &notFoundErr))
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusNotFound, apiError.StatusCode)
+			},
 		},
 		{
 			name: "handle unexpected status code",
@@ -243,16 +359,144 @@ func TestTraktClient_doRequest(t *testing.T) {
 			testServer := tt.requirements(require.New(t))
 			defer testServer.Close()
 			tt.args.requestFields.BasePath = testServer.URL
+			clk := clock.NewFake(dummyNow)
 			c := &TraktClient{
 				client: http.DefaultClient,
 				logger: logger.NewLogger(io.Discard),
+				clock:  clk,
 			}
-			res, err := c.doRequest(tt.args.requestFields)
+			res, err := c.doRequest(context.Background(), tt.args.requestFields)
 			tt.assertions(assert.New(t), res, err)
+			if tt.clockAssertions != nil {
+				tt.clockAssertions(assert.New(t), clk)
+			}
+		})
+	}
+}
+
+func TestTraktClient_awaitWriteDelay(t *testing.T) {
+	delay := 200 * time.Millisecond
+	tests := []struct {
+		name       string
+		writeDelay *time.Duration
+		assertions func(*assert.Assertions, *clock.Fake)
+	}{
+		{
+			name: "space out writes by the configured delay",
+			writeDelay: func() *time.Duration {
+				return &delay
+			}(),
+			assertions: func(assertions *assert.Assertions, clk *clock.Fake) {
+				assertions.Equal(dummyNow.Add(2*delay), clk.Now())
+			},
+		},
+		{
+			name: "skip waiting when the delay is not configured",
+			assertions: func(assertions *assert.Assertions, clk *clock.Fake) {
+				assertions.Equal(dummyNow, clk.Now())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFake(dummyNow)
+			c := &TraktClient{
+				config: traktConfig{
+					Trakt: appconfig.Trakt{
+						WriteDelay: tt.writeDelay,
+					},
+				},
+				logger: logger.NewLogger(io.Discard),
+				clock:  clk,
+			}
+			for i := 0; i < 3; i++ {
+				require.NoError(t, c.awaitWriteDelay(context.Background()))
+			}
+			tt.assertions(assert.New(t), clk)
+		})
+	}
+}
+
+func TestTraktClient_observeRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		pace       time.Duration
+		header     string
+		assertions func(*assert.Assertions, time.Duration)
+	}{
+		{
+			name:   "keep full speed while the budget lasts",
+			header: `{"name":"AUTHED_API_GET_LIMIT","period":300,"limit":1000,"remaining":900,"until":"2024-01-01T00:05:00Z"}`,
+			assertions: func(assertions *assert.Assertions, pace time.Duration) {
+				assertions.Zero(pace)
+			},
+		},
+		{
+			name:   "spread the remaining budget over the rest of the period",
+			header: `{"name":"AUTHED_API_GET_LIMIT","period":300,"limit":1000,"remaining":99,"until":"2024-01-01T00:05:00Z"}`,
+			assertions: func(assertions *assert.Assertions, pace time.Duration) {
+				assertions.Equal(3*time.Second, pace)
+			},
+		},
+		{
+			name:   "stop pacing once the budget recovers",
+			pace:   3 * time.Second,
+			header: `{"name":"AUTHED_API_GET_LIMIT","period":300,"limit":1000,"remaining":1000,"until":"2024-01-01T00:10:00Z"}`,
+			assertions: func(assertions *assert.Assertions, pace time.Duration) {
+				assertions.Zero(pace)
+			},
+		},
+		{
+			name:   "ignore a period that already ended",
+			header: `{"name":"AUTHED_API_GET_LIMIT","period":300,"limit":1000,"remaining":0,"until":"2023-12-31T23:59:00Z"}`,
+			assertions: func(assertions *assert.Assertions, pace time.Duration) {
+				assertions.Zero(pace)
+			},
+		},
+		{
+			name:   "keep the pace when the header is malformed",
+			pace:   time.Second,
+			header: "invalid",
+			assertions: func(assertions *assert.Assertions, pace time.Duration) {
+				assertions.Equal(time.Second, pace)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &TraktClient{
+				logger: logger.NewLogger(io.Discard),
+				clock:  clock.NewFake(dummyNow),
+				pace:   tt.pace,
+			}
+			response := httpmock.NewStringResponse(http.StatusOK, "")
+			response.Header.Set(traktHeaderKeyRateLimit, tt.header)
+			c.observeRateLimit(response)
+			tt.assertions(assert.New(t), c.pace)
 		})
 	}
 }
 
+func TestTraktClient_awaitPace(t *testing.T) {
+	clk := clock.NewFake(dummyNow)
+	c := &TraktClient{
+		logger: logger.NewLogger(io.Discard),
+		clock:  clk,
+	}
+	require.NoError(t, c.awaitPace(context.Background()))
+	assert.Equal(t, dummyNow, clk.Now())
+	c.pace = 2 * time.Second
+	require.NoError(t, c.awaitPace(context.Background()))
+	assert.Equal(t, dummyNow, clk.Now())
+	require.NoError(t, c.awaitPace(context.Background()))
+	assert.Equal(t, dummyNow.Add(2*time.Second), clk.Now())
+	require.NoError(t, c.awaitPace(context.Background()))
+	assert.Equal(t, dummyNow.Add(4*time.Second), clk.Now())
+	clk.Advance(time.Minute)
+	require.NoError(t, c.awaitPace(context.Background()))
+	assert.Equal(t, dummyNow.Add(4*time.Second+time.Minute), clk.Now())
+}
+
 func TestTraktClient_WatchlistGet(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -291,6 +535,26 @@ func TestTraktClient_WatchlistGet(t *testing.T) {
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+				var unavailableErr *TraktWatchlistUnavailableError
+				assertions.False(errors.As(err, &unavailableErr))
+			},
+		},
+		{
+			name: "failure getting restricted watchlist",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathWatchlist,
+					httpmock.NewJsonResponderOrPanic(http.StatusForbidden, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
+				assertions.Nil(list)
+				assertions.Error(err)
+				var unavailableErr *TraktWatchlistUnavailableError
+				assertions.True(errors.As(err, &unavailableErr))
+				var forbiddenErr *TraktForbiddenError
+				assertions.True(errors.As(err, &forbiddenErr))
 			},
 		},
 		{
@@ -315,7 +579,7 @@ func TestTraktClient_WatchlistGet(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(dummyConfig)
-			watchlist, err := c.WatchlistGet()
+			watchlist, err := c.WatchlistGet(context.Background())
 			tt.assertions(assert.New(t), watchlist, err)
 		})
 	}
@@ -333,7 +597,7 @@ func TestTraktClient_WatchlistItemsAdd(t *testing.T) {
 		args         args
 		fields       fields
 		requirements func()
-		assertions   func(*assert.Assertions, error)
+		assertions   func(*assert.Assertions, *entities.TraktResponse, error)
 	}{
 		{
 			name: "successfully add watchlist items",
@@ -350,8 +614,46 @@ func TestTraktClient_WatchlistItemsAdd(t *testing.T) {
 					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "summarise a response with added, existing and not found items",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathWatchlist,
+					httpmock.NewJsonResponderOrPanic(http.StatusCreated, entities.TraktResponse{
+						Added: &entities.TraktCrudItem{
+							Movies: 1,
+						},
+						Existing: &entities.TraktCrudItem{
+							Shows: 1,
+						},
+						NotFound: &entities.TraktListBody{
+							Episodes: entities.TraktItemSpecs{
+								{
+									IDMeta: entities.TraktIDMeta{
+										IMDb: "tt0959621",
+									},
+								},
+							},
+						},
+					}),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.NoError(err)
+				assertions.Equal(1, response.Added.Total())
+				assertions.Equal(1, response.Existing.Total())
+				assertions.Equal(map[string]struct{}{"tt0959621": {}}, response.NotFoundIDs())
 			},
 		},
 		{
@@ -369,7 +671,7 @@ func TestTraktClient_WatchlistItemsAdd(t *testing.T) {
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.Error(err)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
@@ -391,7 +693,7 @@ func TestTraktClient_WatchlistItemsAdd(t *testing.T) {
 					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.Error(err)
 				assertions.Contains(err.Error(), "failure decoding reader")
 			},
@@ -403,8 +705,8 @@ func TestTraktClient_WatchlistItemsAdd(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.WatchlistItemsAdd(tt.args.items)
-			tt.assertions(assert.New(t), err)
+			response, err := c.WatchlistItemsAdd(context.Background(), tt.args.items)
+			tt.assertions(assert.New(t), response, err)
 		})
 	}
 }
@@ -491,7 +793,7 @@ func TestTraktClient_WatchlistItemsRemove(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.WatchlistItemsRemove(tt.args.items)
+			err := c.WatchlistItemsRemove(context.Background(), tt.args.items)
 			tt.assertions(assert.New(t), err)
 		})
 	}
@@ -609,7 +911,7 @@ func TestTraktClient_ListGet(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			list, err := c.ListGet(tt.args.listID)
+			list, err := c.ListGet(context.Background(), tt.args.listID)
 			tt.assertions(assert.New(t), list, err)
 		})
 	}
@@ -628,7 +930,7 @@ func TestTraktClient_ListItemsAdd(t *testing.T) {
 		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, error)
+		assertions   func(*assert.Assertions, *entities.TraktResponse, error)
 	}{
 		{
 			name: "successfully add list items",
@@ -649,8 +951,47 @@ func TestTraktClient_ListItemsAdd(t *testing.T) {
 					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "summarise a response with added, existing and not found items",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				listID: dummyListID,
+				items:  dummyItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserListItems, dummyUsername, dummyListID),
+					httpmock.NewJsonResponderOrPanic(http.StatusCreated, entities.TraktResponse{
+						Added: &entities.TraktCrudItem{
+							Movies: 1,
+						},
+						Existing: &entities.TraktCrudItem{
+							Shows: 1,
+						},
+						NotFound: &entities.TraktListBody{
+							Episodes: entities.TraktItemSpecs{
+								{
+									IDMeta: entities.TraktIDMeta{
+										IMDb: "tt0959621",
+									},
+								},
+							},
+						},
+					}),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.NoError(err)
+				assertions.Equal(1, response.Added.Total())
+				assertions.Equal(1, response.Existing.Total())
+				assertions.Equal(map[string]struct{}{"tt0959621": {}}, response.NotFoundIDs())
 			},
 		},
 		{
@@ -672,7 +1013,7 @@ func TestTraktClient_ListItemsAdd(t *testing.T) {
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.Error(err)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
@@ -695,7 +1036,7 @@ func TestTraktClient_ListItemsAdd(t *testing.T) {
 					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktResponse, err error) {
 				assertions.Error(err)
 				assertions.Contains(err.Error(), "failure decoding reader")
 			},
@@ -707,8 +1048,8 @@ func TestTraktClient_ListItemsAdd(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.ListItemsAdd(tt.args.listID, tt.args.items)
-			tt.assertions(assert.New(t), err)
+			response, err := c.ListItemsAdd(context.Background(), tt.args.listID, tt.args.items)
+			tt.assertions(assert.New(t), response, err)
 		})
 	}
 }
@@ -805,7 +1146,7 @@ func TestTraktClient_ListItemsRemove(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.ListItemsRemove(tt.args.listID, tt.args.items)
+			err := c.ListItemsRemove(context.Background(), tt.args.listID, tt.args.items)
 			tt.assertions(assert.New(t), err)
 		})
 	}
@@ -858,6 +1199,30 @@ func TestTraktClient_ListsGet(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "skip lists of other users",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				idsMeta: []entities.TraktIDMeta{
+					{
+						IMDb:  "ls123456789",
+						Slug:  dummyListID,
+						Owner: "someone-else",
+					},
+				},
+			},
+			requirements: func() {},
+			assertions: func(assertions *assert.Assertions, lists []entities.TraktList, errs []error) {
+				assertions.Empty(lists)
+				assertions.Len(errs, 1)
+				var notOwnedErr *TraktListNotOwnedError
+				assertions.True(errors.As(errs[0], &notOwnedErr))
+				assertions.Equal("someone-else", notOwnedErr.Owner)
+				assertions.Zero(httpmock.GetTotalCallCount())
+			},
+		},
 		{
 			name: "failure getting lists",
 			fields: fields{
@@ -921,7 +1286,7 @@ func TestTraktClient_ListsGet(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			lists, err := c.ListsGet(tt.args.idsMeta)
+			lists, err := c.ListsGet(context.Background(), tt.args.idsMeta)
 			tt.assertions(assert.New(t), lists, err)
 		})
 	}
@@ -934,13 +1299,16 @@ func TestTraktClient_ListAdd(t *testing.T) {
 	type args struct {
 		listID   string
 		listName string
+		privacy  string
+		sortBy   string
+		sortHow  string
 	}
 	tests := []struct {
 		name         string
 		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, error)
+		assertions   func(*assert.Assertions, *entities.TraktList, error)
 	}{
 		{
 			name: "successfully add list",
@@ -950,16 +1318,60 @@ func TestTraktClient_ListAdd(t *testing.T) {
 			args: args{
 				listID:   dummyListID,
 				listName: dummyListName,
+				privacy:  appconfig.ListPrivacyPrivate,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
 					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, ""),
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+					func(request *http.Request) (*http.Response, error) {
+						var body entities.TraktListAddBody
+						if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+						if body.Privacy != appconfig.ListPrivacyPrivate || body.SortBy != traktListSortByDefault || body.SortHow != traktListSortHowDefault {
+							return httpmock.NewStringResponse(http.StatusUnprocessableEntity, ""), nil
+						}
+						return httpmock.NewStringResponse(http.StatusCreated, `{"name":"`+dummyListName+`","ids":{"trakt":1,"slug":"`+dummyListID+`"}}`), nil
+					},
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
+				assertions.NoError(err)
+				assertions.Equal(dummyListID, list.IDMeta.Slug)
+			},
+		},
+		{
+			name: "successfully add list with sort settings",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				listID:   dummyListID,
+				listName: dummyListName,
+				privacy:  appconfig.ListPrivacyPrivate,
+				sortBy:   "released",
+				sortHow:  appconfig.ListSortHowDesc,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, ""),
+					func(request *http.Request) (*http.Response, error) {
+						var body entities.TraktListAddBody
+						if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+						if body.SortBy != "released" || body.SortHow != appconfig.ListSortHowDesc {
+							return httpmock.NewStringResponse(http.StatusUnprocessableEntity, ""), nil
+						}
+						return httpmock.NewStringResponse(http.StatusCreated, `{"name":"`+dummyListName+`","ids":{"trakt":1,"slug":"`+dummyListID+`"}}`), nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
 				assertions.NoError(err)
+				assertions.Equal(dummyListID, list.IDMeta.Slug)
 			},
 		},
 		{
@@ -970,6 +1382,7 @@ func TestTraktClient_ListAdd(t *testing.T) {
 			args: args{
 				listID:   dummyListID,
 				listName: dummyListName,
+				privacy:  appconfig.ListPrivacyPrivate,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
@@ -978,7 +1391,8 @@ func TestTraktClient_ListAdd(t *testing.T) {
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
+				assertions.Nil(list)
 				assertions.Error(err)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
@@ -992,13 +1406,13 @@ func TestTraktClient_ListAdd(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.ListAdd(tt.args.listID, tt.args.listName)
-			tt.assertions(assert.New(t), err)
+			list, err := c.ListAdd(context.Background(), tt.args.listID, tt.args.listName, tt.args.privacy, tt.args.sortBy, tt.args.sortHow)
+			tt.assertions(assert.New(t), list, err)
 		})
 	}
 }
 
-func TestTraktClient_ListRemove(t *testing.T) {
+func TestTraktClient_ListSummaryGet(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
@@ -1010,10 +1424,10 @@ func TestTraktClient_ListRemove(t *testing.T) {
 		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, error)
+		assertions   func(*assert.Assertions, *entities.TraktList, error)
 	}{
 		{
-			name: "successfully remove list",
+			name: "successfully get list summary",
 			fields: fields{
 				config: dummyConfig,
 			},
@@ -1022,17 +1436,26 @@ func TestTraktClient_ListRemove(t *testing.T) {
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodDelete,
+					http.MethodGet,
 					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
-					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
+						"name":    dummyListName,
+						"privacy": appconfig.ListPrivacyFriends,
+						"ids": map[string]any{
+							"slug": dummyListID,
+						},
+					}),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
 				assertions.NoError(err)
+				assertions.Equal(dummyListName, *list.Name)
+				assertions.Equal(appconfig.ListPrivacyFriends, list.Privacy)
+				assertions.Equal(dummyListID, list.IDMeta.Slug)
 			},
 		},
 		{
-			name: "failure removing list",
+			name: "handle list not found",
 			fields: fields{
 				config: dummyConfig,
 			},
@@ -1041,16 +1464,15 @@ func TestTraktClient_ListRemove(t *testing.T) {
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodDelete,
+					http.MethodGet,
 					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
-					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+					httpmock.NewJsonResponderOrPanic(http.StatusNotFound, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				var apiError *ApiError
-				assertions.True(errors.As(err, &apiError))
-				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			assertions: func(assertions *assert.Assertions, list *entities.TraktList, err error) {
+				assertions.Nil(list)
+				var notFoundError *TraktListNotFoundError
+				assertions.True(errors.As(err, &notFoundError))
 			},
 		},
 	}
@@ -1060,55 +1482,97 @@ func TestTraktClient_ListRemove(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.ListRemove(tt.args.listID)
-			tt.assertions(assert.New(t), err)
+			list, err := c.ListSummaryGet(context.Background(), tt.args.listID)
+			tt.assertions(assert.New(t), list, err)
 		})
 	}
 }
 
-func TestTraktClient_RatingsGet(t *testing.T) {
+func TestTraktClient_ListUpdate(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
+	type args struct {
+		listID string
+		body   entities.TraktListUpdateBody
+	}
 	tests := []struct {
 		name         string
 		fields       fields
+		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, entities.TraktItems, error)
+		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully get ratings",
+			name: "successfully update list",
 			fields: fields{
 				config: dummyConfig,
 			},
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseAPI+traktPathRatings,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, httpmock.File("testdata/trakt_ratings.json")),
+			args: args{
+				listID: dummyListID,
+				body: entities.TraktListUpdateBody{
+					Privacy: stringPointer(appconfig.ListPrivacyPublic),
+				},
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPut,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
-				assertions.NotNil(ratings)
+			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.NoError(err)
-				assertions.Equal(3, len(ratings))
 			},
 		},
 		{
-			name: "failure getting ratings",
+			name: "successfully update list name and description",
 			fields: fields{
 				config: dummyConfig,
 			},
+			args: args{
+				listID: dummyListID,
+				body: entities.TraktListUpdateBody{
+					Name:        stringPointer("Favourites"),
+					Description: stringPointer("Movies I keep coming back to"),
+				},
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseAPI+traktPathRatings,
+					http.MethodPut,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
+					func(request *http.Request) (*http.Response, error) {
+						var body map[string]any
+						if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+						if body["name"] != "Favourites" || body["description"] != "Movies I keep coming back to" || body["privacy"] != nil {
+							return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+						}
+						return httpmock.NewStringResponse(http.StatusOK, ""), nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure updating list",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				listID: dummyListID,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPut,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
-				assertions.Nil(ratings)
-				assertions.Error(err)
+			assertions: func(assertions *assert.Assertions, err error) {
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
@@ -1121,18 +1585,18 @@ func TestTraktClient_RatingsGet(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			ratings, err := c.RatingsGet()
-			tt.assertions(assert.New(t), ratings, err)
+			err := c.ListUpdate(context.Background(), tt.args.listID, tt.args.body)
+			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_RatingsAdd(t *testing.T) {
+func TestTraktClient_ListRemove(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
 	type args struct {
-		items entities.TraktItems
+		listID string
 	}
 	tests := []struct {
 		name         string
@@ -1142,18 +1606,18 @@ func TestTraktClient_RatingsAdd(t *testing.T) {
 		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully add ratings",
+			name: "successfully remove list",
 			fields: fields{
 				config: dummyConfig,
 			},
 			args: args{
-				items: dummyItems,
+				listID: dummyListID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathRatings,
-					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
+					http.MethodDelete,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
+					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
@@ -1161,17 +1625,17 @@ func TestTraktClient_RatingsAdd(t *testing.T) {
 			},
 		},
 		{
-			name: "failure adding ratings",
+			name: "failure removing list",
 			fields: fields{
 				config: dummyConfig,
 			},
 			args: args{
-				items: dummyItems,
+				listID: dummyListID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathRatings,
+					http.MethodDelete,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserList, dummyUsername, dummyListID),
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
@@ -1182,26 +1646,6 @@ func TestTraktClient_RatingsAdd(t *testing.T) {
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
-		{
-			name: "failure decoding trakt response",
-			fields: fields{
-				config: dummyConfig,
-			},
-			args: args{
-				items: dummyItems,
-			},
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathRatings,
-					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
-				)
-			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure decoding reader")
-			},
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1209,18 +1653,19 @@ func TestTraktClient_RatingsAdd(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.RatingsAdd(tt.args.items)
+			err := c.ListRemove(context.Background(), tt.args.listID)
 			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_RatingsRemove(t *testing.T) {
+func TestTraktClient_ListLike(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
 	type args struct {
-		items entities.TraktItems
+		owner  string
+		listID string
 	}
 	tests := []struct {
 		name         string
@@ -1230,17 +1675,18 @@ func TestTraktClient_RatingsRemove(t *testing.T) {
 		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully remove ratings",
+			name: "successfully like list",
 			fields: fields{
 				config: dummyConfig,
 			},
 			args: args{
-				items: dummyItems,
+				owner:  "someone-else",
+				listID: dummyListID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathRatingsRemove,
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserListLike, "someone-else", dummyListID),
 					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
 				)
 			},
@@ -1249,45 +1695,25 @@ func TestTraktClient_RatingsRemove(t *testing.T) {
 			},
 		},
 		{
-			name: "failure removing ratings",
-			fields: fields{
-				config: dummyConfig,
-			},
-			args: args{
-				items: dummyItems,
-			},
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathRatingsRemove,
-					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
-				)
-			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				var apiError *ApiError
-				assertions.True(errors.As(err, &apiError))
-				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
-			},
-		},
-		{
-			name: "failure decoding trakt response",
+			name: "failure liking missing list",
 			fields: fields{
 				config: dummyConfig,
 			},
 			args: args{
-				items: dummyItems,
+				owner:  "someone-else",
+				listID: dummyListID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathRatingsRemove,
-					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
+					fmt.Sprintf(traktPathBaseAPI+traktPathUserListLike, "someone-else", dummyListID),
+					httpmock.NewJsonResponderOrPanic(http.StatusNotFound, nil),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure decoding reader")
+				var notFoundErr *TraktListNotFoundError
+				assertions.True(errors.As(err, &notFoundErr))
+				assertions.Equal(dummyListID, notFoundErr.Slug)
 			},
 		},
 	}
@@ -1297,67 +1723,107 @@ func TestTraktClient_RatingsRemove(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.RatingsRemove(tt.args.items)
+			err := c.ListLike(context.Background(), tt.args.owner, tt.args.listID)
 			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_HistoryGet(t *testing.T) {
+func TestTraktClient_RatingsGet(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
-	type args struct {
-		itemType string
-		itemID   string
-	}
 	tests := []struct {
 		name         string
 		fields       fields
-		args         args
 		requirements func()
 		assertions   func(*assert.Assertions, entities.TraktItems, error)
 	}{
 		{
-			name: "successfully get history",
+			name: "successfully get ratings",
 			fields: fields{
 				config: dummyConfig,
 			},
-			args: args{
-				itemType: entities.TraktItemTypeShow,
-				itemID:   dummyItemID,
-			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodGet,
-					fmt.Sprintf(traktPathBaseAPI+traktPathHistoryGet, entities.TraktItemTypeShow+"s", dummyItemID, "1000"),
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, httpmock.File("testdata/trakt_history.json")),
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, httpmock.File("testdata/trakt_ratings.json")),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, history entities.TraktItems, err error) {
-				assertions.NotNil(history)
+			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
+				assertions.NotNil(ratings)
 				assertions.NoError(err)
-				assertions.Equal(1, len(history))
+				assertions.Equal(3, len(ratings))
 			},
 		},
 		{
-			name: "failure getting history",
+			name: "successfully get ratings across pages",
 			fields: fields{
 				config: dummyConfig,
 			},
-			args: args{
-				itemType: entities.TraktItemTypeShow,
-				itemID:   dummyItemID,
+			requirements: func() {
+				for page, body := range []string{
+					`[{"type":"movie","rating":8,"movie":{"ids":{"imdb":"tt5013056"}}}]`,
+					`[{"type":"show","rating":9,"show":{"ids":{"imdb":"tt7366338"}}}]`,
+				} {
+					httpmock.RegisterResponderWithQuery(
+						http.MethodGet,
+						traktPathBaseAPI+traktPathRatings,
+						fmt.Sprintf("page=%d&limit=%d", page+1, traktRatingsPageLimit),
+						func(body string) httpmock.Responder {
+							return func(req *http.Request) (*http.Response, error) {
+								response := httpmock.NewStringResponse(http.StatusOK, body)
+								response.Header.Set(traktHeaderKeyPageCount, "2")
+								return response, nil
+							}
+						}(body),
+					)
+				}
+			},
+			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
+				assertions.NoError(err)
+				assertions.Equal(2, len(ratings))
+				assertions.Equal("tt5013056", ratings[0].Movie.IDMeta.IMDb)
+				assertions.Equal("tt7366338", ratings[1].Show.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "failure parsing ratings page count",
+			fields: fields{
+				config: dummyConfig,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodGet,
-					fmt.Sprintf(traktPathBaseAPI+traktPathHistoryGet, entities.TraktItemTypeShow+"s", dummyItemID, "1000"),
+					traktPathBaseAPI+traktPathRatings,
+					func(req *http.Request) (*http.Response, error) {
+						response := httpmock.NewStringResponse(http.StatusOK, `[]`)
+						response.Header.Set(traktHeaderKeyPageCount, "many")
+						return response, nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
+				assertions.Nil(ratings)
+				assertions.Error(err)
+				assertions.Contains(err.Error(), traktHeaderKeyPageCount)
+			},
+		},
+		{
+			name: "failure getting ratings",
+			fields: fields{
+				config: dummyConfig,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathRatings,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, history entities.TraktItems, err error) {
-				assertions.Nil(history)
+			assertions: func(assertions *assert.Assertions, ratings entities.TraktItems, err error) {
+				assertions.Nil(ratings)
 				assertions.Error(err)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
@@ -1371,13 +1837,13 @@ func TestTraktClient_HistoryGet(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			history, err := c.HistoryGet(tt.args.itemType, tt.args.itemID)
-			tt.assertions(assert.New(t), history, err)
+			ratings, err := c.RatingsGet(context.Background())
+			tt.assertions(assert.New(t), ratings, err)
 		})
 	}
 }
 
-func TestTraktClient_HistoryAdd(t *testing.T) {
+func TestTraktClient_RatingsAdd(t *testing.T) {
 	type fields struct {
 		config traktConfig
 	}
@@ -1392,7 +1858,7 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully add history items",
+			name: "successfully add ratings",
 			fields: fields{
 				config: dummyConfig,
 			},
@@ -1402,7 +1868,7 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistory,
+					traktPathBaseAPI+traktPathRatings,
 					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
 				)
 			},
@@ -1411,7 +1877,7 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 			},
 		},
 		{
-			name: "failure adding history items",
+			name: "failure adding ratings",
 			fields: fields{
 				config: dummyConfig,
 			},
@@ -1421,7 +1887,7 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistory,
+					traktPathBaseAPI+traktPathRatings,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
@@ -1433,9 +1899,16 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 			},
 		},
 		{
-			name: "failure decoding trakt response",
+			name: "successfully add ratings in batches",
 			fields: fields{
-				config: dummyConfig,
+				config: func() traktConfig {
+					config := dummyConfig
+					config.BatchSize = func() *int {
+						size := 1
+						return &size
+					}()
+					return config
+				}(),
 			},
 			args: args{
 				items: dummyItems,
@@ -1443,46 +1916,26 @@ func TestTraktClient_HistoryAdd(t *testing.T) {
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistory,
-					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure decoding reader")
+				assertions.NoError(err)
+				assertions.Equal(len(dummyItems), httpmock.GetTotalCallCount())
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			httpmock.Activate()
-			defer httpmock.DeactivateAndReset()
-			tt.requirements()
-			c := buildTestTraktClient(tt.fields.config)
-			err := c.HistoryAdd(tt.args.items)
-			tt.assertions(assert.New(t), err)
-		})
-	}
-}
-
-func TestTraktClient_HistoryRemove(t *testing.T) {
-	type fields struct {
-		config traktConfig
-	}
-	type args struct {
-		items entities.TraktItems
-	}
-	tests := []struct {
-		name         string
-		fields       fields
-		args         args
-		requirements func()
-		assertions   func(*assert.Assertions, error)
-	}{
 		{
-			name: "successfully remove history items",
+			name: "continue adding ratings after a failing batch",
 			fields: fields{
-				config: dummyConfig,
+				config: func() traktConfig {
+					config := dummyConfig
+					config.BatchSize = func() *int {
+						size := 1
+						return &size
+					}()
+					return config
+				}(),
 			},
 			args: args{
 				items: dummyItems,
@@ -1490,16 +1943,19 @@ func TestTraktClient_HistoryRemove(t *testing.T) {
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistoryRemove,
-					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil).Once().Then(
+						httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
+					),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.NoError(err)
+				assertions.ErrorContains(err, fmt.Sprintf("failure syncing batch 1/%d", len(dummyItems)))
+				assertions.Equal(len(dummyItems), httpmock.GetTotalCallCount())
 			},
 		},
 		{
-			name: "failure removing history items",
+			name: "failure decoding trakt response",
 			fields: fields{
 				config: dummyConfig,
 			},
@@ -1509,35 +1965,93 @@ func TestTraktClient_HistoryRemove(t *testing.T) {
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistoryRemove,
-					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				var apiError *ApiError
-				assertions.True(errors.As(err, &apiError))
-				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+				assertions.Contains(err.Error(), "failure decoding reader")
 			},
 		},
 		{
-			name: "failure decoding trakt response",
+			name: "successfully add ratings not found by imdb id after matching them by title",
 			fields: fields{
-				config: dummyConfig,
+				config: func() traktConfig {
+					config := dummyConfig
+					config.TitleMatch = stringPointer(appconfig.TitleMatchStrict)
+					return config
+				}(),
 			},
 			args: args{
-				items: dummyItems,
+				items: dummyTitledItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathHistoryRemove,
-					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewStringResponder(http.StatusCreated, `{"not_found":{"movies":[{"ids":{"imdb":"tt5013056"}}]}}`).Once().Then(
+						httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
+					),
 				)
-			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure decoding reader")
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+"/search/movie",
+					httpmock.NewStringResponder(http.StatusOK, `[{"type":"movie","movie":{"title":"Dunkirk","year":2017,"ids":{"trakt":1388,"slug":"dunkirk-2017"}}}]`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+				assertions.Equal(3, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "skip ratings matching multiple titles",
+			fields: fields{
+				config: func() traktConfig {
+					config := dummyConfig
+					config.TitleMatch = stringPointer(appconfig.TitleMatchLoose)
+					return config
+				}(),
+			},
+			args: args{
+				items: dummyTitledItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewStringResponder(http.StatusCreated, `{"not_found":{"movies":[{"ids":{"imdb":"tt5013056"}}]}}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+"/search/movie",
+					httpmock.NewStringResponder(http.StatusOK, `[{"type":"movie","movie":{"title":"Dunkirk","year":2017,"ids":{"trakt":1388}}},{"type":"movie","movie":{"title":"Dunkirk","year":2016,"ids":{"trakt":1389}}}]`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+				assertions.Equal(2, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "skip matching ratings by title by default",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyTitledItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathRatings,
+					httpmock.NewStringResponder(http.StatusCreated, `{"not_found":{"movies":[{"ids":{"imdb":"tt5013056"}}]}}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+				assertions.Equal(1, httpmock.GetTotalCallCount())
 			},
 		},
 	}
@@ -1547,102 +2061,155 @@ func TestTraktClient_HistoryRemove(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(tt.fields.config)
-			err := c.HistoryRemove(tt.args.items)
+			err := c.RatingsAdd(context.Background(), tt.args.items)
 			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_BrowseSignIn(t *testing.T) {
+func TestTraktClient_RatingsRemove(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	type args struct {
+		items entities.TraktItems
+	}
 	tests := []struct {
 		name         string
+		fields       fields
+		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, *string, error)
+		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully browse sign in",
+			name: "successfully remove ratings",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+					http.MethodPost,
+					traktPathBaseAPI+traktPathRatingsRemove,
+					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.NoError(err)
-				assertions.NotNil(token)
-				assertions.Equal(dummyAuthenticityToken, *token)
 			},
 		},
 		{
-			name: "failure getting authenticity token",
+			name: "failure removing ratings",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
+					http.MethodPost,
+					traktPathBaseAPI+traktPathRatingsRemove,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				assertions.Nil(token)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
+		{
+			name: "failure decoding trakt response",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathRatingsRemove,
+					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure decoding reader")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			token, err := c.BrowseSignIn()
-			tt.assertions(assert.New(t), token, err)
+			c := buildTestTraktClient(tt.fields.config)
+			err := c.RatingsRemove(context.Background(), tt.args.items)
+			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_SignIn(t *testing.T) {
+func TestTraktClient_HistoryGet(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
 	type args struct {
-		authenticityToken string
+		itemType string
+		itemID   string
 	}
 	tests := []struct {
 		name         string
+		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, error)
+		assertions   func(*assert.Assertions, entities.TraktItems, error)
 	}{
 		{
-			name: "successfully sign in",
+			name: "successfully get history",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				authenticityToken: dummyAuthenticityToken,
+				itemType: entities.TraktItemTypeShow,
+				itemID:   dummyItemID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+					http.MethodGet,
+					fmt.Sprintf(traktPathBaseAPI+traktPathHistoryGet, entities.TraktItemTypeShow+"s", dummyItemID, "1000"),
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, httpmock.File("testdata/trakt_history.json")),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, history entities.TraktItems, err error) {
+				assertions.NotNil(history)
 				assertions.NoError(err)
+				assertions.Equal(1, len(history))
 			},
 		},
 		{
-			name: "failure signing in",
+			name: "failure getting history",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				authenticityToken: dummyAuthenticityToken,
+				itemType: entities.TraktItemTypeShow,
+				itemID:   dummyItemID,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
+					http.MethodGet,
+					fmt.Sprintf(traktPathBaseAPI+traktPathHistoryGet, entities.TraktItemTypeShow+"s", dummyItemID, "1000"),
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, history entities.TraktItems, err error) {
+				assertions.Nil(history)
 				assertions.Error(err)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
@@ -1655,46 +2222,69 @@ func TestTraktClient_SignIn(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			err := c.SignIn(tt.args.authenticityToken)
-			tt.assertions(assert.New(t), err)
+			c := buildTestTraktClient(tt.fields.config)
+			history, err := c.HistoryGet(context.Background(), tt.args.itemType, tt.args.itemID)
+			tt.assertions(assert.New(t), history, err)
 		})
 	}
 }
 
-func TestTraktClient_BrowseActivate(t *testing.T) {
+func TestTraktClient_WatchedGet(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
 	tests := []struct {
 		name         string
+		fields       fields
 		requirements func()
-		assertions   func(*assert.Assertions, *string, error)
+		assertions   func(*assert.Assertions, entities.TraktItems, error)
 	}{
 		{
-			name: "successfully browse activate",
+			name: "successfully get watched movies and shows",
+			fields: fields{
+				config: dummyConfig,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodGet,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+					fmt.Sprintf(traktPathBaseAPI+traktPathWatched, "movies"),
+					httpmock.NewStringResponder(http.StatusOK, `[{"plays":1,"movie":{"title":"Dunkirk","year":2017,"ids":{"imdb":"tt5013056"}}}]`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf(traktPathBaseAPI+traktPathWatched, "shows"),
+					httpmock.NewStringResponder(http.StatusOK, `[{"plays":8,"show":{"title":"Chernobyl","year":2019,"ids":{"imdb":"tt7366338"}}}]`),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, watched entities.TraktItems, err error) {
 				assertions.NoError(err)
-				assertions.NotNil(token)
-				assertions.Equal(dummyAuthenticityToken, *token)
+				assertions.Equal(2, len(watched))
+				assertions.Equal(entities.TraktItemTypeMovie, watched[0].Type)
+				assertions.Equal("tt5013056", watched[0].Movie.IDMeta.IMDb)
+				assertions.Equal(entities.TraktItemTypeShow, watched[1].Type)
+				assertions.Equal("tt7366338", watched[1].Show.IDMeta.IMDb)
 			},
 		},
 		{
-			name: "failure browsing activate",
+			name: "failure getting watched shows",
+			fields: fields{
+				config: dummyConfig,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodGet,
-					traktPathBaseBrowser+traktPathActivate,
+					fmt.Sprintf(traktPathBaseAPI+traktPathWatched, "movies"),
+					httpmock.NewStringResponder(http.StatusOK, `[]`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					fmt.Sprintf(traktPathBaseAPI+traktPathWatched, "shows"),
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, watched entities.TraktItems, err error) {
+				assertions.Nil(watched)
 				assertions.Error(err)
-				assertions.Nil(token)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
@@ -1706,59 +2296,79 @@ func TestTraktClient_BrowseActivate(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			token, err := c.BrowseActivate()
-			tt.assertions(assert.New(t), token, err)
+			c := buildTestTraktClient(tt.fields.config)
+			watched, err := c.WatchedGet(context.Background())
+			tt.assertions(assert.New(t), watched, err)
 		})
 	}
 }
 
-func TestTraktClient_Activate(t *testing.T) {
+func TestTraktClient_HiddenGet(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
 	type args struct {
-		userCode          string
-		authenticityToken string
+		section string
 	}
 	tests := []struct {
 		name         string
+		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, *string, error)
+		assertions   func(*assert.Assertions, entities.TraktItems, error)
 	}{
 		{
-			name: "successfully activate",
-			args: args{
-				userCode:          dummyUserCode,
-				authenticityToken: dummyAuthenticityToken,
+			name: "successfully get hidden items across pages",
+			fields: fields{
+				config: dummyConfig,
 			},
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
-				)
+			args: args{
+				section: "recommendations",
+			},
+			requirements: func() {
+				for page, body := range []string{
+					`[{"hidden_at":"2024-03-05T21:30:00.000Z","type":"movie","movie":{"ids":{"imdb":"tt5013056"}}}]`,
+					`[{"hidden_at":"2024-03-06T21:30:00.000Z","type":"show","show":{"ids":{"imdb":"tt7366338"}}}]`,
+				} {
+					httpmock.RegisterResponderWithQuery(
+						http.MethodGet,
+						traktPathBaseAPI+"/users/hidden/recommendations",
+						fmt.Sprintf("page=%d&limit=%d", page+1, traktHiddenPageLimit),
+						func(body string) httpmock.Responder {
+							return func(req *http.Request) (*http.Response, error) {
+								response := httpmock.NewStringResponse(http.StatusOK, body)
+								response.Header.Set(traktHeaderKeyPageCount, "2")
+								return response, nil
+							}
+						}(body),
+					)
+				}
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, hidden entities.TraktItems, err error) {
 				assertions.NoError(err)
-				assertions.NotNil(token)
-				assertions.Equal(dummyAuthenticityToken, *token)
+				assertions.Equal(2, len(hidden))
+				assertions.Equal("tt5013056", hidden[0].Movie.IDMeta.IMDb)
+				assertions.Equal("tt7366338", hidden[1].Show.IDMeta.IMDb)
 			},
 		},
 		{
-			name: "failure activating",
+			name: "failure getting hidden items",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				userCode:          dummyUserCode,
-				authenticityToken: dummyAuthenticityToken,
+				section: "dropped",
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivate,
+					http.MethodGet,
+					traktPathBaseAPI+"/users/hidden/dropped",
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, token *string, err error) {
+			assertions: func(assertions *assert.Assertions, hidden entities.TraktItems, err error) {
+				assertions.Nil(hidden)
 				assertions.Error(err)
-				assertions.Nil(token)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
@@ -1770,33 +2380,40 @@ func TestTraktClient_Activate(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			token, err := c.Activate(tt.args.userCode, tt.args.authenticityToken)
-			tt.assertions(assert.New(t), token, err)
+			c := buildTestTraktClient(tt.fields.config)
+			hidden, err := c.HiddenGet(context.Background(), tt.args.section)
+			tt.assertions(assert.New(t), hidden, err)
 		})
 	}
 }
 
-func TestTraktClient_ActivateAuthorize(t *testing.T) {
+func TestTraktClient_HistoryAdd(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
 	type args struct {
-		authenticityToken string
+		items entities.TraktItems
 	}
 	tests := []struct {
 		name         string
+		fields       fields
 		args         args
 		requirements func()
 		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully activate authorize",
+			name: "successfully add history items",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				authenticityToken: dummyAuthenticityToken,
+				items: dummyItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
-					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
+					traktPathBaseAPI+traktPathHistory,
+					httpmock.NewJsonResponderOrPanic(http.StatusCreated, nil),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
@@ -1804,14 +2421,17 @@ func TestTraktClient_ActivateAuthorize(t *testing.T) {
 			},
 		},
 		{
-			name: "failure activating authorize",
-			args: args{
-				authenticityToken: dummyAuthenticityToken,
+			name: "failure adding history items",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
+					traktPathBaseAPI+traktPathHistory,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
@@ -1823,37 +2443,23 @@ func TestTraktClient_ActivateAuthorize(t *testing.T) {
 			},
 		},
 		{
-			name: "failure scraping username",
-			args: args{
-				authenticityToken: dummyAuthenticityToken,
-			},
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
-				)
-			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure scraping")
+			name: "failure decoding trakt response",
+			fields: fields{
+				config: dummyConfig,
 			},
-		},
-		{
-			name: "failure parsing scrape result to username",
 			args: args{
-				authenticityToken: dummyAuthenticityToken,
+				items: dummyItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
-					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="invalid"></a>`),
+					traktPathBaseAPI+traktPathHistory,
+					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure scraping")
+				assertions.Contains(err.Error(), "failure decoding reader")
 			},
 		},
 	}
@@ -1862,108 +2468,134 @@ func TestTraktClient_ActivateAuthorize(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			err := c.ActivateAuthorize(tt.args.authenticityToken)
+			c := buildTestTraktClient(tt.fields.config)
+			err := c.HistoryAdd(context.Background(), tt.args.items)
 			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_GetAccessToken(t *testing.T) {
+func TestTraktClient_HistoryRemove(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
 	type args struct {
-		deviceCode string
+		items entities.TraktItems
 	}
 	tests := []struct {
 		name         string
+		fields       fields
 		args         args
 		requirements func()
-		assertions   func(*assert.Assertions, *entities.TraktAuthTokensResponse, error)
+		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully get access token",
+			name: "successfully remove history items",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				deviceCode: dummyDeviceCode,
+				items: dummyItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthTokens,
-					httpmock.NewStringResponder(http.StatusOK, `{"access_token":"access-token-value"}`),
+					traktPathBaseAPI+traktPathHistoryRemove,
+					httpmock.NewJsonResponderOrPanic(http.StatusNoContent, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthTokensResponse, err error) {
+			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.NoError(err)
-				assertions.NotNil(response)
-				assertions.Equal("access-token-value", response.AccessToken)
 			},
 		},
 		{
-			name: "failure getting access token",
+			name: "failure removing history items",
+			fields: fields{
+				config: dummyConfig,
+			},
 			args: args{
-				deviceCode: dummyDeviceCode,
+				items: dummyItems,
 			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthTokens,
+					traktPathBaseAPI+traktPathHistoryRemove,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthTokensResponse, err error) {
+			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				assertions.Nil(response)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
+		{
+			name: "failure decoding trakt response",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				items: dummyItems,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathHistoryRemove,
+					httpmock.NewStringResponder(http.StatusCreated, "invalid"),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure decoding reader")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
-			c := buildTestTraktClient(dummyConfig)
-			response, err := c.GetAccessToken(tt.args.deviceCode)
-			tt.assertions(assert.New(t), response, err)
+			c := buildTestTraktClient(tt.fields.config)
+			err := c.HistoryRemove(context.Background(), tt.args.items)
+			tt.assertions(assert.New(t), err)
 		})
 	}
 }
 
-func TestTraktClient_GetAuthCodes(t *testing.T) {
+func TestTraktClient_BrowseSignIn(t *testing.T) {
 	tests := []struct {
 		name         string
 		requirements func()
-		assertions   func(*assert.Assertions, *entities.TraktAuthCodesResponse, error)
+		assertions   func(*assert.Assertions, *string, error)
 	}{
 		{
-			name: "successfully get auth codes",
+			name: "successfully browse sign in",
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthCodesResponse, err error) {
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
 				assertions.NoError(err)
-				assertions.NotNil(response)
-				assertions.Equal(dummyDeviceCode, response.DeviceCode)
-				assertions.Equal(dummyUserCode, response.UserCode)
+				assertions.NotNil(token)
+				assertions.Equal(dummyAuthenticityToken, *token)
 			},
 		},
 		{
-			name: "failure getting auth codes",
+			name: "failure getting authenticity token",
 			requirements: func() {
 				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthCodesResponse, err error) {
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
 				assertions.Error(err)
-				assertions.Nil(response)
+				assertions.Nil(token)
 				var apiError *ApiError
 				assertions.True(errors.As(err, &apiError))
 				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
@@ -1976,209 +2608,217 @@ func TestTraktClient_GetAuthCodes(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(dummyConfig)
-			response, err := c.GetAuthCodes()
-			tt.assertions(assert.New(t), response, err)
+			token, err := c.BrowseSignIn(context.Background())
+			tt.assertions(assert.New(t), token, err)
 		})
 	}
 }
 
-func TestTraktClient_Hydrate(t *testing.T) {
+func TestTraktClient_SignIn(t *testing.T) {
+	type args struct {
+		authenticityToken string
+	}
 	tests := []struct {
 		name         string
+		args         args
 		requirements func()
 		assertions   func(*assert.Assertions, error)
 	}{
 		{
-			name: "successfully hydrate",
+			name: "successfully sign in",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
+			},
 			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
 				httpmock.RegisterResponder(
 					http.MethodPost,
 					traktPathBaseBrowser+traktPathAuthSignIn,
 					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
 				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
-					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthTokens,
-					httpmock.NewStringResponder(http.StatusOK, `{"access_token":"access-token-value"}`),
-				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.NoError(err)
 			},
 		},
 		{
-			name: "failure getting auth codes",
-			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
-				)
-			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure generating auth codes")
+			name: "failure signing in",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
 			},
-		},
-		{
-			name: "failure browsing sign in",
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
 					traktPathBaseBrowser+traktPathAuthSignIn,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure simulating browse to the trakt sign in page")
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			err := c.SignIn(context.Background(), tt.args.authenticityToken)
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
+
+func TestTraktClient_BrowseActivate(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, *string, error)
+	}{
 		{
-			name: "failure signing in",
+			name: "successfully browse activate",
 			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
 				httpmock.RegisterResponder(
 					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
-				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure simulating trakt sign in form submission")
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(token)
+				assertions.Equal(dummyAuthenticityToken, *token)
 			},
 		},
 		{
 			name: "failure browsing activate",
 			requirements: func() {
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
-				)
 				httpmock.RegisterResponder(
 					http.MethodGet,
 					traktPathBaseBrowser+traktPathActivate,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure simulating browse to the trakt device activation page")
-			},
-		},
-		{
-			name: "failure activating",
+				assertions.Nil(token)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			token, err := c.BrowseActivate(context.Background())
+			tt.assertions(assert.New(t), token, err)
+		})
+	}
+}
+
+func TestTraktClient_Activate(t *testing.T) {
+	type args struct {
+		userCode          string
+		authenticityToken string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, *string, error)
+	}{
+		{
+			name: "successfully activate",
+			args: args{
+				userCode:          dummyUserCode,
+				authenticityToken: dummyAuthenticityToken,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
 					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
 				)
+			},
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(token)
+				assertions.Equal(dummyAuthenticityToken, *token)
+			},
+		},
+		{
+			name: "failure activating",
+			args: args{
+				userCode:          dummyUserCode,
+				authenticityToken: dummyAuthenticityToken,
+			},
+			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
 					traktPathBaseBrowser+traktPathActivate,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, token *string, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure simulating trakt device activation form submission")
+				assertions.Nil(token)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			token, err := c.Activate(context.Background(), tt.args.userCode, tt.args.authenticityToken)
+			tt.assertions(assert.New(t), token, err)
+		})
+	}
+}
+
+func TestTraktClient_ActivateAuthorize(t *testing.T) {
+	type args struct {
+		authenticityToken string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, error)
+	}{
 		{
-			name: "failure activating authorize",
+			name: "successfully activate authorize",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
+					traktPathBaseBrowser+traktPathActivateAuthorize,
+					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
 				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure activating authorize",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
+			},
+			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
 					traktPathBaseBrowser+traktPathActivateAuthorize,
@@ -2187,51 +2827,104 @@ func TestTraktClient_Hydrate(t *testing.T) {
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure simulating trakt api app allowlisting")
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
 		{
-			name: "failure getting access token",
+			name: "failure scraping username",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
+			},
 			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseAPI+traktPathAuthCodes,
-					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathAuthSignIn,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
-				)
-				httpmock.RegisterResponder(
-					http.MethodPost,
-					traktPathBaseBrowser+traktPathAuthSignIn,
+					traktPathBaseBrowser+traktPathActivateAuthorize,
 					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
 				)
-				httpmock.RegisterResponder(
-					http.MethodGet,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
-				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure scraping")
+			},
+		},
+		{
+			name: "failure parsing scrape result to username",
+			args: args{
+				authenticityToken: dummyAuthenticityToken,
+			},
+			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivate,
-					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
+					traktPathBaseBrowser+traktPathActivateAuthorize,
+					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="invalid"></a>`),
 				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure scraping")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			err := c.ActivateAuthorize(context.Background(), tt.args.authenticityToken)
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
+
+func TestTraktClient_GetAccessToken(t *testing.T) {
+	type args struct {
+		deviceCode string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, *entities.TraktAuthTokensResponse, error)
+	}{
+		{
+			name: "successfully get access token",
+			args: args{
+				deviceCode: dummyDeviceCode,
+			},
+			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
-					traktPathBaseBrowser+traktPathActivateAuthorize,
-					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
+					traktPathBaseAPI+traktPathAuthTokens,
+					httpmock.NewStringResponder(http.StatusOK, `{"access_token":"access-token-value"}`),
 				)
+			},
+			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthTokensResponse, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(response)
+				assertions.Equal("access-token-value", response.AccessToken)
+			},
+		},
+		{
+			name: "failure getting access token",
+			args: args{
+				deviceCode: dummyDeviceCode,
+			},
+			requirements: func() {
 				httpmock.RegisterResponder(
 					http.MethodPost,
 					traktPathBaseAPI+traktPathAuthTokens,
 					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
 				)
 			},
-			assertions: func(assertions *assert.Assertions, err error) {
+			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthTokensResponse, err error) {
 				assertions.Error(err)
-				assertions.Contains(err.Error(), "failure exchanging trakt device code for access token")
+				assertions.Nil(response)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
 			},
 		},
 	}
@@ -2241,8 +2934,748 @@ func TestTraktClient_Hydrate(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			tt.requirements()
 			c := buildTestTraktClient(dummyConfig)
-			err := c.Hydrate()
-			tt.assertions(assert.New(t), err)
+			response, err := c.GetAccessToken(context.Background(), tt.args.deviceCode)
+			tt.assertions(assert.New(t), response, err)
+		})
+	}
+}
+
+func TestTraktClient_GetAuthCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, *entities.TraktAuthCodesResponse, error)
+	}{
+		{
+			name: "successfully get auth codes",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthCodesResponse, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(response)
+				assertions.Equal(dummyDeviceCode, response.DeviceCode)
+				assertions.Equal(dummyUserCode, response.UserCode)
+			},
+		},
+		{
+			name: "failure getting auth codes",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, response *entities.TraktAuthCodesResponse, err error) {
+				assertions.Error(err)
+				assertions.Nil(response)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			response, err := c.GetAuthCodes(context.Background())
+			tt.assertions(assert.New(t), response, err)
+		})
+	}
+}
+
+func TestTraktClient_Hydrate(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully hydrate",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivateAuthorize,
+					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthTokens,
+					httpmock.NewStringResponder(http.StatusOK, `{"access_token":"access-token-value"}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure getting auth codes",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure generating auth codes")
+			},
+		},
+		{
+			name: "failure browsing sign in",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure simulating browse to the trakt sign in page")
+			},
+		},
+		{
+			name: "failure signing in",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure simulating trakt sign in form submission")
+			},
+		},
+		{
+			name: "failure browsing activate",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure simulating browse to the trakt device activation page")
+			},
+		},
+		{
+			name: "failure activating",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure simulating trakt device activation form submission")
+			},
+		},
+		{
+			name: "failure activating authorize",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivateAuthorize,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure simulating trakt api app allowlisting")
+			},
+		},
+		{
+			name: "failure getting access token",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthCodes,
+					httpmock.NewStringResponder(http.StatusOK, `{"device_code":"`+dummyDeviceCode+`","user_code":"`+dummyUserCode+`"}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="new_user"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathAuthSignIn,
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, nil),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><form class="form-signin"><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivate,
+					httpmock.NewStringResponder(http.StatusOK, `<div id="auth-form-wrapper"><div class="form-signin less-top"><div><form><input type="hidden" name="authenticity_token" value="authenticity-token-value"></form></div></div></div>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseBrowser+traktPathActivateAuthorize,
+					httpmock.NewStringResponder(http.StatusOK, `<a id="desktop-user-avatar" href="/users/cecobask"></a>`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthTokens,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure exchanging trakt device code for access token")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(dummyConfig)
+			err := c.Hydrate(context.Background())
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
+
+func TestTraktClient_CommentsGet(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		requirements func()
+		assertions   func(*assert.Assertions, entities.TraktItems, error)
+	}{
+		{
+			name: "successfully get comments",
+			fields: fields{
+				config: dummyConfig,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+fmt.Sprintf(traktPathUserComments, dummyUsername, "1000"),
+					httpmock.NewJsonResponderOrPanic(http.StatusOK, dummyItems),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, comments entities.TraktItems, err error) {
+				assertions.NoError(err)
+				assertions.Equal(len(dummyItems), len(comments))
+			},
+		},
+		{
+			name: "failure getting comments",
+			fields: fields{
+				config: dummyConfig,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+fmt.Sprintf(traktPathUserComments, dummyUsername, "1000"),
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, comments entities.TraktItems, err error) {
+				assertions.Nil(comments)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(tt.fields.config)
+			comments, err := c.CommentsGet(context.Background())
+			tt.assertions(assert.New(t), comments, err)
+		})
+	}
+}
+
+func TestTraktClient_LastActivitiesGet(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		requirements func()
+		assertions   func(*assert.Assertions, *entities.TraktLastActivities, error)
+	}{
+		{
+			name: "successfully get last activities",
+			fields: fields{
+				config: dummyConfig,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathLastActivities,
+					httpmock.NewStringResponder(http.StatusOK, `{"all":"2024-03-01T10:00:00.000Z","lists":{"updated_at":"2024-02-01T10:00:00.000Z"}}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, activities *entities.TraktLastActivities, err error) {
+				assertions.NoError(err)
+				assertions.NotNil(activities)
+				assertions.Equal(time.Date(2024, time.February, 1, 10, 0, 0, 0, time.UTC), activities.Lists.UpdatedAt)
+			},
+		},
+		{
+			name: "failure getting last activities",
+			fields: fields{
+				config: dummyConfig,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathLastActivities,
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, activities *entities.TraktLastActivities, err error) {
+				assertions.Nil(activities)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(tt.fields.config)
+			activities, err := c.LastActivitiesGet(context.Background())
+			tt.assertions(assert.New(t), activities, err)
+		})
+	}
+}
+
+func TestTraktClient_CommentAdd(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	type args struct {
+		item    entities.TraktItem
+		comment string
+		spoiler bool
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully add comment",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item:    dummyItems[0],
+				comment: "one of the best war movies ever made",
+				spoiler: true,
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathComments,
+					func(request *http.Request) (*http.Response, error) {
+						var body entities.TraktCommentBody
+						if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+						if body.Movie == nil || !body.Spoiler {
+							return httpmock.NewStringResponse(http.StatusUnprocessableEntity, ""), nil
+						}
+						return httpmock.NewStringResponse(http.StatusCreated, "{}"), nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "handle unsupported item type",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item: dummyItems[3],
+			},
+			requirements: func() {},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "unsupported trakt item type")
+			},
+		},
+		{
+			name: "failure adding comment",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item: dummyItems[1],
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathComments,
+					httpmock.NewJsonResponderOrPanic(http.StatusUnprocessableEntity, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnprocessableEntity, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(tt.fields.config)
+			err := c.CommentAdd(context.Background(), tt.args.item, tt.args.comment, tt.args.spoiler)
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
+
+func TestTraktClient_Scrobble(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	type args struct {
+		item entities.TraktItem
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully scrobble",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item: dummyItems[0],
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathScrobbleStop,
+					func(request *http.Request) (*http.Response, error) {
+						var body entities.TraktScrobbleBody
+						if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+						if body.Movie == nil || body.Progress != 100 {
+							return httpmock.NewStringResponse(http.StatusUnprocessableEntity, ""), nil
+						}
+						return httpmock.NewStringResponse(http.StatusCreated, "{}"), nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "handle unsupported item type",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item: dummyItems[1],
+			},
+			requirements: func() {},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "unsupported trakt item type")
+			},
+		},
+		{
+			name: "failure scrobbling",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				item: dummyItems[2],
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathScrobbleStop,
+					httpmock.NewJsonResponderOrPanic(http.StatusConflict, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusConflict, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(tt.fields.config)
+			err := c.Scrobble(context.Background(), tt.args.item)
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
+
+func TestTraktClient_Search(t *testing.T) {
+	type fields struct {
+		config traktConfig
+	}
+	type args struct {
+		itemType string
+		title    string
+		year     int
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		requirements func()
+		assertions   func(*assert.Assertions, entities.TraktItems, error)
+	}{
+		{
+			name: "successfully search by title and year",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				itemType: entities.TraktItemTypeMovie,
+				title:    "Dunkirk",
+				year:     2017,
+			},
+			requirements: func() {
+				httpmock.RegisterResponderWithQuery(
+					http.MethodGet,
+					traktPathBaseAPI+"/search/movie",
+					"query=Dunkirk&years=2016-2018",
+					httpmock.NewStringResponder(http.StatusOK, `[{"type":"movie","movie":{"title":"Dunkirk","year":2017,"ids":{"trakt":1388,"slug":"dunkirk-2017"}}}]`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, items entities.TraktItems, err error) {
+				assertions.NoError(err)
+				assertions.Equal(1, len(items))
+				assertions.Equal(1388, items[0].Movie.IDMeta.Trakt)
+			},
+		},
+		{
+			name: "failure searching",
+			fields: fields{
+				config: dummyConfig,
+			},
+			args: args{
+				itemType: entities.TraktItemTypeShow,
+				title:    "Breaking Bad",
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+"/search/show",
+					httpmock.NewJsonResponderOrPanic(http.StatusInternalServerError, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, items entities.TraktItems, err error) {
+				assertions.Nil(items)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestTraktClient(tt.fields.config)
+			items, err := c.Search(context.Background(), tt.args.itemType, tt.args.title, tt.args.year)
+			tt.assertions(assert.New(t), items, err)
+		})
+	}
+}
+
+func Test_mapTraktItemsToTraktBody(t *testing.T) {
+	type args struct {
+		file string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, entities.TraktListBody)
+	}{
+		{
+			name: "map mixed imdb ratings to the matching trakt entities",
+			args: args{
+				file: "testdata/imdb_ratings_mixed.csv",
+			},
+			assertions: func(assertions *assert.Assertions, body entities.TraktListBody) {
+				idsOf := func(specs entities.TraktItemSpecs) []string {
+					ids := make([]string, 0, len(specs))
+					for _, spec := range specs {
+						ids = append(ids, spec.IDMeta.IMDb)
+					}
+					return ids
+				}
+				assertions.Equal([]string{"tt5013056"}, idsOf(body.Movies))
+				assertions.Equal([]string{"tt0903747", "tt2356777", "tt14452776"}, idsOf(body.Shows))
+				assertions.Equal([]string{"tt2301451", "tt0959621"}, idsOf(body.Episodes))
+				assertions.Equal(10, *body.Episodes[0].Rating)
+				assertions.NotNil(body.Episodes[0].RatedAt)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(tt.args.file)
+			require.NoError(t, err)
+			defer file.Close()
+			ratings, err := readIMDbRatingsCSV(file)
+			require.NoError(t, err)
+			items := make(entities.TraktItems, 0, len(ratings))
+			for i := range ratings {
+				items = append(items, ratings[i].ToTraktItem())
+			}
+			tt.assertions(assert.New(t), mapTraktItemsToTraktBody(items))
 		})
 	}
 }
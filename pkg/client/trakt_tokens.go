@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+const (
+	traktTokensKeyringService = "imdb-trakt-sync"
+	traktTokensRefreshMargin  = time.Hour
+)
+
+var errKeyringItemNotFound = errors.New("keyring item not found")
+
+// TraktTokens are the trakt api tokens kept between runs, so that the simulated sign in only happens when they can't be refreshed
+// the email of the trakt account they were issued for is kept along, so that the tokens of one account are never used for another
+type TraktTokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Email        string    `json:"email"`
+}
+
+func newTraktTokens(response *entities.TraktAuthTokensResponse, now time.Time) *TraktTokens {
+	createdAt := now
+	if response.CreatedAt > 0 {
+		createdAt = time.Unix(response.CreatedAt, 0)
+	}
+	return &TraktTokens{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		ExpiresAt:    createdAt.Add(time.Duration(response.ExpiresIn) * time.Second),
+	}
+}
+
+// expiresSoon tells whether the access token should be refreshed before using it, the margin keeps it from expiring mid sync
+func (t *TraktTokens) expiresSoon(now time.Time) bool {
+	return !now.Before(t.ExpiresAt.Add(-traktTokensRefreshMargin))
+}
+
+type traktTokenStore interface {
+	load() (*TraktTokens, error)
+	save(tokens *TraktTokens) error
+	String() string
+}
+
+// traktTokensFile keeps the trakt tokens in a json file, a missing file means there are no tokens yet
+type traktTokensFile struct {
+	path string
+}
+
+func (f *traktTokensFile) load() (*TraktTokens, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var tokens TraktTokens
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+func (f *traktTokensFile) save(tokens *TraktTokens) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err = temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), f.path)
+}
+
+func (f *traktTokensFile) String() string {
+	return "file " + f.path
+}
+
+type keyring interface {
+	get(service, account string) (string, error)
+	set(service, account, secret string) error
+}
+
+// traktTokensKeyring keeps the trakt tokens in the os keyring, under the trakt account email
+type traktTokensKeyring struct {
+	keyring keyring
+	account string
+}
+
+func (k *traktTokensKeyring) load() (*TraktTokens, error) {
+	secret, err := k.keyring.get(traktTokensKeyringService, k.account)
+	if err != nil {
+		if errors.Is(err, errKeyringItemNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var tokens TraktTokens
+	if err = json.Unmarshal([]byte(secret), &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+func (k *traktTokensKeyring) save(tokens *TraktTokens) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return k.keyring.set(traktTokensKeyringService, k.account, string(data))
+}
+
+func (k *traktTokensKeyring) String() string {
+	return "os keyring"
+}
+
+func newOSKeyring() (keyring, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &macKeychain{}, nil
+	case "linux", "freebsd", "openbsd":
+		return &secretService{}, nil
+	default:
+		return nil, fmt.Errorf("the os keyring isn't supported on %s, use a tokens file instead", runtime.GOOS)
+	}
+}
+
+// macKeychain talks to the macos keychain through the security command
+type macKeychain struct{}
+
+func (m *macKeychain) get(service, account string) (string, error) {
+	output, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		// the security command exits with code 44 when the item isn't in the keychain
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+			return "", errKeyringItemNotFound
+		}
+		return "", fmt.Errorf("failure reading from the macos keychain: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// set runs the security command in interactive mode, which reads the command from stdin, so that the secret never shows up in the process arguments
+// the secret is hex encoded, which keeps it clear of the quoting rules of the interactive mode
+func (m *macKeychain) set(service, account, secret string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %q -a %q -X %s\n", service, account, hex.EncodeToString([]byte(secret))))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failure writing to the macos keychain: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// secretService talks to the freedesktop secret service, e.g. gnome keyring or kwallet, through the secret-tool command
+type secretService struct{}
+
+func (s *secretService) get(service, account string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		// secret-tool exits with code 1 and prints nothing when the item isn't in the keyring
+		if errors.As(err, &exitErr) && stdout.Len() == 0 && stderr.Len() == 0 {
+			return "", errKeyringItemNotFound
+		}
+		return "", fmt.Errorf("failure reading from the secret service: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (s *secretService) set(service, account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failure writing to the secret service: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
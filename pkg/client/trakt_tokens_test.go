@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyring struct {
+	secrets map[string]string
+}
+
+func (f *fakeKeyring) get(service, account string) (string, error) {
+	secret, ok := f.secrets[service+"/"+account]
+	if !ok {
+		return "", errKeyringItemNotFound
+	}
+	return secret, nil
+}
+
+func (f *fakeKeyring) set(service, account, secret string) error {
+	f.secrets[service+"/"+account] = secret
+	return nil
+}
+
+func TestTraktClient_authenticateWithStoredTokens(t *testing.T) {
+	refreshedTokensResponse := `{"access_token":"refreshed-access-token","refresh_token":"refreshed-refresh-token","expires_in":7776000,"created_at":` + strconv.FormatInt(dummyNow.Unix(), 10) + `}`
+	userSettingsResponse := `{"user":{"username":"Cecobask","ids":{"slug":"cecobask"}}}`
+	tests := []struct {
+		name         string
+		storedTokens *TraktTokens
+		requirements func()
+		assertions   func(*assert.Assertions, bool, error, *TraktClient, *TraktTokens)
+	}{
+		{
+			name: "successfully authenticate with valid stored tokens",
+			storedTokens: &TraktTokens{
+				AccessToken:  "stored-access-token",
+				RefreshToken: "stored-refresh-token",
+				ExpiresAt:    dummyNow.Add(30 * 24 * time.Hour),
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathUserSettings,
+					httpmock.NewStringResponder(http.StatusOK, userSettingsResponse),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.True(authenticated)
+				assertions.Equal("stored-access-token", tc.config.accessToken)
+				assertions.Equal("cecobask", tc.config.username)
+				assertions.Equal("stored-access-token", stored.AccessToken)
+				assertions.Zero(httpmock.GetCallCountInfo()["POST "+traktPathBaseAPI+traktPathAuthTokensRefresh])
+			},
+		},
+		{
+			name: "successfully refresh expiring stored tokens and write them back",
+			storedTokens: &TraktTokens{
+				AccessToken:  "stored-access-token",
+				RefreshToken: "stored-refresh-token",
+				ExpiresAt:    dummyNow.Add(time.Minute),
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthTokensRefresh,
+					httpmock.NewStringResponder(http.StatusOK, refreshedTokensResponse),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathUserSettings,
+					httpmock.NewStringResponder(http.StatusOK, userSettingsResponse),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.True(authenticated)
+				assertions.Equal("refreshed-access-token", tc.config.accessToken)
+				assertions.Equal("refreshed-access-token", stored.AccessToken)
+				assertions.Equal("refreshed-refresh-token", stored.RefreshToken)
+				assertions.True(stored.ExpiresAt.Equal(dummyNow.Add(90 * 24 * time.Hour)))
+			},
+		},
+		{
+			name:         "fall back to signing in when there are no stored tokens",
+			requirements: func() {},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.False(authenticated)
+				assertions.Nil(stored)
+			},
+		},
+		{
+			name: "fall back to signing in when the stored tokens belong to another account",
+			storedTokens: &TraktTokens{
+				AccessToken:  "stored-access-token",
+				RefreshToken: "stored-refresh-token",
+				ExpiresAt:    dummyNow.Add(30 * 24 * time.Hour),
+				Email:        "other@domain.com",
+			},
+			requirements: func() {},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.False(authenticated)
+				assertions.Empty(tc.config.accessToken)
+				assertions.Zero(httpmock.GetCallCountInfo()["GET "+traktPathBaseAPI+traktPathUserSettings])
+			},
+		},
+		{
+			name: "fall back to signing in when refreshing the stored tokens fails",
+			storedTokens: &TraktTokens{
+				AccessToken:  "stored-access-token",
+				RefreshToken: "revoked-refresh-token",
+				ExpiresAt:    dummyNow.Add(-time.Hour),
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					traktPathBaseAPI+traktPathAuthTokensRefresh,
+					httpmock.NewJsonResponderOrPanic(http.StatusBadRequest, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.False(authenticated)
+				assertions.Equal("stored-access-token", stored.AccessToken)
+			},
+		},
+		{
+			name: "fall back to signing in when trakt rejects the stored access token",
+			storedTokens: &TraktTokens{
+				AccessToken:  "revoked-access-token",
+				RefreshToken: "stored-refresh-token",
+				ExpiresAt:    dummyNow.Add(30 * 24 * time.Hour),
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathUserSettings,
+					httpmock.NewJsonResponderOrPanic(http.StatusUnauthorized, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.NoError(err)
+				assertions.False(authenticated)
+				assertions.Empty(tc.config.accessToken)
+			},
+		},
+		{
+			name: "failure fetching user settings",
+			storedTokens: &TraktTokens{
+				AccessToken:  "stored-access-token",
+				RefreshToken: "stored-refresh-token",
+				ExpiresAt:    dummyNow.Add(30 * 24 * time.Hour),
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					traktPathBaseAPI+traktPathUserSettings,
+					httpmock.NewJsonResponderOrPanic(http.StatusNotFound, nil),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, authenticated bool, err error, tc *TraktClient, stored *TraktTokens) {
+				assertions.Error(err)
+				assertions.Contains(err.Error(), "failure fetching trakt user settings")
+				assertions.False(authenticated)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			store := &traktTokensFile{path: filepath.Join(t.TempDir(), "tokens.json")}
+			if tt.storedTokens != nil {
+				require.NoError(t, store.save(tt.storedTokens))
+			}
+			tc := buildTestTraktClient(traktConfig{Trakt: dummyAppConfigTrakt}).(*TraktClient)
+			tc.tokenStore = store
+			authenticated, err := tc.authenticateWithStoredTokens(context.Background())
+			stored, loadErr := store.load()
+			require.NoError(t, loadErr)
+			tt.assertions(assert.New(t), authenticated, err, tc, stored)
+		})
+	}
+}
+
+func TestTraktClient_Hydrate_storedTokens(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		traktPathBaseAPI+traktPathUserSettings,
+		httpmock.NewStringResponder(http.StatusOK, `{"user":{"username":"cecobask","ids":{"slug":"cecobask"}}}`),
+	)
+	store := &traktTokensKeyring{keyring: &fakeKeyring{secrets: make(map[string]string)}, account: "user@domain.com"}
+	require.NoError(t, store.save(&TraktTokens{
+		AccessToken:  "stored-access-token",
+		RefreshToken: "stored-refresh-token",
+		ExpiresAt:    dummyNow.Add(30 * 24 * time.Hour),
+	}))
+	tc := buildTestTraktClient(traktConfig{Trakt: dummyAppConfigTrakt}).(*TraktClient)
+	tc.tokenStore = store
+	err := tc.Hydrate(context.Background())
+	assertions := assert.New(t)
+	assertions.NoError(err)
+	assertions.Equal("stored-access-token", tc.config.accessToken)
+	assertions.Equal("cecobask", tc.config.username)
+	assertions.Zero(httpmock.GetCallCountInfo()["POST "+traktPathBaseAPI+traktPathAuthCodes])
+}
+
+func Test_traktTokensFile(t *testing.T) {
+	assertions := assert.New(t)
+	path := filepath.Join(t.TempDir(), "nested", "tokens.json")
+	store := &traktTokensFile{path: path}
+	tokens, err := store.load()
+	assertions.NoError(err)
+	assertions.Nil(tokens)
+	expected := &TraktTokens{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    dummyNow,
+	}
+	assertions.NoError(store.save(expected))
+	info, err := os.Stat(path)
+	assertions.NoError(err)
+	assertions.Equal(os.FileMode(0600), info.Mode().Perm())
+	tokens, err = store.load()
+	assertions.NoError(err)
+	assertions.Equal(expected.AccessToken, tokens.AccessToken)
+	assertions.Equal(expected.RefreshToken, tokens.RefreshToken)
+	assertions.True(expected.ExpiresAt.Equal(tokens.ExpiresAt))
+	assertions.NoError(os.WriteFile(path, []byte("not json"), 0600))
+	_, err = store.load()
+	assertions.Error(err)
+}
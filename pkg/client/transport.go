@@ -0,0 +1,154 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/version"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const redactedValue = "REDACTED"
+
+// the values of these headers are never written to the logs, as they carry credentials
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	traktHeaderKeyApiKey,
+}
+
+// newBaseTransport returns the transport sending the requests through the configured proxy, trusting the configured certificate authorities
+// nil is returned when neither is configured, so that the default transport is used, which honours the HTTP_PROXY and HTTPS_PROXY environment variables
+func newBaseTransport(conf appconfig.HTTP) (http.RoundTripper, error) {
+	if !conf.HasProxy() && !conf.HasCABundle() {
+		return nil, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.HasProxy() {
+		proxyURL, err := url.Parse(*conf.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if conf.HasCABundle() {
+		pem, err := os.ReadFile(*conf.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failure reading ca bundle %s: %w", *conf.CABundle, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failure reading ca bundle %s: no pem encoded certificates found", *conf.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return transport, nil
+}
+
+// headersTransport sets the user agent and the extra headers on every outbound http request
+type headersTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func newHeadersTransport(next http.RoundTripper, conf appconfig.HTTP, defaultUserAgent string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	userAgent := defaultUserAgent
+	if conf.UserAgent != nil && *conf.UserAgent != "" {
+		userAgent = *conf.UserAgent
+	}
+	return &headersTransport{
+		next:      next,
+		userAgent: userAgent,
+		headers:   conf.Headers,
+	}
+}
+
+func (t *headersTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.Header.Set("User-Agent", t.userAgent)
+	for key, value := range t.headers {
+		// the extra headers must not interfere with the ones the clients rely on, e.g. authorization
+		if request.Header.Get(key) == "" {
+			request.Header.Set(key, value)
+		}
+	}
+	return t.next.RoundTrip(request)
+}
+
+// userAgentDefault identifies the application and its version, as recommended by the trakt api
+func userAgentDefault() string {
+	return "imdb-trakt-sync/" + version.Get().Version
+}
+
+// loggingTransport logs every outbound http request and the response status at debug level
+type loggingTransport struct {
+	next       http.RoundTripper
+	logger     *slog.Logger
+	clientName string
+}
+
+func newLoggingTransport(next http.RoundTripper, logger *slog.Logger, clientName string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{
+		next:       next,
+		logger:     logger,
+		clientName: clientName,
+	}
+}
+
+func (t *loggingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return t.next.RoundTrip(request)
+	}
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	attrs := []any{
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.Redacted()),
+		slog.Any("headers", redactHeaders(request.Header)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		t.logger.DebugContext(ctx, "failed sending "+t.clientName+" http request", append(attrs, logger.Error(err))...)
+		return nil, err
+	}
+	attrs = append(attrs, slog.Int("status", response.StatusCode), slog.Any("responseHeaders", redactHeaders(response.Header)))
+	t.logger.DebugContext(ctx, "sent "+t.clientName+" http request", attrs...)
+	return response, nil
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		if slices.ContainsFunc(sensitiveHeaders, func(name string) bool { return strings.EqualFold(name, key) }) {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = strings.Join(values, ", ")
+	}
+	return redacted
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func Test_loggingTransport_RoundTrip(t *testing.T) {
+	type args struct {
+		level     slog.Level
+		roundTrip roundTripperFunc
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, *http.Response, error)
+	}{
+		{
+			name: "log request and response with redacted secrets",
+			args: args{
+				level: slog.LevelDebug,
+				roundTrip: func(*http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header: http.Header{
+							"Set-Cookie":   []string{"session=secret-session"},
+							"Content-Type": []string{"application/json"},
+						},
+					}, nil
+				},
+			},
+			assertions: func(assertions *assert.Assertions, logs string, response *http.Response, err error) {
+				assertions.NoError(err)
+				assertions.Equal(http.StatusOK, response.StatusCode)
+				assertions.Contains(logs, "sent trakt http request")
+				assertions.Contains(logs, "https://api.trakt.tv/sync/ratings")
+				assertions.Contains(logs, `"status":200`)
+				assertions.Contains(logs, "application/json")
+				assertions.Contains(logs, redactedValue)
+				assertions.NotContains(logs, "secret-token")
+				assertions.NotContains(logs, "secret-client-id")
+				assertions.NotContains(logs, "secret-cookie")
+				assertions.NotContains(logs, "secret-session")
+			},
+		},
+		{
+			name: "log request failure",
+			args: args{
+				level: slog.LevelDebug,
+				roundTrip: func(*http.Request) (*http.Response, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			assertions: func(assertions *assert.Assertions, logs string, response *http.Response, err error) {
+				assertions.Nil(response)
+				assertions.ErrorContains(err, "connection refused")
+				assertions.Contains(logs, "failed sending trakt http request")
+				assertions.Contains(logs, "connection refused")
+			},
+		},
+		{
+			name: "skip logging above debug level",
+			args: args{
+				level: slog.LevelInfo,
+				roundTrip: func(*http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+					}, nil
+				},
+			},
+			assertions: func(assertions *assert.Assertions, logs string, response *http.Response, err error) {
+				assertions.NoError(err)
+				assertions.Equal(http.StatusOK, response.StatusCode)
+				assertions.Empty(logs)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			transport := newLoggingTransport(tt.args.roundTrip, logger.NewLoggerWithLevel(buf, tt.args.level), clientNameTrakt)
+			request, err := http.NewRequest(http.MethodGet, "https://api.trakt.tv/sync/ratings", nil)
+			assert.NoError(t, err)
+			request.Header.Set(traktHeaderKeyAuthorization, "Bearer secret-token")
+			request.Header.Set(traktHeaderKeyApiKey, "secret-client-id")
+			request.Header.Set("Cookie", "at-main=secret-cookie")
+			response, err := transport.RoundTrip(request)
+			tt.assertions(assert.New(t), buf.String(), response, err)
+		})
+	}
+}
+
+func Test_newBaseTransport(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+	dir := t.TempDir()
+	caBundle := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw}), 0644))
+	invalidCABundle := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCABundle, []byte("not a certificate"), 0644))
+	proxy := "http://proxy.example.com:3128"
+	missingCABundle := filepath.Join(dir, "missing.pem")
+	tests := []struct {
+		name       string
+		conf       appconfig.HTTP
+		assertions func(*assert.Assertions, http.RoundTripper, error)
+	}{
+		{
+			name: "use the default transport when nothing is configured",
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.NoError(err)
+				assertions.Nil(transport)
+			},
+		},
+		{
+			name: "route requests through the configured proxy",
+			conf: appconfig.HTTP{
+				Proxy: &proxy,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.NoError(err)
+				request := httptest.NewRequest(http.MethodGet, "https://api.trakt.tv", http.NoBody)
+				proxyURL, err := transport.(*http.Transport).Proxy(request)
+				assertions.NoError(err)
+				assertions.Equal(proxy, proxyURL.String())
+			},
+		},
+		{
+			name: "trust the configured ca bundle",
+			conf: appconfig.HTTP{
+				CABundle: &caBundle,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.NoError(err)
+				response, err := (&http.Client{Transport: transport}).Get(testServer.URL)
+				assertions.NoError(err)
+				assertions.Equal(http.StatusOK, response.StatusCode)
+				response.Body.Close()
+			},
+		},
+		{
+			name: "fail on a missing ca bundle",
+			conf: appconfig.HTTP{
+				CABundle: &missingCABundle,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.ErrorIs(err, os.ErrNotExist)
+				assertions.Nil(transport)
+			},
+		},
+		{
+			name: "fail on a ca bundle without certificates",
+			conf: appconfig.HTTP{
+				CABundle: &invalidCABundle,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.ErrorContains(err, "no pem encoded certificates found")
+				assertions.Nil(transport)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := newBaseTransport(tt.conf)
+			tt.assertions(assert.New(t), transport, err)
+		})
+	}
+}
+
+func Test_headersTransport_RoundTrip(t *testing.T) {
+	type args struct {
+		conf appconfig.HTTP
+	}
+	userAgent := "my-agent/1.0"
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, *http.Request)
+	}{
+		{
+			name: "use default user agent",
+			assertions: func(assertions *assert.Assertions, request *http.Request) {
+				assertions.Equal("default-agent", request.Header.Get("User-Agent"))
+				assertions.Equal("Bearer token", request.Header.Get(traktHeaderKeyAuthorization))
+			},
+		},
+		{
+			name: "use configured user agent and extra headers",
+			args: args{
+				conf: appconfig.HTTP{
+					UserAgent: &userAgent,
+					Headers: map[string]string{
+						"X-Allowlist-Token":         "allowed",
+						traktHeaderKeyAuthorization: "Bearer overridden",
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, request *http.Request) {
+				assertions.Equal(userAgent, request.Header.Get("User-Agent"))
+				assertions.Equal("allowed", request.Header.Get("X-Allowlist-Token"))
+				assertions.Equal("Bearer token", request.Header.Get(traktHeaderKeyAuthorization))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *http.Request
+			next := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				sent = request
+				return &http.Response{
+					StatusCode: http.StatusOK,
+				}, nil
+			})
+			request, err := http.NewRequest(http.MethodGet, "https://api.trakt.tv/sync/ratings", nil)
+			assert.NoError(t, err)
+			request.Header.Set(traktHeaderKeyAuthorization, "Bearer token")
+			_, err = newHeadersTransport(next, tt.args.conf, "default-agent").RoundTrip(request)
+			assert.NoError(t, err)
+			assert.Empty(t, request.Header.Get("User-Agent"))
+			tt.assertions(assert.New(t), sent)
+		})
+	}
+}
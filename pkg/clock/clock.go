@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time, allowing time-sensitive behaviour to be tested deterministically
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake is a manually controlled clock, waiting on it advances its time instead of blocking
+type Fake struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now: now,
+	}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- f.Advance(d)
+	return ch
+}
+
+// Advance moves the clock forward by d and returns the new time
+func (f *Fake) Advance(d time.Duration) time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+	return f.now
+}
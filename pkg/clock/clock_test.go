@@ -0,0 +1,51 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		action     func(*Fake)
+		assertions func(*assert.Assertions, *Fake)
+	}{
+		{
+			name:   "return initial time",
+			action: func(*Fake) {},
+			assertions: func(assertions *assert.Assertions, f *Fake) {
+				assertions.Equal(start, f.Now())
+			},
+		},
+		{
+			name: "advance time",
+			action: func(f *Fake) {
+				f.Advance(time.Hour)
+			},
+			assertions: func(assertions *assert.Assertions, f *Fake) {
+				assertions.Equal(start.Add(time.Hour), f.Now())
+			},
+		},
+		{
+			name: "fire after without blocking",
+			action: func(f *Fake) {
+				<-f.After(time.Minute)
+				<-f.After(time.Minute)
+			},
+			assertions: func(assertions *assert.Assertions, f *Fake) {
+				assertions.Equal(start.Add(2*time.Minute), f.Now())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFake(start)
+			tt.action(f)
+			tt.assertions(assert.New(t), f)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression of the standard five fields: minute, hour, day of month, month and day of week
+// fields support wildcards, ranges, steps and lists, e.g. "*/15 6-22 * * 1,3,5"
+type Schedule struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+	// cron matches either day field when both are restricted, rather than both of them
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type field struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	fieldMinute     = field{name: "minute", min: 0, max: 59}
+	fieldHour       = field{name: "hour", min: 0, max: 23}
+	fieldDayOfMonth = field{name: "day of month", min: 1, max: 31}
+	fieldMonth      = field{name: "month", min: 1, max: 12}
+	fieldDayOfWeek  = field{name: "day of week", min: 0, max: 7}
+
+	macros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// searchLimit bounds the search for the next matching time, expressions such as "0 0 31 2 *" never match
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if expanded, ok := macros[strings.ToLower(expression)]; ok {
+		expression = expanded
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, found %d", expression, len(fields))
+	}
+	var (
+		schedule Schedule
+		err      error
+	)
+	if schedule.minutes, err = parseField(fields[0], fieldMinute); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = parseField(fields[1], fieldHour); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfMonth, err = parseField(fields[2], fieldDayOfMonth); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = parseField(fields[3], fieldMonth); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfWeek, err = parseField(fields[4], fieldDayOfWeek); err != nil {
+		return nil, err
+	}
+	// sunday is both 0 and 7
+	schedule.daysOfWeek[0] = schedule.daysOfWeek[0] || schedule.daysOfWeek[7]
+	schedule.anyDayOfMonth = strings.HasPrefix(fields[2], "*")
+	schedule.anyDayOfWeek = strings.HasPrefix(fields[4], "*")
+	return &schedule, nil
+}
+
+func parseField(value string, f field) ([]bool, error) {
+	matches := make([]bool, f.max+1)
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q in %s field %q", stepPart, f.name, value)
+			}
+		}
+		start, end := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(from, f); err != nil {
+				return nil, err
+			}
+			if end, err = parseValue(to, f); err != nil {
+				return nil, err
+			}
+			if start > end {
+				return nil, fmt.Errorf("invalid range %q in %s field %q", rangePart, f.name, value)
+			}
+		default:
+			var err error
+			if start, err = parseValue(rangePart, f); err != nil {
+				return nil, err
+			}
+			end = start
+			if hasStep {
+				end = f.max
+			}
+		}
+		for i := start; i <= end; i += step {
+			matches[i] = true
+		}
+	}
+	return matches, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < f.min || i > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be between %d and %d", value, f.name, f.min, f.max)
+	}
+	return i, nil
+}
+
+// Next returns the first time after the given time matching the schedule, in the location of the given time
+// the zero time is returned when the schedule never matches
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Add(searchLimit)
+	for t.Before(limit) {
+		if !s.months[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth, dayOfWeek := s.daysOfMonth[t.Day()], s.daysOfWeek[t.Weekday()]
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		assertions func(*assert.Assertions, *Schedule, error)
+	}{
+		{
+			name:       "parse wildcards, ranges, steps and lists",
+			expression: "*/15 6-22 1,15 * 1-5",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.NoError(err)
+				assertions.True(schedule.minutes[45])
+				assertions.False(schedule.minutes[50])
+				assertions.False(schedule.hours[23])
+				assertions.True(schedule.daysOfMonth[15])
+				assertions.False(schedule.daysOfWeek[0])
+			},
+		},
+		{
+			name:       "parse macros",
+			expression: "@daily",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.NoError(err)
+				assertions.True(schedule.minutes[0])
+				assertions.False(schedule.minutes[1])
+			},
+		},
+		{
+			name:       "treat 7 as sunday",
+			expression: "0 0 * * 7",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.NoError(err)
+				assertions.True(schedule.daysOfWeek[0])
+			},
+		},
+		{
+			name:       "fail on a wrong number of fields",
+			expression: "0 0 * *",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "must have 5 fields")
+			},
+		},
+		{
+			name:       "fail on out of range values",
+			expression: "60 * * * *",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "minute")
+			},
+		},
+		{
+			name:       "fail on invalid steps",
+			expression: "*/0 * * * *",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "invalid step")
+			},
+		},
+		{
+			name:       "fail on inverted ranges",
+			expression: "* 22-6 * * *",
+			assertions: func(assertions *assert.Assertions, schedule *Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "invalid range")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			tt.assertions(assert.New(t), schedule, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// monday
+	start := time.Date(2024, time.January, 1, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		expected   time.Time
+	}{
+		{
+			name:       "next step of the minute",
+			expression: "*/15 * * * *",
+			expected:   time.Date(2024, time.January, 1, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "next hour",
+			expression: "0 */6 * * *",
+			expected:   time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next day",
+			expression: "@daily",
+			expected:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next day of week",
+			expression: "30 8 * * 5",
+			expected:   time.Date(2024, time.January, 5, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "either day field when both are restricted",
+			expression: "0 0 15 * 3",
+			expected:   time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next month",
+			expression: "0 0 1 3 *",
+			expected:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "leap day",
+			expression: "0 0 29 2 *",
+			expected:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "never",
+			expression: "0 0 31 2 *",
+			expected:   time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			assertions := assert.New(t)
+			assertions.NoError(err)
+			assertions.Equal(tt.expected, schedule.Next(start))
+		})
+	}
+}
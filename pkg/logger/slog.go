@@ -1,21 +1,35 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 )
 
 const keyError = "error"
 
 func NewLogger(writer io.Writer) *slog.Logger {
+	return NewLoggerWithLevel(writer, slog.LevelInfo)
+}
+
+func NewLoggerWithLevel(writer io.Writer, level slog.Level) *slog.Logger {
 	opts := &slog.HandlerOptions{
 		AddSource: true,
-		Level:     slog.LevelInfo,
+		Level:     level,
 	}
 	handler := slog.NewJSONHandler(writer, opts)
 	return slog.New(handler)
 }
 
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return l, fmt.Errorf("failure parsing log level %s: %w", level, err)
+	}
+	return l, nil
+}
+
 func Error(err error) slog.Attr {
 	return slog.Any(keyError, err)
 }
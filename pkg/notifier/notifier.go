@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	PresetDiscord = "discord"
+	PresetGeneric = "generic"
+	PresetNtfy    = "ntfy"
+
+	StatusFailure        = "failure"
+	StatusPartialFailure = "partial-failure"
+	StatusSuccess        = "success"
+
+	discordContentLimit  = 2000
+	headerKeyContentType = "Content-Type"
+	headerKeyNtfyTitle   = "Title"
+	headerKeyNtfyTags    = "Tags"
+)
+
+type Notification struct {
+	RunID  string `json:"run_id,omitempty"`
+	Status string `json:"status"`
+	Stats  any    `json:"stats,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (n Notification) Text() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("imdb-trakt-sync finished with status: %s", n.Status))
+	if n.RunID != "" {
+		sb.WriteString(fmt.Sprintf("\nrun: %s", n.RunID))
+	}
+	if n.Stats != nil {
+		stats, err := json.Marshal(n.Stats)
+		if err == nil {
+			sb.WriteString(fmt.Sprintf("\nstats: %s", stats))
+		}
+	}
+	if n.Error != "" {
+		sb.WriteString(fmt.Sprintf("\nerror: %s", n.Error))
+	}
+	return sb.String()
+}
+
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+type WebhookNotifier struct {
+	client *http.Client
+	url    string
+	preset string
+}
+
+func NewWebhookNotifier(url, preset string) (Notifier, error) {
+	switch preset {
+	case "":
+		preset = PresetGeneric
+	case PresetGeneric, PresetDiscord, PresetNtfy:
+	default:
+		return nil, fmt.Errorf("unknown notifier preset %s", preset)
+	}
+	return &WebhookNotifier{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		url:    url,
+		preset: preset,
+	}, nil
+}
+
+func (n *WebhookNotifier) Notify(notification Notification) error {
+	request, err := n.buildRequest(notification)
+	if err != nil {
+		return fmt.Errorf("failure building %s notification request: %w", n.preset, err)
+	}
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failure sending %s notification: %w", n.preset, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s notification returned unexpected status code %d", n.preset, response.StatusCode)
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) buildRequest(notification Notification) (*http.Request, error) {
+	var (
+		body    io.Reader
+		headers = make(map[string]string)
+	)
+	switch n.preset {
+	case PresetDiscord:
+		content := notification.Text()
+		if len(content) > discordContentLimit {
+			content = content[:discordContentLimit]
+		}
+		data, err := json.Marshal(map[string]string{"content": content})
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		headers[headerKeyContentType] = "application/json"
+	case PresetNtfy:
+		body = strings.NewReader(notification.Text())
+		headers[headerKeyNtfyTitle] = "imdb-trakt-sync"
+		headers[headerKeyNtfyTags] = notification.Status
+	default:
+		data, err := json.Marshal(notification)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		headers[headerKeyContentType] = "application/json"
+	}
+	request, err := http.NewRequest(http.MethodPost, n.url, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	return request, nil
+}
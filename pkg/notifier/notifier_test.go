@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookNotifier(t *testing.T) {
+	type args struct {
+		preset string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, Notifier, error)
+	}{
+		{
+			name: "default to generic preset",
+			args: args{
+				preset: "",
+			},
+			assertions: func(assertions *assert.Assertions, n Notifier, err error) {
+				assertions.NoError(err)
+				assertions.Equal(PresetGeneric, n.(*WebhookNotifier).preset)
+			},
+		},
+		{
+			name: "handle unknown preset",
+			args: args{
+				preset: "invalid",
+			},
+			assertions: func(assertions *assert.Assertions, n Notifier, err error) {
+				assertions.Nil(n)
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewWebhookNotifier("http://localhost", tt.args.preset)
+			tt.assertions(assert.New(t), n, err)
+		})
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	type args struct {
+		preset       string
+		notification Notification
+	}
+	dummyNotification := Notification{
+		RunID:  "0123456789abcdef",
+		Status: StatusPartialFailure,
+		Stats: map[string]int{
+			"added": 1,
+		},
+		Error: "failure syncing ratings",
+	}
+	tests := []struct {
+		name         string
+		args         args
+		requirements func(*require.Assertions) *httptest.Server
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully notify generic webhook",
+			args: args{
+				preset:       PresetGeneric,
+				notification: dummyNotification,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(http.MethodPost, r.Method)
+					requirements.Equal("application/json", r.Header.Get(headerKeyContentType))
+					var body Notification
+					requirements.NoError(json.NewDecoder(r.Body).Decode(&body))
+					requirements.Equal("0123456789abcdef", body.RunID)
+					requirements.Equal(StatusPartialFailure, body.Status)
+					requirements.Equal("failure syncing ratings", body.Error)
+					w.WriteHeader(http.StatusOK)
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "successfully notify discord webhook",
+			args: args{
+				preset:       PresetDiscord,
+				notification: dummyNotification,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]string
+					requirements.NoError(json.NewDecoder(r.Body).Decode(&body))
+					requirements.Contains(body["content"], StatusPartialFailure)
+					requirements.Contains(body["content"], "run: 0123456789abcdef")
+					w.WriteHeader(http.StatusNoContent)
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "successfully notify ntfy topic",
+			args: args{
+				preset:       PresetNtfy,
+				notification: dummyNotification,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requirements.Equal(StatusPartialFailure, r.Header.Get(headerKeyNtfyTags))
+					body, err := io.ReadAll(r.Body)
+					requirements.NoError(err)
+					requirements.Contains(string(body), "failure syncing ratings")
+					w.WriteHeader(http.StatusOK)
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "handle unexpected status",
+			args: args{
+				preset:       PresetGeneric,
+				notification: dummyNotification,
+			},
+			requirements: func(requirements *require.Assertions) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Error(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := tt.requirements(require.New(t))
+			defer testServer.Close()
+			n, err := NewWebhookNotifier(testServer.URL, tt.args.preset)
+			require.NoError(t, err)
+			tt.assertions(assert.New(t), n.Notify(tt.args.notification))
+		})
+	}
+}